@@ -40,6 +40,35 @@ type LogicalVolumeStatus struct {
 	Code        codes.Code         `json:"code,omitempty"`
 	Message     string             `json:"message,omitempty"`
 	CurrentSize *resource.Quantity `json:"currentSize,omitempty"`
+
+	// 'ioLimits' shows the I/O limits applied to the pod using the logical volume.
+	// This field is populated only while the logical volume is published with I/O limits.
+	// +kubebuilder:validation:Optional
+	IOLimits *IOLimits `json:"ioLimits,omitempty"`
+
+	// 'ioLimitsMessage' shows why the I/O limits are not applied to the pod using the logical volume.
+	// The logical volume is published without the I/O limits in that case.
+	// +kubebuilder:validation:Optional
+	IOLimitsMessage string `json:"ioLimitsMessage,omitempty"`
+
+	// 'publishedTargets' shows the target paths where the logical volume is published on the node.
+	// +kubebuilder:validation:Optional
+	PublishedTargets []PublishedTarget `json:"publishedTargets,omitempty"`
+}
+
+// PublishedTarget defines a target path where a logical volume is published.
+type PublishedTarget struct {
+	Path     string `json:"path"`
+	ReadOnly bool   `json:"readOnly,omitempty"`
+}
+
+// IOLimits defines the I/O limits of a logical volume.
+// Zero means unlimited.
+type IOLimits struct {
+	ReadIOPS  uint64 `json:"readIOPS,omitempty"`
+	WriteIOPS uint64 `json:"writeIOPS,omitempty"`
+	ReadBPS   uint64 `json:"readBPS,omitempty"`
+	WriteBPS  uint64 `json:"writeBPS,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -9,6 +9,21 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOLimits) DeepCopyInto(out *IOLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOLimits.
+func (in *IOLimits) DeepCopy() *IOLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(IOLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogicalVolume) DeepCopyInto(out *LogicalVolume) {
 	*out = *in
@@ -92,6 +107,16 @@ func (in *LogicalVolumeStatus) DeepCopyInto(out *LogicalVolumeStatus) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.IOLimits != nil {
+		in, out := &in.IOLimits, &out.IOLimits
+		*out = new(IOLimits)
+		**out = **in
+	}
+	if in.PublishedTargets != nil {
+		in, out := &in.PublishedTargets, &out.PublishedTargets
+		*out = make([]PublishedTarget, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeStatus.
@@ -103,3 +128,18 @@ func (in *LogicalVolumeStatus) DeepCopy() *LogicalVolumeStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishedTarget) DeepCopyInto(out *PublishedTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishedTarget.
+func (in *PublishedTarget) DeepCopy() *PublishedTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishedTarget)
+	in.DeepCopyInto(out)
+	return out
+}
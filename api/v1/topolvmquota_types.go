@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopoLVMQuotaSpec defines the desired state of TopoLVMQuota
+type TopoLVMQuotaSpec struct {
+	// 'limits' specifies the limits of PVCs in the namespace per device-class.
+	Limits []DeviceClassQuota `json:"limits"`
+}
+
+// DeviceClassQuota defines the limits of a device-class.
+type DeviceClassQuota struct {
+	// 'deviceClass' specifies the device-class given by the StorageClass parameter.
+	// An empty string means StorageClasses without the device-class parameter.
+	// +kubebuilder:validation:Optional
+	DeviceClass string `json:"deviceClass"`
+
+	// 'storage' specifies the maximum total requested bytes of PVCs.
+	// +kubebuilder:validation:Optional
+	Storage *resource.Quantity `json:"storage,omitempty"`
+
+	// 'volumes' specifies the maximum number of PVCs.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Volumes *int64 `json:"volumes,omitempty"`
+}
+
+// TopoLVMQuotaStatus defines the observed state of TopoLVMQuota
+type TopoLVMQuotaStatus struct {
+	// 'used' shows the current usage of the device-classes in 'limits'.
+	// +kubebuilder:validation:Optional
+	Used []DeviceClassUsage `json:"used,omitempty"`
+}
+
+// DeviceClassUsage defines the usage of a device-class.
+type DeviceClassUsage struct {
+	DeviceClass string            `json:"deviceClass"`
+	Storage     resource.Quantity `json:"storage"`
+	Volumes     int64             `json:"volumes"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=topolvmquotas,scope=Namespaced
+
+// TopoLVMQuota is the Schema for the topolvmquotas API
+type TopoLVMQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TopoLVMQuotaSpec   `json:"spec,omitempty"`
+	Status TopoLVMQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// TopoLVMQuotaList contains a list of TopoLVMQuota
+type TopoLVMQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TopoLVMQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TopoLVMQuota{}, &TopoLVMQuotaList{})
+}
@@ -9,6 +9,62 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassQuota) DeepCopyInto(out *DeviceClassQuota) {
+	*out = *in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassQuota.
+func (in *DeviceClassQuota) DeepCopy() *DeviceClassQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceClassUsage) DeepCopyInto(out *DeviceClassUsage) {
+	*out = *in
+	out.Storage = in.Storage.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceClassUsage.
+func (in *DeviceClassUsage) DeepCopy() *DeviceClassUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceClassUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IOLimits) DeepCopyInto(out *IOLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IOLimits.
+func (in *IOLimits) DeepCopy() *IOLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(IOLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogicalVolume) DeepCopyInto(out *LogicalVolume) {
 	*out = *in
@@ -92,6 +148,16 @@ func (in *LogicalVolumeStatus) DeepCopyInto(out *LogicalVolumeStatus) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.IOLimits != nil {
+		in, out := &in.IOLimits, &out.IOLimits
+		*out = new(IOLimits)
+		**out = **in
+	}
+	if in.PublishedTargets != nil {
+		in, out := &in.PublishedTargets, &out.PublishedTargets
+		*out = make([]PublishedTarget, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalVolumeStatus.
@@ -103,3 +169,121 @@ func (in *LogicalVolumeStatus) DeepCopy() *LogicalVolumeStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishedTarget) DeepCopyInto(out *PublishedTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishedTarget.
+func (in *PublishedTarget) DeepCopy() *PublishedTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishedTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopoLVMQuota) DeepCopyInto(out *TopoLVMQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoLVMQuota.
+func (in *TopoLVMQuota) DeepCopy() *TopoLVMQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(TopoLVMQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TopoLVMQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopoLVMQuotaList) DeepCopyInto(out *TopoLVMQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TopoLVMQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoLVMQuotaList.
+func (in *TopoLVMQuotaList) DeepCopy() *TopoLVMQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(TopoLVMQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TopoLVMQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopoLVMQuotaSpec) DeepCopyInto(out *TopoLVMQuotaSpec) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make([]DeviceClassQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoLVMQuotaSpec.
+func (in *TopoLVMQuotaSpec) DeepCopy() *TopoLVMQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TopoLVMQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopoLVMQuotaStatus) DeepCopyInto(out *TopoLVMQuotaStatus) {
+	*out = *in
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make([]DeviceClassUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopoLVMQuotaStatus.
+func (in *TopoLVMQuotaStatus) DeepCopy() *TopoLVMQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TopoLVMQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,10 +1,15 @@
 package topolvm
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -30,6 +35,53 @@ func GetCapacityKeyPrefix() string {
 	return fmt.Sprintf("capacity.%s/", GetPluginName())
 }
 
+// GetSizeKeyPrefix returns the key prefix of Node annotation that represents VG size.
+// For thin device-classes, it represents the size of the thin pool with overprovision.
+func GetSizeKeyPrefix() string {
+	return fmt.Sprintf("size.%s/", GetPluginName())
+}
+
+// GetThinDataPercentKeyPrefix returns the key prefix of Node annotation that represents data percent occupied on the thin pool.
+func GetThinDataPercentKeyPrefix() string {
+	return fmt.Sprintf("thin-data-percent.%s/", GetPluginName())
+}
+
+// GetThinMetadataPercentKeyPrefix returns the key prefix of Node annotation that represents metadata percent occupied on the thin pool.
+func GetThinMetadataPercentKeyPrefix() string {
+	return fmt.Sprintf("thin-metadata-percent.%s/", GetPluginName())
+}
+
+// GetDeviceClassesKeyPrefix returns the key prefix of Pod annotation that represents the device-classes of a fallback group.
+func GetDeviceClassesKeyPrefix() string {
+	return fmt.Sprintf("device-classes.%s/", GetPluginName())
+}
+
+// GetDeviceClassSelectorKeyPrefix returns the key prefix of Pod annotation that represents the label selector of a fallback group.
+func GetDeviceClassSelectorKeyPrefix() string {
+	return fmt.Sprintf("device-class-selector.%s/", GetPluginName())
+}
+
+// GetDeviceClassLabelsKeyPrefix returns the key prefix of Node annotation that represents the labels of a device-class.
+func GetDeviceClassLabelsKeyPrefix() string {
+	return fmt.Sprintf("device-class-labels.%s/", GetPluginName())
+}
+
+// GetMkfsOptionsKeyPrefix returns the key prefix of Node annotation that represents the default mkfs options of a device-class.
+// The value is a JSON object whose keys are filesystem types.
+func GetMkfsOptionsKeyPrefix() string {
+	return fmt.Sprintf("mkfs-options.%s/", GetPluginName())
+}
+
+// GetMountOptionsKeyPrefix returns the key prefix of Node annotation that represents the default mount options of a device-class.
+func GetMountOptionsKeyPrefix() string {
+	return fmt.Sprintf("mount-options.%s/", GetPluginName())
+}
+
+// GetReservedKeyPrefix returns the key prefix of Node annotation that represents VG space reserved for in-flight LogicalVolumes.
+func GetReservedKeyPrefix() string {
+	return fmt.Sprintf("reserved.%s/", GetPluginName())
+}
+
 // GetCapacityResource returns the resource name of topolvm capacity.
 func GetCapacityResource() corev1.ResourceName {
 	return corev1.ResourceName(fmt.Sprintf("%s/capacity", GetPluginName()))
@@ -45,16 +97,131 @@ func GetDeviceClassKey() string {
 	return fmt.Sprintf("%s/device-class", GetPluginName())
 }
 
+// DeviceClasses returns the device-classes in priority order from the value of the device-class parameter.
+// The value may list several device-classes separated by commas, e.g. "nvme,ssd,hdd".
+// The default device-class is returned for an empty value.
+func DeviceClasses(param string) []string {
+	var dcs []string
+	for _, dc := range strings.Split(param, ",") {
+		dc = strings.TrimSpace(dc)
+		if dc != "" {
+			dcs = append(dcs, dc)
+		}
+	}
+	if len(dcs) == 0 {
+		return []string{DefaultDeviceClassName}
+	}
+	return dcs
+}
+
+// GetFallbackGroupName returns the name of the fallback group of the device-classes.
+// It is used in the pod annotations instead of a device-class name.
+func GetFallbackGroupName(dcs []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(dcs, ",")))
+	return "fallback-" + hex.EncodeToString(sum[:])[:10]
+}
+
+// GetDeviceClassSelectorKey returns the key used in CSI volume create requests to select device-classes by their labels.
+func GetDeviceClassSelectorKey() string {
+	return fmt.Sprintf("%s/device-class-selector", GetPluginName())
+}
+
+// GetSelectorGroupName returns the name of the fallback group of the device-classes selected by the label selector.
+// It is used in the pod annotations instead of a device-class name.
+func GetSelectorGroupName(selector labels.Selector) string {
+	sum := sha256.Sum256([]byte(selector.String()))
+	return "selector-" + hex.EncodeToString(sum[:])[:10]
+}
+
+// SelectDeviceClasses returns the device-classes of the node whose labels match the selector.
+// The labels are published to the annotations of the node by topolvm-node.
+// The device-classes are sorted by name.
+func SelectDeviceClasses(node *corev1.Node, selector labels.Selector) []string {
+	var dcs []string
+	for k, v := range node.Annotations {
+		if !strings.HasPrefix(k, GetDeviceClassLabelsKeyPrefix()) {
+			continue
+		}
+		set := labels.Set{}
+		if v != "" {
+			var err error
+			set, err = labels.ConvertSelectorToLabelsMap(v)
+			if err != nil {
+				continue
+			}
+		}
+		if selector.Matches(set) {
+			dcs = append(dcs, k[len(GetDeviceClassLabelsKeyPrefix()):])
+		}
+	}
+	sort.Strings(dcs)
+	return dcs
+}
+
 // GetDeviceClassKey returns the key used in CSI volume create requests to specify a lvcreate-option-class.
 func GetLvcreateOptionClassKey() string {
 	return fmt.Sprintf("%s/lvcreate-option-class", GetPluginName())
 }
 
+// GetReadIOPSKey returns the key used in CSI volume create requests to specify the read IOPS limit.
+func GetReadIOPSKey() string {
+	return fmt.Sprintf("%s/read-iops", GetPluginName())
+}
+
+// GetWriteIOPSKey returns the key used in CSI volume create requests to specify the write IOPS limit.
+func GetWriteIOPSKey() string {
+	return fmt.Sprintf("%s/write-iops", GetPluginName())
+}
+
+// GetReadBPSKey returns the key used in CSI volume create requests to specify the read bytes per second limit.
+func GetReadBPSKey() string {
+	return fmt.Sprintf("%s/read-bps", GetPluginName())
+}
+
+// GetWriteBPSKey returns the key used in CSI volume create requests to specify the write bytes per second limit.
+func GetWriteBPSKey() string {
+	return fmt.Sprintf("%s/write-bps", GetPluginName())
+}
+
+// GetFsFreezeKey returns the key used in CSI volume create and snapshot requests to specify the filesystem freeze policy.
+// It is also used as the annotation key of LogicalVolume to record the policy.
+func GetFsFreezeKey() string {
+	return fmt.Sprintf("%s/fsfreeze", GetPluginName())
+}
+
+// GetFsckKey returns the key used in CSI volume create requests to specify the filesystem check policy.
+// It is also used as the annotation key of LogicalVolume to record the policy.
+func GetFsckKey() string {
+	return fmt.Sprintf("%s/fsck", GetPluginName())
+}
+
+// GetMkfsOptionsKey returns the key used in CSI volume create requests to specify the mkfs options.
+// It is also used as the annotation key of LogicalVolume to record the options.
+func GetMkfsOptionsKey() string {
+	return fmt.Sprintf("%s/mkfs-options", GetPluginName())
+}
+
+// GetMountOptionsKey returns the key used in CSI volume create requests to specify the default mount options.
+// It is also used as the annotation key of LogicalVolume to record the options.
+func GetMountOptionsKey() string {
+	return fmt.Sprintf("%s/mount-options", GetPluginName())
+}
+
 // GetResizeRequestedAtKey returns the key of LogicalVolume that represents the timestamp of the resize request.
 func GetResizeRequestedAtKey() string {
 	return fmt.Sprintf("%s/resize-requested-at", GetPluginName())
 }
 
+// GetGroupSnapshotKey returns the label key of LogicalVolume that represents the volume group snapshot it belongs to.
+func GetGroupSnapshotKey() string {
+	return fmt.Sprintf("%s/group-snapshot", GetPluginName())
+}
+
+// GetGroupSnapshotSizeKey returns the annotation key of LogicalVolume that represents the number of snapshots in the volume group snapshot.
+func GetGroupSnapshotSizeKey() string {
+	return fmt.Sprintf("%s/group-snapshot-size", GetPluginName())
+}
+
 // GetLogicalVolumeFinalizer returns the name of LogicalVolume finalizer
 func GetLogicalVolumeFinalizer() string {
 	return fmt.Sprintf("%s/logicalvolume", GetPluginName())
@@ -71,6 +238,27 @@ const PVCFinalizer = pluginName + "/pvc"
 // LegacyPVCFinalizer is a legacy finalizer of PVC.
 const LegacyPVCFinalizer = legacyPluginName + "/pvc"
 
+// Filesystem freeze policies for snapshots.
+const (
+	// FsFreezeRequired fails to create the snapshot if the filesystem cannot be frozen.
+	FsFreezeRequired = "required"
+	// FsFreezeBestEffort creates the snapshot without freezing if the filesystem cannot be frozen.
+	FsFreezeBestEffort = "best-effort"
+	// FsFreezeDisabled does not freeze the filesystem.
+	FsFreezeDisabled = "disabled"
+)
+
+// Filesystem check policies before mounting volumes.
+const (
+	// FsckNever mounts the filesystem without checking it.
+	FsckNever = "never"
+	// FsckPreen repairs the filesystem automatically before mounting it if needed.
+	// This is the default policy.
+	FsckPreen = "preen"
+	// FsckFullIfDirty checks the whole filesystem before mounting it if it is marked dirty, and preens it otherwise.
+	FsckFullIfDirty = "full-if-dirty"
+)
+
 // DefaultCSISocket is the default path of the CSI socket file.
 const DefaultCSISocket = "/run/topolvm/csi-topolvm.sock"
 
@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	testingutil "github.com/topolvm/topolvm/util/testing"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func TestUseLegacy(t *testing.T) {
@@ -130,3 +133,55 @@ func doContainTest(t *testing.T, f func() string) {
 		})
 	}
 }
+
+func TestDeviceClasses(t *testing.T) {
+	tests := []struct {
+		param    string
+		expected []string
+	}{
+		{"", []string{DefaultDeviceClassName}},
+		{"ssd", []string{"ssd"}},
+		{"nvme,ssd,hdd", []string{"nvme", "ssd", "hdd"}},
+		{" nvme , ssd,,", []string{"nvme", "ssd"}},
+	}
+	for _, tt := range tests {
+		actual := DeviceClasses(tt.param)
+		if strings.Join(actual, "/") != strings.Join(tt.expected, "/") || len(actual) != len(tt.expected) {
+			t.Errorf("DeviceClasses(%q): expected=%q actual=%q", tt.param, tt.expected, actual)
+		}
+	}
+}
+
+func TestSelectDeviceClasses(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				GetDeviceClassLabelsKeyPrefix() + "nvme0":    "media=ssd",
+				GetDeviceClassLabelsKeyPrefix() + "fast":     "media=ssd,redundancy=raid1",
+				GetDeviceClassLabelsKeyPrefix() + "hdd":      "media=hdd",
+				GetDeviceClassLabelsKeyPrefix() + "nolabels": "",
+				GetCapacityKeyPrefix() + "ssd":               "1073741824",
+			},
+		},
+	}
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{"media=ssd", []string{"fast", "nvme0"}},
+		{"media=ssd,redundancy=raid1", []string{"fast"}},
+		{"media in (ssd,hdd),redundancy!=raid1", []string{"hdd", "nvme0"}},
+		{"!media", []string{"nolabels"}},
+		{"media=tape", nil},
+	}
+	for _, tt := range tests {
+		selector, err := labels.Parse(tt.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := SelectDeviceClasses(node, selector)
+		if strings.Join(actual, "/") != strings.Join(tt.expected, "/") || len(actual) != len(tt.expected) {
+			t.Errorf("SelectDeviceClasses(%q): expected=%q actual=%q", tt.selector, tt.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/filesystem"
+	"golang.org/x/sys/unix"
+	mountutil "k8s.io/mount-utils"
+)
+
+// fsFreezeTimeout is the maximum duration while the filesystem is frozen to take a snapshot.
+const fsFreezeTimeout = 30 * time.Second
+
+const mountInfoPath = "/proc/self/mountinfo"
+
+// errNotMounted is returned when the filesystem of a volume published read/write is not found.
+var errNotMounted = errors.New("filesystem is not mounted on any target")
+
+// fsFreezer freezes filesystems to take consistent snapshots.
+type fsFreezer struct {
+	freeze  func(target string) error
+	thaw    func(target string) error
+	timeout time.Duration
+}
+
+func newFsFreezer() fsFreezer {
+	return fsFreezer{
+		freeze:  filesystem.Freeze,
+		thaw:    filesystem.Thaw,
+		timeout: fsFreezeTimeout,
+	}
+}
+
+// freezeTarget freezes the filesystem mounted on target according to the policy.
+// Freezing stalls I/O of the applications, so an empty policy means disabled.
+// findErr is the error occurred while finding the target, and an empty target means that
+// the filesystem is not written because it is not mounted read/write.
+// The returned function must be called after the snapshot is created to thaw the filesystem.
+// It returns false if the filesystem was thawed by the timeout before it is called, because
+// the snapshot may be taken after the filesystem is thawed.
+func (f fsFreezer) freezeTarget(log logr.Logger, policy, target string, findErr error) (func() bool, error) {
+	noop := func() bool { return true }
+	if policy == "" || policy == topolvm.FsFreezeDisabled {
+		return noop, nil
+	}
+
+	if findErr != nil {
+		if policy == topolvm.FsFreezeRequired {
+			return nil, findErr
+		}
+		log.Error(findErr, "failed to find the filesystem, taking a snapshot without freezing")
+		return noop, nil
+	}
+	if target == "" {
+		return noop, nil
+	}
+
+	if err := f.freeze(target); err != nil {
+		if policy == topolvm.FsFreezeRequired {
+			return nil, err
+		}
+		log.Error(err, "failed to freeze filesystem, taking a snapshot without freezing", "target", target)
+		return noop, nil
+	}
+	log.Info("froze filesystem", "target", target)
+
+	var once sync.Once
+	thaw := func() {
+		once.Do(func() {
+			if err := f.thaw(target); err != nil {
+				log.Error(err, "failed to thaw filesystem", "target", target)
+				return
+			}
+			log.Info("thawed filesystem", "target", target)
+		})
+	}
+	timer := time.AfterFunc(f.timeout, func() {
+		log.Info("filesystem freeze timed out", "target", target)
+		thaw()
+	})
+	return func() bool {
+		frozen := timer.Stop()
+		thaw()
+		// An unfrozen snapshot is acceptable for best-effort.
+		return frozen || policy != topolvm.FsFreezeRequired
+	}, nil
+}
+
+// findMountedTarget returns the path in paths where the filesystem on the device dev is mounted.
+// The paths that do not exist or are not directories, i.e. device files of block volumes, are ignored.
+// errNotMounted is returned if the filesystem is mounted on none of the directories.
+// The device of a mount is identified by the device number in mountinfo, or by the mount source
+// for filesystems such as btrfs that report anonymous device numbers.
+func findMountedTarget(paths []string, mountInfos []mountutil.MountInfo, dev uint64, sourceDevice func(string) (uint64, bool)) (string, error) {
+	var dirs []string
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil || !fi.IsDir() {
+			continue
+		}
+		dirs = append(dirs, filepath.Clean(p))
+	}
+	if len(dirs) == 0 {
+		return "", nil
+	}
+
+	for _, dir := range dirs {
+		for _, mi := range mountInfos {
+			if mi.MountPoint != dir {
+				continue
+			}
+			if unix.Mkdev(uint32(mi.Major), uint32(mi.Minor)) == dev {
+				return dir, nil
+			}
+			if d, ok := sourceDevice(mi.Source); ok && d == dev {
+				return dir, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: %v", errNotMounted, dirs)
+}
+
+// blockDeviceNumber returns the device number of the block device file.
+func blockDeviceNumber(path string) (uint64, bool) {
+	var st unix.Stat_t
+	if err := filesystem.Stat(path, &st); err != nil {
+		return 0, false
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return 0, false
+	}
+	return st.Rdev, true
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/topolvm/topolvm"
+	"golang.org/x/sys/unix"
+	mountutil "k8s.io/mount-utils"
+)
+
+type fakeFilesystem struct {
+	mu        sync.Mutex
+	freezeErr error
+	frozen    []string
+	thawed    []string
+}
+
+func (f *fakeFilesystem) freezer(timeout time.Duration) fsFreezer {
+	return fsFreezer{
+		freeze: func(target string) error {
+			if f.freezeErr != nil {
+				return f.freezeErr
+			}
+			f.frozen = append(f.frozen, target)
+			return nil
+		},
+		thaw: func(target string) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.thawed = append(f.thawed, target)
+			return nil
+		},
+		timeout: timeout,
+	}
+}
+
+func TestFreezeTarget(t *testing.T) {
+	errFind := errors.New("find error")
+	errFreeze := errors.New("freeze error")
+
+	testCases := []struct {
+		name      string
+		policy    string
+		target    string
+		findErr   error
+		freezeErr error
+		isErr     bool
+		frozen    bool
+	}{
+		{name: "default", policy: "", target: "/mnt"},
+		{name: "best-effort", policy: topolvm.FsFreezeBestEffort, target: "/mnt", frozen: true},
+		{name: "required", policy: topolvm.FsFreezeRequired, target: "/mnt", frozen: true},
+		{name: "disabled", policy: topolvm.FsFreezeDisabled, target: "/mnt"},
+		{name: "not mounted", policy: topolvm.FsFreezeRequired, target: ""},
+		{name: "best-effort find error", policy: topolvm.FsFreezeBestEffort, findErr: errFind},
+		{name: "required find error", policy: topolvm.FsFreezeRequired, findErr: errFind, isErr: true},
+		{name: "best-effort freeze error", policy: topolvm.FsFreezeBestEffort, target: "/mnt", freezeErr: errFreeze},
+		{name: "required freeze error", policy: topolvm.FsFreezeRequired, target: "/mnt", freezeErr: errFreeze, isErr: true},
+	}
+
+	for _, tc := range testCases {
+		fs := &fakeFilesystem{freezeErr: tc.freezeErr}
+		thaw, err := fs.freezer(time.Minute).freezeTarget(logr.Discard(), tc.policy, tc.target, tc.findErr)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("%s: error is expected", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if (len(fs.frozen) == 1) != tc.frozen {
+			t.Errorf("%s: unexpected frozen targets: %v", tc.name, fs.frozen)
+		}
+		if !thaw() {
+			t.Errorf("%s: snapshot should be consistent", tc.name)
+		}
+		thaw()
+		if len(fs.thawed) != len(fs.frozen) {
+			t.Errorf("%s: filesystem should be thawed once: %v", tc.name, fs.thawed)
+		}
+	}
+}
+
+func TestFreezeTargetTimeout(t *testing.T) {
+	for _, policy := range []string{topolvm.FsFreezeRequired, topolvm.FsFreezeBestEffort} {
+		fs := &fakeFilesystem{}
+		thaw, err := fs.freezer(10*time.Millisecond).freezeTarget(logr.Discard(), policy, "/mnt", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+		fs.mu.Lock()
+		thawed := len(fs.thawed)
+		fs.mu.Unlock()
+		if thawed != 1 {
+			t.Errorf("%s: filesystem should be thawed by the timeout: %v", policy, fs.thawed)
+		}
+
+		consistent := thaw()
+		if policy == topolvm.FsFreezeRequired && consistent {
+			t.Errorf("%s: snapshot taken after the timeout should not be accepted", policy)
+		}
+		if policy == topolvm.FsFreezeBestEffort && !consistent {
+			t.Errorf("%s: snapshot taken after the timeout should be accepted", policy)
+		}
+		if len(fs.thawed) != 1 {
+			t.Errorf("%s: filesystem should not be thawed twice: %v", policy, fs.thawed)
+		}
+	}
+}
+
+func TestFindMountedTarget(t *testing.T) {
+	dir := t.TempDir()
+	ext4 := filepath.Join(dir, "ext4")
+	btrfs := filepath.Join(dir, "btrfs")
+	unmounted := filepath.Join(dir, "unmounted")
+	block := filepath.Join(dir, "block")
+	for _, d := range []string{ext4, btrfs, unmounted} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A block volume is published as a device file, which is not a directory.
+	if err := os.WriteFile(block, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	dev := unix.Mkdev(253, 3)
+	mountInfos := []mountutil.MountInfo{
+		{Major: 8, Minor: 1, Source: "/dev/sda1", MountPoint: "/"},
+		{Major: 253, Minor: 3, Source: "/dev/topolvm/vol", MountPoint: ext4},
+		// btrfs reports an anonymous device number.
+		{Major: 0, Minor: 52, Source: "/dev/topolvm/vol", MountPoint: btrfs},
+		{Major: 253, Minor: 4, Source: "/dev/topolvm/other", MountPoint: unmounted},
+	}
+	sourceDevice := func(path string) (uint64, bool) {
+		switch path {
+		case "/dev/topolvm/vol":
+			return dev, true
+		case "/dev/topolvm/other":
+			return unix.Mkdev(253, 4), true
+		}
+		return 0, false
+	}
+
+	testCases := []struct {
+		name     string
+		paths    []string
+		expected string
+		isErr    bool
+	}{
+		{name: "filesystem", paths: []string{ext4}, expected: ext4},
+		{name: "btrfs", paths: []string{btrfs}, expected: btrfs},
+		{name: "block volume", paths: []string{block}, expected: ""},
+		{name: "removed target", paths: []string{missing}, expected: ""},
+		{name: "block and filesystem", paths: []string{block, unmounted, ext4}, expected: ext4},
+		{name: "not mounted", paths: []string{block, unmounted}, isErr: true},
+	}
+
+	for _, tc := range testCases {
+		target, err := findMountedTarget(tc.paths, mountInfos, dev, sourceDevice)
+		if tc.isErr {
+			if !errors.Is(err, errNotMounted) {
+				t.Errorf("%s: errNotMounted is expected: %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if target != tc.expected {
+			t.Errorf("%s: expected=%q actual=%q", tc.name, tc.expected, target)
+		}
+	}
+}
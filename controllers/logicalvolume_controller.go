@@ -2,19 +2,24 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/topolvm/topolvm"
 	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
 	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 	"github.com/topolvm/topolvm/lvmd/proto"
+	"golang.org/x/sys/unix"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
+	mountutil "k8s.io/mount-utils"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -22,12 +27,19 @@ import (
 	crlog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// groupSnapshotRequeueInterval is the interval to check that all the LogicalVolumes of a volume group snapshot are created.
+const groupSnapshotRequeueInterval = time.Second
+
+// errGroupSnapshotIncomplete is returned when some LogicalVolumes of a volume group snapshot are not created yet.
+var errGroupSnapshotIncomplete = errors.New("volume group snapshot is incomplete")
+
 // LogicalVolumeReconciler reconciles a LogicalVolume object
 type LogicalVolumeReconciler struct {
 	client    client.Client
 	nodeName  string
 	vgService proto.VGServiceClient
 	lvService proto.LVServiceClient
+	freezer   fsFreezer
 }
 
 //+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;update;patch
@@ -40,6 +52,7 @@ func NewLogicalVolumeReconciler(client client.Client, nodeName string, conn *grp
 		nodeName:  nodeName,
 		vgService: proto.NewVGServiceClient(conn),
 		lvService: proto.NewLVServiceClient(conn),
+		freezer:   newFsFreezer(),
 	}
 }
 
@@ -88,10 +101,18 @@ func (r *LogicalVolumeReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 		if lv.Status.VolumeID == "" {
 			err := r.createLV(ctx, log, lv)
+			if errors.Is(err, errGroupSnapshotIncomplete) {
+				log.Info("waiting for the other snapshots of the volume group snapshot", "name", lv.Name)
+				return ctrl.Result{RequeueAfter: groupSnapshotRequeueInterval}, nil
+			}
 			if err != nil {
 				log.Error(err, "failed to create LV", "name", lv.Name)
+				return ctrl.Result{}, err
 			}
-			return ctrl.Result{}, err
+			if lv.Status.VolumeID == "" {
+				return ctrl.Result{}, nil
+			}
+			// A snapshot LV is created at the size of the source, so it is expanded to the requested size below.
 		}
 
 		err := r.expandLV(ctx, log, lv)
@@ -203,8 +224,14 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 
 		var volume *proto.LogicalVolume
 
-		// Create a snapshot LV
-		if lv.Spec.Source != "" {
+		if _, ok := lv.Labels[topolvm.GetGroupSnapshotKey()]; ok && lv.Spec.Source != "" {
+			// Create snapshot LVs of the volume group snapshot at once
+			volume, err = r.createGroupSnapshot(ctx, log, lv)
+			if err != nil {
+				return err
+			}
+		} else if lv.Spec.Source != "" {
+			// Create a snapshot LV
 			// accessType should be either "readonly" or "readwrite".
 			if lv.Spec.AccessType != "ro" && lv.Spec.AccessType != "rw" {
 				return fmt.Errorf("invalid access type for source volume: %s", lv.Spec.AccessType)
@@ -216,6 +243,14 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 			}
 			sourceVolID := sourcelv.Status.VolumeID
 
+			thaw, err := r.freezeSource(ctx, log, lv, sourcelv)
+			if err != nil {
+				log.Error(err, "failed to freeze filesystem", "name", lv.Name, "source", sourcelv.Name)
+				lv.Status.Code = codes.FailedPrecondition
+				lv.Status.Message = fmt.Sprintf("failed to freeze filesystem: %v", err)
+				return err
+			}
+
 			// Create a snapshot lv
 			resp, err := r.lvService.CreateLVSnapshot(ctx, &proto.CreateLVSnapshotRequest{
 				Name:         string(lv.UID),
@@ -224,6 +259,7 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 				SizeGb:       uint64(reqBytes >> 30),
 				AccessType:   lv.Spec.AccessType,
 			})
+			frozen := thaw()
 			if err != nil {
 				code, message := extractFromError(err)
 				log.Error(err, message)
@@ -231,6 +267,10 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 				lv.Status.Message = message
 				return err
 			}
+			if !frozen {
+				// The snapshot has to be removed before retrying, otherwise it is adopted as is.
+				return r.removeUnfrozenSnapshot(ctx, log, lv)
+			}
 			volume = resp.Snapshot
 		} else {
 			// Create a regular lv
@@ -251,12 +291,19 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 		}
 
 		lv.Status.VolumeID = volume.Name
-		lv.Status.CurrentSize = resource.NewQuantity(reqBytes, resource.BinarySI)
+		if lv.Spec.Source != "" {
+			lv.Status.CurrentSize = resource.NewQuantity(int64(volume.SizeGb<<30), resource.BinarySI)
+		} else {
+			lv.Status.CurrentSize = resource.NewQuantity(reqBytes, resource.BinarySI)
+		}
 		lv.Status.Code = codes.OK
 		lv.Status.Message = ""
 		return nil
 	}()
 
+	if errors.Is(err, errGroupSnapshotIncomplete) {
+		return err
+	}
 	if err != nil {
 		if err2 := r.client.Status().Update(ctx, lv); err2 != nil {
 			// err2 is logged but not returned because err is more important
@@ -274,6 +321,174 @@ func (r *LogicalVolumeReconciler) createLV(ctx context.Context, log logr.Logger,
 	return nil
 }
 
+// freezeSource freezes the filesystem of the source volume to take a snapshot according to the policy of lv.
+// The filesystem is found through the targets where the source volume is published.
+// See fsFreezer.freezeTarget for the returned function.
+func (r *LogicalVolumeReconciler) freezeSource(ctx context.Context, log logr.Logger, lv, sourcelv *topolvmv1.LogicalVolume) (func() bool, error) {
+	policy := lv.Annotations[topolvm.GetFsFreezeKey()]
+	var target string
+	var err error
+	if policy != "" && policy != topolvm.FsFreezeDisabled {
+		target, err = r.mountedTarget(ctx, sourcelv)
+	}
+	return r.freezer.freezeTarget(log.WithValues("name", lv.Name, "source", sourcelv.Name), policy, target, err)
+}
+
+// removeUnfrozenSnapshot removes the snapshot LV taken after the filesystem was thawed by the timeout.
+// It returns an error to retry the creation.  If the LV cannot be removed, the creation fails
+// not to adopt the inconsistent snapshot.
+func (r *LogicalVolumeReconciler) removeUnfrozenSnapshot(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
+	err := fmt.Errorf("filesystem was thawed after %s before the snapshot was created", r.freezer.timeout)
+	log.Error(err, "removing the snapshot taken without freezing", "name", lv.Name, "uid", lv.UID)
+	_, err2 := r.lvService.RemoveLV(ctx, &proto.RemoveLVRequest{Name: string(lv.UID), DeviceClass: lv.Spec.DeviceClass})
+	if err2 != nil {
+		log.Error(err2, "failed to remove LV", "name", lv.Name, "uid", lv.UID)
+		lv.Status.Code = codes.DeadlineExceeded
+	}
+	lv.Status.Message = err.Error()
+	return err
+}
+
+// mountedTarget returns a target path where the filesystem of lv is mounted read/write.
+// It returns an empty string if there is no such target.
+func (r *LogicalVolumeReconciler) mountedTarget(ctx context.Context, lv *topolvmv1.LogicalVolume) (string, error) {
+	var paths []string
+	for _, t := range lv.Status.PublishedTargets {
+		if !t.ReadOnly {
+			paths = append(paths, t.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return "", nil
+	}
+
+	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
+	if err != nil {
+		return "", err
+	}
+	var volume *proto.LogicalVolume
+	for _, v := range respList.Volumes {
+		if v.Name == lv.Status.VolumeID {
+			volume = v
+			break
+		}
+	}
+	if volume == nil {
+		return "", fmt.Errorf("logical volume %s is not found", lv.Status.VolumeID)
+	}
+
+	mountInfos, err := mountutil.ParseMountInfo(mountInfoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", mountInfoPath, err)
+	}
+	return findMountedTarget(paths, mountInfos, unix.Mkdev(volume.DevMajor, volume.DevMinor), blockDeviceNumber)
+}
+
+// createGroupSnapshot creates the snapshot LVs of all the LogicalVolumes in the volume group snapshot of lv at once.
+// The statuses of the LogicalVolumes other than lv are updated here.
+func (r *LogicalVolumeReconciler) createGroupSnapshot(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) (*proto.LogicalVolume, error) {
+	group := lv.Labels[topolvm.GetGroupSnapshotKey()]
+	size, err := strconv.Atoi(lv.Annotations[topolvm.GetGroupSnapshotSizeKey()])
+	if err != nil {
+		lv.Status.Code = codes.InvalidArgument
+		lv.Status.Message = fmt.Sprintf("invalid annotation %s", topolvm.GetGroupSnapshotSizeKey())
+		return nil, err
+	}
+
+	lvList := new(topolvmv1.LogicalVolumeList)
+	if err := r.client.List(ctx, lvList, client.MatchingLabels{topolvm.GetGroupSnapshotKey(): group}); err != nil {
+		log.Error(err, "failed to list LogicalVolumes of the volume group snapshot", "group", group)
+		return nil, err
+	}
+	// The LogicalVolumes need the finalizer not to leak LVs.
+	if len(lvList.Items) < size {
+		return nil, errGroupSnapshotIncomplete
+	}
+	for _, member := range lvList.Items {
+		if !controllerutil.ContainsFinalizer(&member, topolvm.GetLogicalVolumeFinalizer()) {
+			return nil, errGroupSnapshotIncomplete
+		}
+	}
+
+	respList, err := r.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: lv.Spec.DeviceClass})
+	if err != nil {
+		lv.Status.Code = codes.Internal
+		lv.Status.Message = "failed to check volume existence"
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, v := range respList.Volumes {
+		existing[v.Name] = true
+	}
+
+	var members []*topolvmv1.LogicalVolume
+	req := &proto.CreateLVSnapshotsRequest{DeviceClass: lv.Spec.DeviceClass}
+	for i := range lvList.Items {
+		member := &lvList.Items[i]
+		if member.Status.VolumeID != "" || existing[string(member.UID)] {
+			continue
+		}
+		if member.Spec.NodeName != lv.Spec.NodeName || member.Spec.DeviceClass != lv.Spec.DeviceClass {
+			lv.Status.Code = codes.InvalidArgument
+			lv.Status.Message = "snapshots of a volume group snapshot must be on the same node and device class"
+			return nil, errors.New(lv.Status.Message)
+		}
+		sourcelv := new(topolvmv1.LogicalVolume)
+		if err := r.client.Get(ctx, types.NamespacedName{Name: member.Spec.Source}, sourcelv); err != nil {
+			log.Error(err, "unable to fetch source LogicalVolume", "name", member.Name)
+			return nil, err
+		}
+		members = append(members, member)
+		req.Snapshots = append(req.Snapshots, &proto.CreateLVSnapshotRequest{
+			Name:         string(member.UID),
+			SourceVolume: sourcelv.Status.VolumeID,
+			AccessType:   member.Spec.AccessType,
+		})
+	}
+
+	resp, err := r.lvService.CreateLVSnapshots(ctx, req)
+	if err != nil {
+		code, message := extractFromError(err)
+		log.Error(err, message)
+		lv.Status.Code = code
+		lv.Status.Message = message
+		for _, member := range members {
+			if member.UID == lv.UID {
+				continue
+			}
+			member.Status.Code = code
+			member.Status.Message = message
+			if err2 := r.client.Status().Update(ctx, member); err2 != nil {
+				log.Error(err2, "failed to update status", "name", member.Name, "uid", member.UID)
+			}
+		}
+		return nil, err
+	}
+
+	var volume *proto.LogicalVolume
+	for i, member := range members {
+		snapshot := resp.Snapshots[i]
+		if member.UID == lv.UID {
+			volume = snapshot
+			continue
+		}
+		member.Status.VolumeID = snapshot.Name
+		member.Status.CurrentSize = resource.NewQuantity(int64(snapshot.SizeGb<<30), resource.BinarySI)
+		member.Status.Code = codes.OK
+		member.Status.Message = ""
+		// If this fails, the status is set when the LogicalVolume is reconciled as the LV exists.
+		if err := r.client.Status().Update(ctx, member); err != nil {
+			log.Error(err, "failed to update status", "name", member.Name, "uid", member.UID)
+		}
+	}
+	if volume == nil {
+		return nil, fmt.Errorf("LogicalVolume %s is not found in the volume group snapshot %s", lv.Name, group)
+	}
+
+	log.Info("created snapshot LVs of the volume group snapshot", "group", group, "count", len(members))
+	return volume, nil
+}
+
 func (r *LogicalVolumeReconciler) expandLV(ctx context.Context, log logr.Logger, lv *topolvmv1.LogicalVolume) error {
 	// We denote unknown size as -1.
 	var origBytes int64 = -1
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/topolvm/topolvm"
+	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/reservation"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ReservationReconciler publishes the space reserved for in-flight LogicalVolumes as Node annotations.
+type ReservationReconciler struct {
+	client client.Client
+}
+
+// NewReservationReconciler returns ReservationReconciler.
+func NewReservationReconciler(client client.Client) *ReservationReconciler {
+	return &ReservationReconciler{
+		client: client,
+	}
+}
+
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+
+// Reconcile updates the reservation annotations of Node.
+func (r *ReservationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	node := &corev1.Node{}
+	err := r.client.Get(ctx, req.NamespacedName, node)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+
+	if node.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	ledger, err := reservation.Build(ctx, r.client, "")
+	if err != nil {
+		log.Error(err, "failed to build the reservation ledger")
+		return ctrl.Result{}, err
+	}
+
+	desired := reservation.Annotations(ledger[node.Name])
+	node2 := node.DeepCopy()
+	changed := false
+	for k := range node2.Annotations {
+		if !strings.HasPrefix(k, topolvm.GetReservedKeyPrefix()) {
+			continue
+		}
+		if _, ok := desired[k]; !ok {
+			delete(node2.Annotations, k)
+			changed = true
+		}
+	}
+	for k, v := range desired {
+		if node2.Annotations[k] == v {
+			continue
+		}
+		if node2.Annotations == nil {
+			node2.Annotations = make(map[string]string)
+		}
+		node2.Annotations[k] = v
+		changed = true
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(node)
+	if err := r.client.Patch(ctx, node2, patch); err != nil {
+		log.Error(err, "failed to patch reservation annotations", "name", node.Name)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ReservationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	var lv client.Object = &topolvmv1.LogicalVolume{}
+	if topolvm.UseLegacy() {
+		lv = &topolvmlegacyv1.LogicalVolume{}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("reservation").
+		// Only annotation changes can make the reservation annotations stale, so status updates are ignored.
+		For(&corev1.Node{}, builder.WithPredicates(predicate.AnnotationChangedPredicate{})).
+		Watches(&source.Kind{Type: lv}, handler.EnqueueRequestsFromMapFunc(nodeOfLogicalVolume)).
+		Complete(r)
+}
+
+// nodeOfLogicalVolume returns a request for the Node of the LogicalVolume.
+func nodeOfLogicalVolume(obj client.Object) []reconcile.Request {
+	var nodeName string
+	switch lv := obj.(type) {
+	case *topolvmv1.LogicalVolume:
+		nodeName = lv.Spec.NodeName
+	case *topolvmlegacyv1.LogicalVolume:
+		nodeName = lv.Spec.NodeName
+	}
+	if nodeName == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: nodeName}}}
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ReservationController controller", func() {
+	ctx := context.Background()
+	var stopFunc func()
+	errCh := make(chan error)
+
+	BeforeEach(func() {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme: scheme,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		reconciler := NewReservationReconciler(mgr.GetClient())
+		err = reconciler.SetupWithManager(mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		stopFunc = cancel
+		go func() {
+			errCh <- mgr.Start(ctx)
+		}()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	AfterEach(func() {
+		stopFunc()
+		Expect(<-errCh).NotTo(HaveOccurred())
+	})
+
+	It("should publish the space reserved for in-flight LogicalVolumes", func() {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "reservation-node"},
+		}
+		err := k8sClient.Create(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+
+		lv := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: "reservation-lv"},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:        "reservation-lv",
+				NodeName:    node.Name,
+				DeviceClass: "ssd",
+				Size:        *resource.NewQuantity(1<<30, resource.BinarySI),
+			},
+		}
+		err = k8sClient.Create(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("reserving the space of the LogicalVolume")
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(node), node)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(node.Annotations).To(HaveKeyWithValue(topolvm.GetReservedKeyPrefix()+"ssd", "1073741824"))
+		}).Should(Succeed())
+
+		By("releasing the reservation when the LV appears")
+		lv.Status.VolumeID = "reservation-lv"
+		err = k8sClient.Status().Update(ctx, lv)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(node), node)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(node.Annotations).NotTo(HaveKey(topolvm.GetReservedKeyPrefix() + "ssd"))
+		}).Should(Succeed())
+	})
+})
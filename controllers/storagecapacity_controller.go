@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+	topolvmlegacyv1 "github.com/topolvm/topolvm/api/legacy/v1"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/reservation"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// StorageCapacityReconciler maintains CSIStorageCapacity objects for each pair of Node and StorageClass.
+type StorageCapacityReconciler struct {
+	client    client.Client
+	namespace string
+}
+
+// NewStorageCapacityReconciler returns StorageCapacityReconciler.
+// CSIStorageCapacity objects are created in the namespace.
+func NewStorageCapacityReconciler(client client.Client, namespace string) *StorageCapacityReconciler {
+	return &StorageCapacityReconciler{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=csistoragecapacities,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+
+// Reconcile creates, updates and deletes CSIStorageCapacity objects of Node.
+func (r *StorageCapacityReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	node := &corev1.Node{}
+	err := r.client.Get(ctx, req.NamespacedName, node)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		// CSIStorageCapacity objects are garbage collected by the owner reference.
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+
+	if node.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	desired, err := r.desiredCapacities(ctx, node)
+	if err != nil {
+		log.Error(err, "failed to compute capacities", "name", node.Name)
+		return ctrl.Result{}, err
+	}
+
+	for _, c := range desired {
+		capacity := &storagev1.CSIStorageCapacity{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: c.Namespace,
+				Name:      c.Name,
+			},
+		}
+		op, err := controllerutil.CreateOrUpdate(ctx, r.client, capacity, func() error {
+			if capacity.Labels == nil {
+				capacity.Labels = make(map[string]string)
+			}
+			capacity.Labels[topolvm.CreatedbyLabelKey] = topolvm.CreatedbyLabelValue
+			capacity.NodeTopology = c.NodeTopology
+			capacity.StorageClassName = c.StorageClassName
+			capacity.Capacity = c.Capacity
+			capacity.MaximumVolumeSize = c.MaximumVolumeSize
+			capacity.OwnerReferences = c.OwnerReferences
+			return nil
+		})
+		if err != nil {
+			log.Error(err, "failed to create or update CSIStorageCapacity", "name", c.Name, "storageclass", c.StorageClassName)
+			return ctrl.Result{}, err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("CSIStorageCapacity is "+string(op), "name", c.Name, "node", node.Name, "storageclass", c.StorageClassName, "capacity", c.Capacity.String())
+		}
+	}
+
+	var capacities storagev1.CSIStorageCapacityList
+	err = r.client.List(ctx, &capacities, client.InNamespace(r.namespace), client.MatchingLabels{topolvm.CreatedbyLabelKey: topolvm.CreatedbyLabelValue})
+	if err != nil {
+		log.Error(err, "failed to list CSIStorageCapacity")
+		return ctrl.Result{}, err
+	}
+	for i := range capacities.Items {
+		c := &capacities.Items[i]
+		if !isOwnedBy(c, node) {
+			continue
+		}
+		if _, ok := desired[c.Name]; ok {
+			continue
+		}
+		if err := r.client.Delete(ctx, c); err != nil && !apierrors.IsNotFound(err) {
+			log.Error(err, "failed to delete CSIStorageCapacity", "name", c.Name)
+			return ctrl.Result{}, err
+		}
+		log.Info("deleted CSIStorageCapacity", "name", c.Name, "node", node.Name, "storageclass", c.StorageClassName)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// desiredCapacities returns CSIStorageCapacity objects of the node for TopoLVM StorageClasses.
+// The keys are the names of the objects.
+func (r *StorageCapacityReconciler) desiredCapacities(ctx context.Context, node *corev1.Node) (map[string]*storagev1.CSIStorageCapacity, error) {
+	desired := make(map[string]*storagev1.CSIStorageCapacity)
+
+	// The label is added by topolvm-node.
+	topology, ok := node.Labels[topolvm.GetTopologyNodeKey()]
+	if !ok {
+		return desired, nil
+	}
+
+	var scl storagev1.StorageClassList
+	if err := r.client.List(ctx, &scl); err != nil {
+		return nil, err
+	}
+	ledger, err := reservation.Build(ctx, r.client, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sc := range scl.Items {
+		if sc.Provisioner != topolvm.GetPluginName() {
+			continue
+		}
+		// A volume is created in one of the listed or selected device-classes.
+		dcs := topolvm.DeviceClasses(sc.Parameters[topolvm.GetDeviceClassKey()])
+		if param, ok := sc.Parameters[topolvm.GetDeviceClassSelectorKey()]; ok {
+			selector, err := labels.Parse(param)
+			if err != nil {
+				crlog.FromContext(ctx).Error(err, "invalid device-class selector", "storageclass", sc.Name)
+				continue
+			}
+			dcs = topolvm.SelectDeviceClasses(node, selector)
+		}
+		var capacity, maxVolumeSize int64
+		found := false
+		for _, dc := range dcs {
+			free, ok := freeSpace(node, dc, ledger)
+			if !ok {
+				continue
+			}
+			found = true
+			capacity += free
+			if free > maxVolumeSize {
+				maxVolumeSize = free
+			}
+		}
+		if !found {
+			continue
+		}
+
+		name := storageCapacityName(node.Name, sc.Name)
+		desired[name] = &storagev1.CSIStorageCapacity{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.namespace,
+				Name:      name,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(node, corev1.SchemeGroupVersion.WithKind("Node")),
+				},
+			},
+			NodeTopology: &metav1.LabelSelector{
+				MatchLabels: map[string]string{topolvm.GetTopologyNodeKey(): topology},
+			},
+			StorageClassName: sc.Name,
+			Capacity:         resource.NewQuantity(capacity, resource.BinarySI),
+			// A volume can use up all the free space, which is overprovisioned for thin pools.
+			MaximumVolumeSize: resource.NewQuantity(maxVolumeSize, resource.BinarySI),
+		}
+	}
+	return desired, nil
+}
+
+// freeSpace returns the free space of the device-class on the node minus the reserved space.
+// The second return value is false if the node does not have the device-class.
+func freeSpace(node *corev1.Node, dc string, ledger reservation.Ledger) (int64, bool) {
+	annotationName := dc
+	if dc == topolvm.DefaultDeviceClassName {
+		annotationName = topolvm.DefaultDeviceClassAnnotationName
+	}
+	val, ok := node.Annotations[topolvm.GetCapacityKeyPrefix()+annotationName]
+	if !ok {
+		return 0, false
+	}
+	free, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	free -= ledger.Reserved(node.Name, dc)
+	if free < 0 {
+		free = 0
+	}
+	return free, true
+}
+
+// storageCapacityName returns the name of CSIStorageCapacity for the pair of Node and StorageClass.
+// The name is hashed to fit the length limit.
+func storageCapacityName(nodeName, scName string) string {
+	sum := sha256.Sum256([]byte(nodeName + "/" + scName))
+	return "topolvm-" + hex.EncodeToString(sum[:])[:16]
+}
+
+func isOwnedBy(obj metav1.Object, node *corev1.Node) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "Node" && ref.Name == node.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StorageCapacityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	var lv client.Object = &topolvmv1.LogicalVolume{}
+	if topolvm.UseLegacy() {
+		lv = &topolvmlegacyv1.LogicalVolume{}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("storagecapacity").
+		For(&corev1.Node{}, builder.WithPredicates(predicate.Or(predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Owns(&storagev1.CSIStorageCapacity{}).
+		Watches(&source.Kind{Type: &storagev1.StorageClass{}}, handler.EnqueueRequestsFromMapFunc(r.allNodes)).
+		Watches(&source.Kind{Type: lv}, handler.EnqueueRequestsFromMapFunc(nodeOfLogicalVolume)).
+		Complete(r)
+}
+
+// allNodes returns requests for all Nodes.
+func (r *StorageCapacityReconciler) allNodes(obj client.Object) []reconcile.Request {
+	var nodes corev1.NodeList
+	if err := r.client.List(context.Background(), &nodes); err != nil {
+		crlog.Log.Error(err, "failed to list Node")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(nodes.Items))
+	for i, n := range nodes.Items {
+		requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Name: n.Name}}
+	}
+	return requests
+}
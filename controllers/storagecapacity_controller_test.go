@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("StorageCapacityController controller", func() {
+	ctx := context.Background()
+	var stopFunc func()
+	errCh := make(chan error)
+
+	BeforeEach(func() {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme: scheme,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		reconciler := NewStorageCapacityReconciler(mgr.GetClient(), "default")
+		err = reconciler.SetupWithManager(mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		stopFunc = cancel
+		go func() {
+			errCh <- mgr.Start(ctx)
+		}()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	AfterEach(func() {
+		stopFunc()
+		Expect(<-errCh).NotTo(HaveOccurred())
+	})
+
+	It("should publish CSIStorageCapacity of the node", func() {
+		sc := &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "capacity-sc"},
+			Provisioner: topolvm.GetPluginName(),
+			Parameters:  map[string]string{topolvm.GetDeviceClassKey(): "capacity-dc"},
+		}
+		err := k8sClient.Create(ctx, sc)
+		Expect(err).NotTo(HaveOccurred())
+
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "capacity-node",
+				Labels:      map[string]string{topolvm.GetTopologyNodeKey(): "capacity-node"},
+				Annotations: map[string]string{topolvm.GetCapacityKeyPrefix() + "capacity-dc": "10737418240"},
+			},
+		}
+		err = k8sClient.Create(ctx, node)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("creating CSIStorageCapacity")
+		capacity := &storagev1.CSIStorageCapacity{}
+		key := client.ObjectKey{Namespace: "default", Name: storageCapacityName(node.Name, sc.Name)}
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, key, capacity)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(capacity.StorageClassName).To(Equal(sc.Name))
+			g.Expect(capacity.NodeTopology.MatchLabels).To(HaveKeyWithValue(topolvm.GetTopologyNodeKey(), "capacity-node"))
+			g.Expect(capacity.Capacity.Value()).To(Equal(int64(10737418240)))
+			g.Expect(capacity.MaximumVolumeSize.Value()).To(Equal(int64(10737418240)))
+			g.Expect(capacity.OwnerReferences).To(HaveLen(1))
+			g.Expect(capacity.OwnerReferences[0].Name).To(Equal(node.Name))
+		}).Should(Succeed())
+
+		By("updating CSIStorageCapacity when the capacity changes")
+		node2 := node.DeepCopy()
+		node2.Annotations[topolvm.GetCapacityKeyPrefix()+"capacity-dc"] = "5368709120"
+		err = k8sClient.Patch(ctx, node2, client.MergeFrom(node))
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, key, capacity)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(capacity.Capacity.Cmp(*resource.NewQuantity(5368709120, resource.BinarySI))).To(Equal(0))
+		}).Should(Succeed())
+
+		By("deleting CSIStorageCapacity when the StorageClass is deleted")
+		err = k8sClient.Delete(ctx, sc)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, key, capacity)
+			g.Expect(err).To(HaveOccurred())
+		}).Should(Succeed())
+	})
+})
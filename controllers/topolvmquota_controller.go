@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/quota"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	crlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// TopoLVMQuotaReconciler reconciles a TopoLVMQuota object
+type TopoLVMQuotaReconciler struct {
+	client client.Client
+}
+
+// NewTopoLVMQuotaReconciler returns TopoLVMQuotaReconciler.
+func NewTopoLVMQuotaReconciler(client client.Client) *TopoLVMQuotaReconciler {
+	return &TopoLVMQuotaReconciler{
+		client: client,
+	}
+}
+
+//+kubebuilder:rbac:groups=topolvm.io,resources=topolvmquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=topolvmquotas/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
+// Reconcile updates the usage in the status of TopoLVMQuota.
+func (r *TopoLVMQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := crlog.FromContext(ctx)
+
+	q := &topolvmv1.TopoLVMQuota{}
+	err := r.client.Get(ctx, req.NamespacedName, q)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{}, err
+	}
+
+	usage, err := quota.NamespaceUsage(ctx, r.client, q.Namespace, "")
+	if err != nil {
+		log.Error(err, "failed to get usage", "namespace", q.Namespace)
+		return ctrl.Result{}, err
+	}
+
+	used := usedDeviceClasses(q.Spec.Limits, usage)
+	if equality.Semantic.DeepEqual(q.Status.Used, used) {
+		return ctrl.Result{}, nil
+	}
+
+	q.Status.Used = used
+	if err := r.client.Status().Update(ctx, q); err != nil {
+		log.Error(err, "failed to update status", "name", q.Name, "namespace", q.Namespace)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// usedDeviceClasses returns the usage of the device-classes in limits.
+func usedDeviceClasses(limits []topolvmv1.DeviceClassQuota, usage map[string]*quota.Usage) []topolvmv1.DeviceClassUsage {
+	var used []topolvmv1.DeviceClassUsage
+	seen := make(map[string]bool)
+	for _, l := range limits {
+		if seen[l.DeviceClass] {
+			continue
+		}
+		seen[l.DeviceClass] = true
+
+		u := topolvmv1.DeviceClassUsage{
+			DeviceClass: l.DeviceClass,
+			Storage:     *resource.NewQuantity(0, resource.BinarySI),
+		}
+		if v, ok := usage[l.DeviceClass]; ok {
+			u.Storage = *resource.NewQuantity(v.Bytes, resource.BinarySI)
+			u.Volumes = v.Volumes
+		}
+		used = append(used, u)
+	}
+	return used
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TopoLVMQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&topolvmv1.TopoLVMQuota{}).
+		Watches(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, handler.EnqueueRequestsFromMapFunc(r.quotasInNamespace)).
+		Complete(r)
+}
+
+// quotasInNamespace returns requests for TopoLVMQuotas in the namespace of the object.
+func (r *TopoLVMQuotaReconciler) quotasInNamespace(obj client.Object) []reconcile.Request {
+	var quotaList topolvmv1.TopoLVMQuotaList
+	if err := r.client.List(context.Background(), &quotaList, client.InNamespace(obj.GetNamespace())); err != nil {
+		crlog.Log.Error(err, "failed to list TopoLVMQuota", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(quotaList.Items))
+	for i, q := range quotaList.Items {
+		requests[i] = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: q.Namespace, Name: q.Name}}
+	}
+	return requests
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("TopoLVMQuotaController controller", func() {
+	ctx := context.Background()
+	var stopFunc func()
+	errCh := make(chan error)
+
+	BeforeEach(func() {
+		mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+			Scheme: scheme,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		reconciler := NewTopoLVMQuotaReconciler(mgr.GetClient())
+		err = reconciler.SetupWithManager(mgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(ctx)
+		stopFunc = cancel
+		go func() {
+			errCh <- mgr.Start(ctx)
+		}()
+		time.Sleep(100 * time.Millisecond)
+	})
+
+	AfterEach(func() {
+		stopFunc()
+		Expect(<-errCh).NotTo(HaveOccurred())
+	})
+
+	It("should report the usage of device-classes", func() {
+		ns := createNamespace()
+
+		sc := &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "quota-ssd"},
+			Provisioner: topolvm.GetPluginName(),
+			Parameters:  map[string]string{topolvm.GetDeviceClassKey(): "ssd"},
+		}
+		err := k8sClient.Create(ctx, sc)
+		Expect(err).NotTo(HaveOccurred())
+
+		storage := resource.MustParse("10Gi")
+		q := &topolvmv1.TopoLVMQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: ns},
+			Spec: topolvmv1.TopoLVMQuotaSpec{
+				Limits: []topolvmv1.DeviceClassQuota{
+					{DeviceClass: "ssd", Storage: &storage},
+					{DeviceClass: "hdd", Storage: &storage},
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, q)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("reporting no usage")
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(q), q)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(q.Status.Used).To(HaveLen(2))
+			g.Expect(q.Status.Used[0].Volumes).To(BeEquivalentTo(0))
+		}).Should(Succeed())
+
+		By("creating a PVC")
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "pvc", Namespace: ns},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: &sc.Name,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: *resource.NewQuantity(1<<30, resource.BinarySI),
+					},
+				},
+			},
+		}
+		err = k8sClient.Create(ctx, pvc)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func(g Gomega) {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(q), q)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(q.Status.Used).To(HaveLen(2))
+			g.Expect(q.Status.Used[0].DeviceClass).To(Equal("ssd"))
+			g.Expect(q.Status.Used[0].Volumes).To(BeEquivalentTo(1))
+			g.Expect(q.Status.Used[0].Storage.Value()).To(BeEquivalentTo(1 << 30))
+			g.Expect(q.Status.Used[1].DeviceClass).To(Equal("hdd"))
+			g.Expect(q.Status.Used[1].Volumes).To(BeEquivalentTo(0))
+		}).Should(Succeed())
+	})
+})
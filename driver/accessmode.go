@@ -0,0 +1,58 @@
+package driver
+
+import (
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// isSupportedAccessMode returns true if TopoLVM supports the access mode.
+// A volume is accessible only from the node where it is created,
+// so MULTI_NODE_READER_ONLY is constrained to the node.
+func isSupportedAccessMode(mode csi.VolumeCapability_AccessMode_Mode) bool {
+	switch mode {
+	case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
+		return true
+	}
+	return false
+}
+
+// isReadOnlyAccessMode returns true if volumes of the access mode are always published as read-only.
+func isReadOnlyAccessMode(mode csi.VolumeCapability_AccessMode_Mode) bool {
+	return mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY ||
+		mode == csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY
+}
+
+// checkReadOnlyBlock returns an error if a block volume is requested to be read-only.
+// A device file without write permission does not prevent privileged containers,
+// which usually consume raw block volumes, from writing to the device,
+// so read-only block volumes are rejected rather than published as writable.
+func checkReadOnlyBlock(isBlock, readOnly bool) error {
+	if isBlock && readOnly {
+		return status.Error(codes.InvalidArgument, "read-only block volumes are not supported")
+	}
+	return nil
+}
+
+// checkPublishedTargets checks that the volume can be published at the path
+// in addition to the targets where it is already published.
+func checkPublishedTargets(targets []v1.PublishedTarget, path string, mode csi.VolumeCapability_AccessMode_Mode, readOnly bool) error {
+	for _, t := range targets {
+		if t.Path == path {
+			if t.ReadOnly != readOnly {
+				return status.Errorf(codes.AlreadyExists, "volume is already published at %s with readonly=%t", path, t.ReadOnly)
+			}
+			continue
+		}
+		// SINGLE_NODE_SINGLE_WRITER allows only one read/write publish at a time.
+		if mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER && !readOnly && !t.ReadOnly {
+			return status.Errorf(codes.FailedPrecondition, "volume is already published as read/write at %s", t.Path)
+		}
+	}
+	return nil
+}
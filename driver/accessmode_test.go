@@ -0,0 +1,97 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckPublishedTargets(t *testing.T) {
+	targets := []v1.PublishedTarget{
+		{Path: "/pod1"},
+		{Path: "/pod2", ReadOnly: true},
+	}
+
+	testCases := []struct {
+		name     string
+		targets  []v1.PublishedTarget
+		path     string
+		mode     csi.VolumeCapability_AccessMode_Mode
+		readOnly bool
+		code     codes.Code
+	}{
+		{
+			name: "first publish",
+			path: "/pod1",
+			mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			code: codes.OK,
+		},
+		{
+			name:    "same target",
+			targets: targets,
+			path:    "/pod1",
+			mode:    csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			code:    codes.OK,
+		},
+		{
+			name:     "same target with different readonly",
+			targets:  targets,
+			path:     "/pod1",
+			mode:     csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			readOnly: true,
+			code:     codes.AlreadyExists,
+		},
+		{
+			name:    "second writer of single writer",
+			targets: targets,
+			path:    "/pod3",
+			mode:    csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			code:    codes.FailedPrecondition,
+		},
+		{
+			name:     "reader of single writer",
+			targets:  targets,
+			path:     "/pod3",
+			mode:     csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			readOnly: true,
+			code:     codes.OK,
+		},
+		{
+			name:    "second writer of multi writer",
+			targets: targets,
+			path:    "/pod3",
+			mode:    csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+			code:    codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := checkPublishedTargets(tc.targets, tc.path, tc.mode, tc.readOnly)
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("%s: expected=%s actual=%s", tc.name, tc.code, code)
+		}
+	}
+}
+
+func TestCheckReadOnlyBlock(t *testing.T) {
+	testCases := []struct {
+		isBlock  bool
+		readOnly bool
+		code     codes.Code
+	}{
+		{isBlock: false, readOnly: false, code: codes.OK},
+		{isBlock: false, readOnly: true, code: codes.OK},
+		{isBlock: true, readOnly: false, code: codes.OK},
+		{isBlock: true, readOnly: true, code: codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		err := checkReadOnlyBlock(tc.isBlock, tc.readOnly)
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("block=%t readonly=%t: expected code %s, actual %s", tc.isBlock, tc.readOnly, tc.code, code)
+		}
+	}
+}
@@ -4,35 +4,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/topolvm/topolvm"
 	v1 "github.com/topolvm/topolvm/api/v1"
+	clientwrapper "github.com/topolvm/topolvm/client"
 	"github.com/topolvm/topolvm/driver/internal/k8s"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 var ctrlLogger = ctrl.Log.WithName("driver").WithName("controller")
 
+// ControllerServer implements the CSI controller service and group controller service.
+type ControllerServer interface {
+	csi.ControllerServer
+	csi.GroupControllerServer
+}
+
 // NewControllerServer returns a new ControllerServer.
-func NewControllerServer(mgr manager.Manager) (csi.ControllerServer, error) {
+func NewControllerServer(mgr manager.Manager) (ControllerServer, error) {
 	lvService, err := k8s.NewLogicalVolumeService(mgr)
 	if err != nil {
 		return nil, err
 	}
 
+	apiReader := clientwrapper.NewWrappedReader(mgr.GetAPIReader(), mgr.GetClient().Scheme())
 	return &controllerServer{
 		lockByName:     NewLockWithID(),
 		lockByVolumeID: NewLockWithID(),
 		server: &controllerServerNoLocked{
-			lvService:   lvService,
-			nodeService: k8s.NewNodeService(mgr.GetClient()),
+			lvService:       lvService,
+			nodeService:     k8s.NewNodeService(clientwrapper.NewWrappedClient(mgr.GetClient())),
+			capacityService: k8s.NewNodeService(apiReader),
+			apiReader:       apiReader,
+			lockByCapacity:  NewLockWithID(),
 		},
 	}, nil
 }
@@ -109,17 +126,26 @@ type controllerServerNoLocked struct {
 
 	lvService   *k8s.LogicalVolumeService
 	nodeService *k8s.NodeService
+	// capacityService reads the capacity and the reservations from the API server
+	// because the cache may not have LogicalVolumes created just before.
+	capacityService *k8s.NodeService
+	apiReader       client.Reader
+	// lockByCapacity serializes the capacity check and the creation of LogicalVolume
+	// for each node and device-class.
+	lockByCapacity *LockByID
 }
 
 func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
 	capabilities := req.GetVolumeCapabilities()
 	source := req.GetVolumeContentSource()
-	deviceClass := req.GetParameters()[topolvm.GetDeviceClassKey()]
+	// The device-classes are listed in priority order.
+	deviceClasses := topolvm.DeviceClasses(req.GetParameters()[topolvm.GetDeviceClassKey()])
+	deviceClass := deviceClasses[0]
 	lvcreateOptionClass := req.GetParameters()[topolvm.GetLvcreateOptionClassKey()]
 
 	ctrlLogger.Info("CreateVolume called",
 		"name", req.GetName(),
-		"device_class", deviceClass,
+		"device_class", deviceClasses,
 		"required", req.GetCapacityRange().GetRequiredBytes(),
 		"limit", req.GetCapacityRange().GetLimitBytes(),
 		"parameters", req.GetParameters(),
@@ -155,12 +181,12 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		if mode := capability.GetAccessMode(); mode != nil {
 			modeName := csi.VolumeCapability_AccessMode_Mode_name[int32(mode.GetMode())]
 			ctrlLogger.Info("CreateVolume specifies volume capability", "access_mode", modeName)
-			// we only support SINGLE_NODE_WRITER
-			switch mode.GetMode() {
-			case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:
-			default:
+			if !isSupportedAccessMode(mode.GetMode()) {
 				return nil, status.Errorf(codes.InvalidArgument, "unsupported access mode: %s", modeName)
 			}
+			if err := checkReadOnlyBlock(capability.GetBlock() != nil, isReadOnlyAccessMode(mode.GetMode())); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -169,6 +195,47 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	ioLimits, err := ioLimitsFromParameters(req.GetParameters())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The policy is recorded in LogicalVolume and applied when snapshots of the volume are taken.
+	annotations := make(map[string]string)
+	fsFreeze, err := policyFromParameters(req.GetParameters(), topolvm.GetFsFreezeKey(), fsFreezePolicies)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if fsFreeze != "" {
+		annotations[topolvm.GetFsFreezeKey()] = fsFreeze
+	}
+
+	// The filesystem options and the check policy are recorded in LogicalVolume and used when the volume is staged.
+	fsType := fsTypeOfCapabilities(capabilities)
+	mkfsOptions, mountOptions, err := fsOptionsFromParameters(req.GetParameters(), fsType)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fsck, err := policyFromParameters(req.GetParameters(), topolvm.GetFsckKey(), fsckPolicies)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if fsck != "" && fsType != "" {
+		annotations[topolvm.GetFsckKey()] = fsck
+	}
+
+	// The device-classes selected by labels are resolved after the node is decided.
+	var selector labels.Selector
+	if param, ok := req.GetParameters()[topolvm.GetDeviceClassSelectorKey()]; ok {
+		if _, ok := req.GetParameters()[topolvm.GetDeviceClassKey()]; ok {
+			return nil, status.Errorf(codes.InvalidArgument, "%s and %s cannot be specified at the same time", topolvm.GetDeviceClassKey(), topolvm.GetDeviceClassSelectorKey())
+		}
+		selector, err = labels.Parse(param)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", topolvm.GetDeviceClassSelectorKey(), err)
+		}
+	}
+
 	// check if the create volume request has a data source
 	if source != nil {
 		// get the source volumeID/snapshotID if exists
@@ -176,15 +243,14 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		if err != nil {
 			return nil, err
 		}
-		// check if the volume has the same size as the source volume.
-		// TODO (Yuggupta27): Allow user to create a volume with more size than that of the source volume.
+		// A volume larger than the source is created at the size of the source and expanded by topolvm-node.
 		sourceSizeGb := sourceVol.Spec.Size.Value() >> 30
-		if sourceSizeGb != requestGb {
-			return nil, status.Error(codes.OutOfRange, "requested size does not match the size of the source")
+		if requestGb < sourceSizeGb {
+			return nil, status.Error(codes.OutOfRange, "requested size is smaller than the size of the source")
 		}
 		// If a volume has a source, it has to provisioned on the same node and device class as the source volume.
 
-		if deviceClass != sourceVol.Spec.DeviceClass {
+		if selector == nil && !containsString(deviceClasses, sourceVol.Spec.DeviceClass) {
 			return nil, status.Error(codes.InvalidArgument, "device class mismatch. Snapshots should be created with the same device class as the source.")
 		}
 		deviceClass = sourceVol.Spec.DeviceClass
@@ -235,10 +301,23 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 			// - https://github.com/container-storage-interface/spec/blob/release-1.1/spec.md#createvolume
 			// - https://github.com/kubernetes-csi/csi-test/blob/6738ab2206eac88874f0a3ede59b40f680f59f43/pkg/sanity/controller.go#L404-L428
 			ctrlLogger.Info("decide node because accessibility_requirements not found")
-			nodeName, capacity, err := s.nodeService.GetMaxCapacity(ctx, deviceClass)
-
-			if err != nil {
-				return nil, status.Errorf(codes.Internal, "failed to get max capacity node %v", err)
+			var nodeName string
+			var capacity int64
+			if selector != nil {
+				nodeName, capacity, err = s.nodeService.GetMaxCapacityBySelector(ctx, selector)
+				if err != nil {
+					return nil, status.Errorf(codes.Internal, "failed to get max capacity node %v", err)
+				}
+			} else {
+				for _, dc := range deviceClasses {
+					nodeName, capacity, err = s.nodeService.GetMaxCapacity(ctx, dc)
+					if err != nil {
+						return nil, status.Errorf(codes.Internal, "failed to get max capacity node %v", err)
+					}
+					if nodeName != "" && capacity >= (requestGb<<30) {
+						break
+					}
+				}
 			}
 			if nodeName == "" {
 				return nil, status.Error(codes.Internal, "can not find any node")
@@ -268,6 +347,22 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		}
 	}
 
+	if selector != nil {
+		deviceClasses, err = s.nodeService.GetDeviceClassesBySelector(ctx, node, selector)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to select device-classes on node %s: %v", node, err)
+		}
+		if len(deviceClasses) == 0 {
+			return nil, status.Errorf(codes.ResourceExhausted, "no device-class matches %q on node %s", selector.String(), node)
+		}
+		if source != nil && !containsString(deviceClasses, sourceVol.Spec.DeviceClass) {
+			return nil, status.Error(codes.InvalidArgument, "device class mismatch. Snapshots should be created with the same device class as the source.")
+		}
+		if source == nil {
+			deviceClass = deviceClasses[0]
+		}
+	}
+
 	name := req.GetName()
 	if name == "" {
 		return nil, status.Error(codes.InvalidArgument, "invalid name")
@@ -275,7 +370,68 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 
 	name = strings.ToLower(name)
 
-	volumeID, err := s.lvService.CreateVolume(ctx, node, deviceClass, lvcreateOptionClass, name, sourceName, requestGb)
+	requestBytes := req.GetCapacityRange().GetRequiredBytes()
+	if requestBytes == 0 {
+		requestBytes = requestGb << 30
+	}
+
+	// Thin snapshots do not consume the VG free space.
+	unlockCapacity := func() {}
+	if sourceName == "" {
+		// Concurrent requests for the same node and device-class have to see the reservation
+		// of each other, so the lock is held until the LogicalVolume is created.
+		candidates := []string{deviceClass}
+		if len(deviceClasses) > 1 {
+			candidates = deviceClasses
+		}
+		unlockCapacity = s.lockCapacity(node, candidates)
+		defer unlockCapacity()
+
+		if len(deviceClasses) > 1 {
+			deviceClass, err = s.selectDeviceClass(ctx, node, deviceClasses, name, requestGb<<30)
+		} else {
+			err = s.checkNodeCapacity(ctx, node, deviceClass, name, requestGb<<30)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = checkQuota(ctx, s.apiReader, req.GetParameters()[pvcNamespaceKey], req.GetParameters()[pvcNameKey], deviceClass, requestBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The defaults of the device-class are used for the options not specified in StorageClass.
+	if fsType != "" && (mkfsOptions == "" || mountOptions == "") {
+		defaultMkfsOptions, defaultMountOptions, err := s.nodeService.GetDefaultFilesystemOptions(ctx, node, deviceClass, fsType)
+		if err != nil && err != k8s.ErrNodeNotFound {
+			return nil, status.Errorf(codes.Internal, "failed to get default filesystem options: %v", err)
+		}
+		defaultMkfsOptions, defaultMountOptions, err = normalizeFsOptions(fsType, defaultMkfsOptions, defaultMountOptions)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "invalid default filesystem options of device-class %s on node %s: %v", deviceClass, node, err)
+		}
+		if mkfsOptions == "" {
+			mkfsOptions = defaultMkfsOptions
+		}
+		if mountOptions == "" {
+			mountOptions = defaultMountOptions
+		}
+	}
+	if mkfsOptions != "" {
+		annotations[topolvm.GetMkfsOptionsKey()] = mkfsOptions
+	}
+	if mountOptions != "" {
+		annotations[topolvm.GetMountOptionsKey()] = mountOptions
+	}
+
+	var volumeID string
+	err = s.lvService.CreateLogicalVolume(ctx, node, deviceClass, lvcreateOptionClass, name, sourceName, requestGb, annotations)
+	if err == nil {
+		unlockCapacity()
+		volumeID, err = s.lvService.WaitForVolumeID(ctx, name)
+	}
 	if err != nil {
 		_, ok := status.FromError(err)
 		if !ok {
@@ -288,6 +444,9 @@ func (s controllerServerNoLocked) CreateVolume(ctx context.Context, req *csi.Cre
 		Volume: &csi.Volume{
 			CapacityBytes: requestGb << 30,
 			VolumeId:      volumeID,
+			// The I/O limits are passed to NodePublishVolume through the volume context
+			// because it is stored in PersistentVolume and given on every publish.
+			VolumeContext: ioLimitsToVolumeContext(ioLimits),
 			ContentSource: source,
 			AccessibleTopology: []*csi.Topology{
 				{
@@ -364,6 +523,18 @@ func (s controllerServerNoLocked) CreateSnapshot(ctx context.Context, req *csi.C
 		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	// The policy in VolumeSnapshotClass takes precedence over the one in StorageClass of the source.
+	fsFreeze, err := policyFromParameters(req.GetParameters(), topolvm.GetFsFreezeKey(), fsFreezePolicies)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if fsFreeze == "" {
+		fsFreeze = sourceVol.Annotations[topolvm.GetFsFreezeKey()]
+	}
+	var annotations map[string]string
+	if fsFreeze != "" {
+		annotations = map[string]string{topolvm.GetFsFreezeKey(): fsFreeze}
+	}
 	snapTimeStamp := &timestamp.Timestamp{
 		Seconds: time.Now().Unix(),
 		Nanos:   0,
@@ -373,7 +544,7 @@ func (s controllerServerNoLocked) CreateSnapshot(ctx context.Context, req *csi.C
 	deviceClass := sourceVol.Spec.DeviceClass
 	size := sourceVol.Spec.Size
 	sourceVolName := sourceVol.Spec.Name
-	snapshotID, err := s.lvService.CreateSnapshot(ctx, node, deviceClass, sourceVolName, name, accessType, size)
+	snapshotID, err := s.lvService.CreateSnapshot(ctx, node, deviceClass, sourceVolName, name, accessType, size, annotations)
 	if err != nil {
 		_, ok := status.FromError(err)
 		if !ok {
@@ -435,6 +606,93 @@ func convertRequestCapacity(requestBytes, limitBytes int64) (int64, error) {
 	return (requestBytes-1)>>30 + 1, nil
 }
 
+// checkNodeCapacity checks that the node has enough space for a new volume.
+// The space reserved for other in-flight LogicalVolumes is subtracted from the capacity, so
+// volumes scheduled onto the same node simultaneously cannot use the same space.
+// ResourceExhausted lets the external-provisioner reschedule the pod.
+func (s controllerServerNoLocked) checkNodeCapacity(ctx context.Context, node, deviceClass, name string, requestBytes int64) error {
+	// The LogicalVolume already exists when the request is retried.
+	var lv v1.LogicalVolume
+	err := s.apiReader.Get(ctx, client.ObjectKey{Name: name}, &lv)
+	switch {
+	case err == nil:
+		return nil
+	case !apierrors.IsNotFound(err):
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	capacity, err := s.capacityService.GetAvailableCapacity(ctx, node, deviceClass, name)
+	if err != nil {
+		// lvmd reports the error if the node or the device-class is missing.
+		ctrlLogger.Info("failed to get the capacity of the node", "node", node, "device_class", deviceClass, "error", err.Error())
+		return nil
+	}
+	if capacity < requestBytes {
+		return status.Errorf(codes.ResourceExhausted, "not enough space on node %s: requested=%d available=%d", node, requestBytes, capacity)
+	}
+	return nil
+}
+
+// selectDeviceClass returns the first device-class that has enough space for a new volume on the node.
+// If the LogicalVolume already exists because the request is retried, its device-class is returned.
+func (s controllerServerNoLocked) selectDeviceClass(ctx context.Context, node string, deviceClasses []string, name string, requestBytes int64) (string, error) {
+	var lv v1.LogicalVolume
+	err := s.apiReader.Get(ctx, client.ObjectKey{Name: name}, &lv)
+	switch {
+	case err == nil:
+		return lv.Spec.DeviceClass, nil
+	case !apierrors.IsNotFound(err):
+		return "", status.Error(codes.Internal, err.Error())
+	}
+
+	for _, dc := range deviceClasses {
+		capacity, err := s.capacityService.GetAvailableCapacity(ctx, node, dc, name)
+		if err != nil {
+			// the device-class may not exist on the node.
+			ctrlLogger.Info("failed to get the capacity of the node", "node", node, "device_class", dc, "error", err.Error())
+			continue
+		}
+		if capacity >= requestBytes {
+			return dc, nil
+		}
+	}
+	return "", status.Errorf(codes.ResourceExhausted, "not enough space on node %s in any of device-classes %v: requested=%d", node, deviceClasses, requestBytes)
+}
+
+// lockCapacity takes the locks for the device-classes on the node in order to avoid deadlocks.
+// The returned function releases the locks, and can be called more than once.
+func (s controllerServerNoLocked) lockCapacity(node string, deviceClasses []string) func() {
+	keys := make([]string, 0, len(deviceClasses))
+	for _, dc := range deviceClasses {
+		key := node + "/" + dc
+		if !containsString(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s.lockByCapacity.LockByID(key)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for _, key := range keys {
+				s.lockByCapacity.UnlockByID(key)
+			}
+		})
+	}
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (s controllerServerNoLocked) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
 	ctrlLogger.Info("DeleteVolume called",
 		"volume_id", req.GetVolumeId(),
@@ -479,6 +737,19 @@ func (s controllerServerNoLocked) ValidateVolumeCapabilities(ctx context.Context
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	for _, capability := range req.GetVolumeCapabilities() {
+		if mode := capability.GetAccessMode(); mode != nil && !isSupportedAccessMode(mode.GetMode()) {
+			return &csi.ValidateVolumeCapabilitiesResponse{
+				Message: "unsupported access mode: " + mode.GetMode().String(),
+			}, nil
+		}
+		if err := checkReadOnlyBlock(capability.GetBlock() != nil, isReadOnlyAccessMode(capability.GetAccessMode().GetMode())); err != nil {
+			return &csi.ValidateVolumeCapabilitiesResponse{
+				Message: status.Convert(err).Message(),
+			}, nil
+		}
+	}
+
 	// Since TopoLVM does not provide means to pre-provision volumes,
 	// any existing volume is valid.
 	return &csi.ValidateVolumeCapabilitiesResponse{
@@ -501,15 +772,31 @@ func (s controllerServerNoLocked) GetCapacity(ctx context.Context, req *csi.GetC
 		ctrlLogger.Info("capability argument is not nil, but TopoLVM ignores it")
 	}
 
-	deviceClass := req.GetParameters()[topolvm.GetDeviceClassKey()]
+	// The capacity of the device-classes listed in a StorageClass is the sum of them,
+	// while a volume is created in one of them.
+	deviceClasses := topolvm.DeviceClasses(req.GetParameters()[topolvm.GetDeviceClassKey()])
+
+	if param, ok := req.GetParameters()[topolvm.GetDeviceClassSelectorKey()]; ok {
+		return s.getCapacityBySelector(ctx, req, param)
+	}
 
-	var capacity int64
+	var capacity, maxVolumeSize int64
 	switch topology {
 	case nil:
-		var err error
-		capacity, err = s.nodeService.GetTotalCapacity(ctx, deviceClass)
-		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
+		for _, dc := range deviceClasses {
+			c, err := s.nodeService.GetTotalCapacity(ctx, dc)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			capacity += c
+			// a volume cannot span nodes.
+			_, m, err := s.nodeService.GetMaxCapacity(ctx, dc)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			if m > maxVolumeSize {
+				maxVolumeSize = m
+			}
 		}
 	default:
 		v, ok := topology.Segments[topolvm.GetTopologyNodeKey()]
@@ -518,23 +805,64 @@ func (s controllerServerNoLocked) GetCapacity(ctx context.Context, req *csi.GetC
 			ctrlLogger.Error(err, "target node key is not found")
 			return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
 		}
-		var err error
-		capacity, err = s.nodeService.GetCapacityByTopologyLabel(ctx, v, deviceClass)
-		switch err {
-		case k8s.ErrNodeNotFound:
-			ctrlLogger.Info("target is not found", "accessible_topology", req.AccessibleTopology)
-			return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
-		case k8s.ErrDeviceClassNotFound:
-			ctrlLogger.Info("target device class is not found on the specified node", "accessible_topology", req.AccessibleTopology, "device-class", deviceClass)
+		for _, dc := range deviceClasses {
+			c, err := s.nodeService.GetCapacityByTopologyLabel(ctx, v, dc)
+			switch err {
+			case k8s.ErrNodeNotFound:
+				ctrlLogger.Info("target is not found", "accessible_topology", req.AccessibleTopology)
+				return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
+			case k8s.ErrDeviceClassNotFound:
+				ctrlLogger.Info("target device class is not found on the specified node", "accessible_topology", req.AccessibleTopology, "device-class", dc)
+				continue
+			case nil:
+			default:
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			capacity += c
+			// the free space is overprovisioned for thin pools, so a volume can use up all of it.
+			if c > maxVolumeSize {
+				maxVolumeSize = c
+			}
+		}
+	}
+
+	return &csi.GetCapacityResponse{
+		AvailableCapacity: capacity,
+		MaximumVolumeSize: &wrappers.Int64Value{Value: maxVolumeSize},
+	}, nil
+}
+
+// getCapacityBySelector returns the capacity of the device-classes selected by labels.
+func (s controllerServerNoLocked) getCapacityBySelector(ctx context.Context, req *csi.GetCapacityRequest, param string) (*csi.GetCapacityResponse, error) {
+	selector, err := labels.Parse(param)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s: %v", topolvm.GetDeviceClassSelectorKey(), err)
+	}
+
+	var node string
+	if topology := req.GetAccessibleTopology(); topology != nil {
+		v, ok := topology.Segments[topolvm.GetTopologyNodeKey()]
+		if !ok {
+			err := fmt.Errorf("%s is not found in req.AccessibleTopology", topolvm.GetTopologyNodeKey())
+			ctrlLogger.Error(err, "target node key is not found")
 			return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
-		case nil:
-		default:
-			return nil, status.Error(codes.Internal, err.Error())
 		}
+		node = v
+	}
+
+	capacity, maxVolumeSize, err := s.nodeService.GetCapacityBySelector(ctx, node, selector)
+	switch err {
+	case k8s.ErrNodeNotFound:
+		ctrlLogger.Info("target is not found", "accessible_topology", req.AccessibleTopology)
+		return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
+	case nil:
+	default:
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	return &csi.GetCapacityResponse{
 		AvailableCapacity: capacity,
+		MaximumVolumeSize: &wrappers.Int64Value{Value: maxVolumeSize},
 	}, nil
 }
 
@@ -545,6 +873,12 @@ func (s controllerServerNoLocked) ControllerGetCapabilities(context.Context, *cs
 		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 	}
 
 	csiCaps := make([]*csi.ControllerServiceCapability, len(capabilities))
@@ -603,6 +937,19 @@ func (s controllerServerNoLocked) ControllerExpandVolume(ctx context.Context, re
 			NodeExpansionRequired: true,
 		}, nil
 	}
+	pvcNamespace, pvcName, err := boundPVC(ctx, s.apiReader, lv.Spec.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	requestBytes := req.GetCapacityRange().GetRequiredBytes()
+	if requestBytes == 0 {
+		requestBytes = requestGb << 30
+	}
+	err = checkQuota(ctx, s.apiReader, pvcNamespace, pvcName, lv.Spec.DeviceClass, requestBytes)
+	if err != nil {
+		return nil, err
+	}
+
 	capacity, err := s.nodeService.GetCapacityByName(ctx, lv.Spec.NodeName, lv.Spec.DeviceClass)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
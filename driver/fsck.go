@@ -0,0 +1,89 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/topolvm/topolvm"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/filesystem"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxEventMessageLength is the maximum length of event messages accepted by the API server.
+const maxEventMessageLength = 1024
+
+// checkFilesystem checks the filesystem on the device before it is mounted according to the policy recorded in LogicalVolume.
+// The output of fsck is recorded as an event of the PVC if the filesystem is repaired or needs manual repair.
+func (s *nodeServerNoLocked) checkFilesystem(ctx context.Context, lvr *v1.LogicalVolume, device, fsType string) error {
+	volumeID := lvr.Status.VolumeID
+	policy := lvr.Annotations[topolvm.GetFsckKey()]
+	if policy == "" {
+		policy = topolvm.FsckPreen
+	}
+	if policy == topolvm.FsckNever {
+		return nil
+	}
+
+	full := false
+	if policy == topolvm.FsckFullIfDirty {
+		dirty, err := filesystem.IsDirty(device, fsType)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to check whether filesystem is dirty: volume=%s, error=%v", volumeID, err)
+		}
+		full = dirty
+	}
+
+	nodeLogger.Info("checking filesystem",
+		"volume_id", volumeID,
+		"fstype", fsType,
+		"full", full)
+	result, err := filesystem.Check(device, full)
+	switch {
+	case errors.Is(err, filesystem.ErrNeedsRepair):
+		nodeLogger.Error(err, "filesystem needs manual repair", "volume_id", volumeID, "output", result.Output)
+		s.recordPVCEvent(ctx, lvr, corev1.EventTypeWarning, "FilesystemNeedsRepair",
+			fmt.Sprintf("fsck found errors that cannot be repaired automatically on %s: %s", volumeID, result.Output))
+		return status.Errorf(codes.FailedPrecondition, "filesystem of volume %s has errors that need manual repair with fsck on node %s", volumeID, s.nodeName)
+	case err != nil:
+		return status.Errorf(codes.Internal, "filesystem check failed: volume=%s, error=%v", volumeID, err)
+	}
+
+	if result.Repaired {
+		nodeLogger.Info("filesystem is repaired", "volume_id", volumeID, "output", result.Output)
+		s.recordPVCEvent(ctx, lvr, corev1.EventTypeWarning, "FilesystemRepaired",
+			fmt.Sprintf("fsck repaired errors on %s: %s", volumeID, result.Output))
+	} else if full {
+		s.recordPVCEvent(ctx, lvr, corev1.EventTypeNormal, "FilesystemChecked",
+			fmt.Sprintf("fsck checked %s marked dirty: %s", volumeID, result.Output))
+	}
+	return nil
+}
+
+// recordPVCEvent records an event of the PVC bound to the volume.
+// Failures are only logged because events are informational.
+func (s *nodeServerNoLocked) recordPVCEvent(ctx context.Context, lvr *v1.LogicalVolume, eventType, reason, message string) {
+	namespace, name, err := boundPVC(ctx, s.apiReader, lvr.Spec.Name)
+	if err != nil {
+		nodeLogger.Error(err, "failed to get PersistentVolume", "name", lvr.Spec.Name)
+		return
+	}
+	if name == "" {
+		return
+	}
+	var pvc corev1.PersistentVolumeClaim
+	if err := s.apiReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &pvc); err != nil {
+		nodeLogger.Error(err, "failed to get PersistentVolumeClaim", "namespace", namespace, "name", name)
+		return
+	}
+
+	// Keep the tail of the message because fsck reports the summary at the end.
+	if len(message) > maxEventMessageLength {
+		message = "..." + message[len(message)-maxEventMessageLength+3:]
+	}
+	s.recorder.Event(&pvc, eventType, reason, message)
+}
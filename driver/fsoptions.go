@@ -0,0 +1,61 @@
+package driver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/filesystem"
+)
+
+// defaultFsType is the filesystem type used if it is not specified in the volume capability.
+const defaultFsType = "ext4"
+
+// fsTypeOfCapabilities returns the filesystem type of the mount capability.
+// It returns an empty string if the volume is requested as a block volume.
+func fsTypeOfCapabilities(capabilities []*csi.VolumeCapability) string {
+	for _, capability := range capabilities {
+		if mount := capability.GetMount(); mount != nil {
+			if mount.GetFsType() == "" {
+				return defaultFsType
+			}
+			return mount.GetFsType()
+		}
+	}
+	return ""
+}
+
+// normalizeFsOptions validates the mkfs options separated by spaces and the mount options separated by commas,
+// and returns them in the form recorded in LogicalVolume annotations.
+func normalizeFsOptions(fsType, mkfsOptions, mountOptions string) (string, string, error) {
+	mkfsArgs, err := filesystem.ParseMkfsOptions(fsType, mkfsOptions)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid %s: %w", topolvm.GetMkfsOptionsKey(), err)
+	}
+	mountArgs, err := filesystem.ParseMountOptions(mountOptions)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid %s: %w", topolvm.GetMountOptionsKey(), err)
+	}
+	return strings.Join(mkfsArgs, " "), strings.Join(mountArgs, ","), nil
+}
+
+// fsOptionsFromParameters returns the mkfs and mount options in StorageClass parameters.
+// The options are ignored for block volumes because a StorageClass can be used for both block and filesystem volumes.
+func fsOptionsFromParameters(params map[string]string, fsType string) (string, string, error) {
+	if fsType == "" {
+		return "", "", nil
+	}
+	return normalizeFsOptions(fsType, params[topolvm.GetMkfsOptionsKey()], params[topolvm.GetMountOptionsKey()])
+}
+
+// mkfsArgs returns the arguments of mkfs to format the device forcibly with the options.
+// mke2fs for ext2, ext3 and ext4 takes -F to force formatting, while the others take -f.
+func mkfsArgs(device, fsType string, options []string) []string {
+	args := []string{"-f"}
+	if strings.HasPrefix(fsType, "ext") {
+		args = []string{"-F"}
+	}
+	args = append(args, options...)
+	return append(args, device)
+}
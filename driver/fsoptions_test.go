@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/topolvm/topolvm"
+)
+
+func TestFsTypeOfCapabilities(t *testing.T) {
+	block := &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}}
+	mount := func(fsType string) *csi.VolumeCapability {
+		return &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: fsType}}}
+	}
+
+	testCases := []struct {
+		name         string
+		capabilities []*csi.VolumeCapability
+		expected     string
+	}{
+		{name: "block", capabilities: []*csi.VolumeCapability{block}, expected: ""},
+		{name: "default", capabilities: []*csi.VolumeCapability{mount("")}, expected: "ext4"},
+		{name: "xfs", capabilities: []*csi.VolumeCapability{mount("xfs")}, expected: "xfs"},
+	}
+
+	for _, tc := range testCases {
+		if fsType := fsTypeOfCapabilities(tc.capabilities); fsType != tc.expected {
+			t.Errorf("%s: expected=%q actual=%q", tc.name, tc.expected, fsType)
+		}
+	}
+}
+
+func TestFsOptionsFromParameters(t *testing.T) {
+	testCases := []struct {
+		name   string
+		params map[string]string
+		fsType string
+		mkfs   string
+		mount  string
+		hasErr bool
+	}{
+		{
+			name:   "no options",
+			params: map[string]string{},
+			fsType: "ext4",
+		},
+		{
+			name: "normalized",
+			params: map[string]string{
+				topolvm.GetMkfsOptionsKey():  " -E  lazy_itable_init=0 -m 0 ",
+				topolvm.GetMountOptionsKey(): "discard, noatime",
+			},
+			fsType: "ext4",
+			mkfs:   "-E lazy_itable_init=0 -m 0",
+			mount:  "discard,noatime",
+		},
+		{
+			name:   "block volume",
+			params: map[string]string{topolvm.GetMkfsOptionsKey(): "-m reflink=1"},
+			fsType: "",
+		},
+		{
+			name:   "invalid mkfs options",
+			params: map[string]string{topolvm.GetMkfsOptionsKey(): "-m reflink=1 -f"},
+			fsType: "xfs",
+			hasErr: true,
+		},
+		{
+			name:   "invalid mount options",
+			params: map[string]string{topolvm.GetMountOptionsKey(): "noatime,ro"},
+			fsType: "xfs",
+			hasErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		mkfs, mount, err := fsOptionsFromParameters(tc.params, tc.fsType)
+		if tc.hasErr {
+			if err == nil {
+				t.Errorf("%s: error is expected", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if mkfs != tc.mkfs || mount != tc.mount {
+			t.Errorf("%s: expected=(%q, %q) actual=(%q, %q)", tc.name, tc.mkfs, tc.mount, mkfs, mount)
+		}
+	}
+}
+
+func TestMkfsArgs(t *testing.T) {
+	testCases := []struct {
+		fsType   string
+		options  []string
+		expected []string
+	}{
+		{"ext3", []string{"-b", "4096"}, []string{"-F", "-b", "4096", "/dev/topolvm/vol"}},
+		{"ext4", []string{"-m", "0"}, []string{"-F", "-m", "0", "/dev/topolvm/vol"}},
+		{"xfs", []string{"-m", "reflink=1"}, []string{"-f", "-m", "reflink=1", "/dev/topolvm/vol"}},
+		{"btrfs", nil, []string{"-f", "/dev/topolvm/vol"}},
+		{"f2fs", []string{"-O", "extra_attr"}, []string{"-f", "-O", "extra_attr", "/dev/topolvm/vol"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.fsType, func(t *testing.T) {
+			actual := mkfsArgs("/dev/topolvm/vol", tc.fsType, tc.options)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected %v, actual %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/driver/internal/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func (s *controllerServer) GroupControllerGetCapabilities(ctx context.Context, req *csi.GroupControllerGetCapabilitiesRequest) (*csi.GroupControllerGetCapabilitiesResponse, error) {
+	// This returns constants only, it is unnecessary to take lock.
+	return s.server.GroupControllerGetCapabilities(ctx, req)
+}
+
+func (s *controllerServer) CreateVolumeGroupSnapshot(ctx context.Context, req *csi.CreateVolumeGroupSnapshotRequest) (*csi.CreateVolumeGroupSnapshotResponse, error) {
+	s.lockByName.LockByID(req.GetName())
+	defer s.lockByName.UnlockByID(req.GetName())
+
+	return s.server.CreateVolumeGroupSnapshot(ctx, req)
+}
+
+func (s *controllerServer) DeleteVolumeGroupSnapshot(ctx context.Context, req *csi.DeleteVolumeGroupSnapshotRequest) (*csi.DeleteVolumeGroupSnapshotResponse, error) {
+	s.lockByVolumeID.LockByID(req.GetGroupSnapshotId())
+	defer s.lockByVolumeID.UnlockByID(req.GetGroupSnapshotId())
+
+	return s.server.DeleteVolumeGroupSnapshot(ctx, req)
+}
+
+func (s *controllerServer) GetVolumeGroupSnapshot(ctx context.Context, req *csi.GetVolumeGroupSnapshotRequest) (*csi.GetVolumeGroupSnapshotResponse, error) {
+	s.lockByVolumeID.LockByID(req.GetGroupSnapshotId())
+	defer s.lockByVolumeID.UnlockByID(req.GetGroupSnapshotId())
+
+	return s.server.GetVolumeGroupSnapshot(ctx, req)
+}
+
+func (s controllerServerNoLocked) GroupControllerGetCapabilities(context.Context, *csi.GroupControllerGetCapabilitiesRequest) (*csi.GroupControllerGetCapabilitiesResponse, error) {
+	return &csi.GroupControllerGetCapabilitiesResponse{
+		Capabilities: []*csi.GroupControllerServiceCapability{
+			{
+				Type: &csi.GroupControllerServiceCapability_Rpc{
+					Rpc: &csi.GroupControllerServiceCapability_RPC{
+						Type: csi.GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// CreateVolumeGroupSnapshot creates snapshots of multiple volumes at the same instant.
+// The group snapshot ID is the lowercased name, which is also set to the label of the LogicalVolumes.
+func (s controllerServerNoLocked) CreateVolumeGroupSnapshot(ctx context.Context, req *csi.CreateVolumeGroupSnapshotRequest) (*csi.CreateVolumeGroupSnapshotResponse, error) {
+	// Since the kubernetes snapshots are Read-Only, we set accessType as 'ro' to activate thin-snapshots as read-only volumes
+	accessType := "ro"
+
+	ctrlLogger.Info("CreateVolumeGroupSnapshot called",
+		"name", req.GetName(),
+		"source_volume_ids", req.GetSourceVolumeIds(),
+		"parameters", req.GetParameters(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing name")
+	}
+	if len(req.GetSourceVolumeIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing source volume ids")
+	}
+
+	group := strings.ToLower(req.GetName())
+	if errs := validation.IsValidLabelValue(group); len(errs) != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid name: %s", strings.Join(errs, ", "))
+	}
+
+	sources := make([]*v1.LogicalVolume, len(req.GetSourceVolumeIds()))
+	seen := make(map[string]bool)
+	for i, sourceVolID := range req.GetSourceVolumeIds() {
+		if seen[sourceVolID] {
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate source volume id %s", sourceVolID)
+		}
+		seen[sourceVolID] = true
+
+		sourceVol, err := s.lvService.GetVolume(ctx, sourceVolID)
+		if err != nil {
+			if errors.Is(err, k8s.ErrVolumeNotFound) {
+				return nil, status.Errorf(codes.NotFound, "failed to find source volume %s", sourceVolID)
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		// the snapshots are taken at once on the node, so they are required to be in the same node and device class.
+		if i > 0 && (sourceVol.Spec.NodeName != sources[0].Spec.NodeName || sourceVol.Spec.DeviceClass != sources[0].Spec.DeviceClass) {
+			return nil, status.Error(codes.InvalidArgument, "source volumes should be in the same node and device class")
+		}
+		sources[i] = sourceVol
+	}
+
+	err := s.lvService.CreateGroupSnapshot(ctx, group, accessType, sources)
+	if err != nil {
+		_, ok := status.FromError(err)
+		if !ok {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, err
+	}
+
+	lvs, err := s.lvService.GetGroupSnapshot(ctx, group)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	groupSnapshot, err := s.volumeGroupSnapshot(ctx, group, lvs)
+	if err != nil {
+		return nil, err
+	}
+	return &csi.CreateVolumeGroupSnapshotResponse{
+		GroupSnapshot: groupSnapshot,
+	}, nil
+}
+
+// DeleteVolumeGroupSnapshot deletes all the snapshots of the volume group snapshot.
+func (s controllerServerNoLocked) DeleteVolumeGroupSnapshot(ctx context.Context, req *csi.DeleteVolumeGroupSnapshotRequest) (*csi.DeleteVolumeGroupSnapshotResponse, error) {
+	ctrlLogger.Info("DeleteVolumeGroupSnapshot called",
+		"group_snapshot_id", req.GetGroupSnapshotId(),
+		"snapshot_ids", req.GetSnapshotIds(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetGroupSnapshotId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing group snapshot id")
+	}
+
+	lvs, err := s.lvService.GetGroupSnapshot(ctx, req.GetGroupSnapshotId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(lvs) == 0 {
+		ctrlLogger.Info("group snapshot is not found", "group_snapshot_id", req.GetGroupSnapshotId())
+		return &csi.DeleteVolumeGroupSnapshotResponse{}, nil
+	}
+	if err := checkGroupSnapshotIDs(lvs, req.GetSnapshotIds()); err != nil {
+		return nil, err
+	}
+
+	if err := s.lvService.DeleteGroupSnapshot(ctx, req.GetGroupSnapshotId()); err != nil {
+		ctrlLogger.Error(err, "DeleteVolumeGroupSnapshot failed", "group_snapshot_id", req.GetGroupSnapshotId())
+		_, ok := status.FromError(err)
+		if !ok {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, err
+	}
+
+	return &csi.DeleteVolumeGroupSnapshotResponse{}, nil
+}
+
+// GetVolumeGroupSnapshot returns the volume group snapshot.
+func (s controllerServerNoLocked) GetVolumeGroupSnapshot(ctx context.Context, req *csi.GetVolumeGroupSnapshotRequest) (*csi.GetVolumeGroupSnapshotResponse, error) {
+	ctrlLogger.Info("GetVolumeGroupSnapshot called",
+		"group_snapshot_id", req.GetGroupSnapshotId(),
+		"snapshot_ids", req.GetSnapshotIds(),
+		"num_secrets", len(req.GetSecrets()))
+
+	if req.GetGroupSnapshotId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing group snapshot id")
+	}
+
+	lvs, err := s.lvService.GetGroupSnapshot(ctx, req.GetGroupSnapshotId())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(lvs) == 0 {
+		return nil, status.Errorf(codes.NotFound, "group snapshot %s is not found", req.GetGroupSnapshotId())
+	}
+	if err := checkGroupSnapshotIDs(lvs, req.GetSnapshotIds()); err != nil {
+		return nil, err
+	}
+
+	groupSnapshot, err := s.volumeGroupSnapshot(ctx, req.GetGroupSnapshotId(), lvs)
+	if err != nil {
+		return nil, err
+	}
+	return &csi.GetVolumeGroupSnapshotResponse{
+		GroupSnapshot: groupSnapshot,
+	}, nil
+}
+
+// volumeGroupSnapshot converts the LogicalVolumes of the volume group snapshot to csi.VolumeGroupSnapshot.
+func (s controllerServerNoLocked) volumeGroupSnapshot(ctx context.Context, group string, lvs []v1.LogicalVolume) (*csi.VolumeGroupSnapshot, error) {
+	groupSnapshot := &csi.VolumeGroupSnapshot{
+		GroupSnapshotId: group,
+		ReadyToUse:      true,
+	}
+	for _, lv := range lvs {
+		sourceVol, err := s.lvService.GetVolumeByName(ctx, lv.Spec.Source)
+		if err != nil {
+			if errors.Is(err, k8s.ErrVolumeNotFound) {
+				return nil, status.Errorf(codes.NotFound, "failed to find source volume of %s", lv.Name)
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		creationTime := timestamppb.New(lv.CreationTimestamp.Time)
+		readyToUse := lv.Status.VolumeID != ""
+		groupSnapshot.Snapshots = append(groupSnapshot.Snapshots, &csi.Snapshot{
+			SnapshotId:      lv.Status.VolumeID,
+			SourceVolumeId:  sourceVol.Status.VolumeID,
+			SizeBytes:       lv.Spec.Size.Value(),
+			CreationTime:    creationTime,
+			ReadyToUse:      readyToUse,
+			GroupSnapshotId: group,
+		})
+		if groupSnapshot.CreationTime == nil || creationTime.AsTime().Before(groupSnapshot.CreationTime.AsTime()) {
+			groupSnapshot.CreationTime = creationTime
+		}
+		groupSnapshot.ReadyToUse = groupSnapshot.ReadyToUse && readyToUse
+	}
+	return groupSnapshot, nil
+}
+
+// checkGroupSnapshotIDs checks that the snapshots are in the volume group snapshot.
+func checkGroupSnapshotIDs(lvs []v1.LogicalVolume, snapshotIDs []string) error {
+	ids := make(map[string]bool)
+	for _, lv := range lvs {
+		ids[lv.Status.VolumeID] = true
+	}
+	for _, id := range snapshotIDs {
+		if !ids[id] {
+			return status.Errorf(codes.InvalidArgument, "snapshot %s is not in the group snapshot", id)
+		}
+	}
+	return nil
+}
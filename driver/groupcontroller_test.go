@@ -0,0 +1,49 @@
+package driver
+
+import (
+	"testing"
+
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckGroupSnapshotIDs(t *testing.T) {
+	lvs := []v1.LogicalVolume{
+		{Status: v1.LogicalVolumeStatus{VolumeID: "snap1"}},
+		{Status: v1.LogicalVolumeStatus{VolumeID: "snap2"}},
+	}
+
+	testCases := []struct {
+		name        string
+		snapshotIDs []string
+		code        codes.Code
+	}{
+		{
+			name: "no snapshot ids",
+			code: codes.OK,
+		},
+		{
+			name:        "all snapshots",
+			snapshotIDs: []string{"snap1", "snap2"},
+			code:        codes.OK,
+		},
+		{
+			name:        "some snapshots",
+			snapshotIDs: []string{"snap2"},
+			code:        codes.OK,
+		},
+		{
+			name:        "snapshot of another group",
+			snapshotIDs: []string{"snap1", "snap3"},
+			code:        codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := checkGroupSnapshotIDs(lvs, tc.snapshotIDs)
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("%s: expected=%s actual=%s", tc.name, tc.code, code)
+		}
+	}
+}
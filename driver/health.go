@@ -0,0 +1,199 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/driver/internal/k8s"
+	"github.com/topolvm/topolvm/filesystem"
+	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"golang.org/x/sys/unix"
+	mountutil "k8s.io/mount-utils"
+)
+
+const mountInfoPath = "/proc/self/mountinfo"
+
+// lvListCacheTTL is how long the LV list of a device-class is reused for volume conditions.
+// kubelet polls NodeGetVolumeStats for every volume periodically, so the volumes of
+// a device-class share one GetLVList call within this interval.
+const lvListCacheTTL = time.Minute
+
+// lvListCache caches the responses of GetLVList per device-class.
+type lvListCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]lvListEntry
+}
+
+type lvListEntry struct {
+	resp   *proto.GetLVListResponse
+	expiry time.Time
+}
+
+func newLVListCache() *lvListCache {
+	return &lvListCache{
+		now:     time.Now,
+		entries: make(map[string]lvListEntry),
+	}
+}
+
+// get returns the cached LV list of the device-class, or calls GetLVList
+// if it is not cached, it is expired, or refresh is true.
+func (c *lvListCache) get(ctx context.Context, vgClient proto.VGServiceClient, deviceClass string, refresh bool) (*proto.GetLVListResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if e, ok := c.entries[deviceClass]; ok && !refresh && now.Before(e.expiry) {
+		return e.resp, nil
+	}
+	resp, err := vgClient.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass})
+	if err != nil {
+		delete(c.entries, deviceClass)
+		return nil, err
+	}
+	c.entries[deviceClass] = lvListEntry{resp: resp, expiry: now.Add(lvListCacheTTL)}
+	return resp, nil
+}
+
+// volumeCondition inspects the LV, the device file and the mount of the volume
+// and returns the condition to be reported by NodeGetVolumeStats.
+func (s *nodeServerNoLocked) volumeCondition(ctx context.Context, volumeID, volumePath string, isBlock bool) (*csi.VolumeCondition, error) {
+	deviceClass := topolvm.DefaultDeviceClassName
+	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
+	if err == nil {
+		deviceClass = lvr.Spec.DeviceClass
+	} else if err != k8s.ErrVolumeNotFound {
+		return nil, err
+	}
+
+	listResp, err := s.lvLists.get(ctx, s.client, deviceClass, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list LV: %w", err)
+	}
+	lv := s.findVolumeByID(listResp, volumeID)
+	if lv == nil {
+		// The volume may be created after the list is cached.
+		listResp, err = s.lvLists.get(ctx, s.client, deviceClass, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list LV: %w", err)
+		}
+		lv = s.findVolumeByID(listResp, volumeID)
+	}
+	if lv == nil {
+		return abnormalCondition(fmt.Sprintf("LV %s is not found in lvmd", volumeID)), nil
+	}
+	if msg := lvHealthMessage(lv, listResp.GetThinPool()); msg != "" {
+		return abnormalCondition(msg), nil
+	}
+
+	// A block volume is published as a device file, while a filesystem volume
+	// is mounted from the device file under DeviceDirectory.
+	device := volumePath
+	if !isBlock {
+		device = filepath.Join(DeviceDirectory, volumeID)
+	}
+	var st unix.Stat_t
+	if err := filesystem.Stat(device, &st); err != nil {
+		return abnormalCondition(fmt.Sprintf("failed to stat device %s: %v", device, err)), nil
+	}
+	if msg := deviceMessage(device, &st, lv); msg != "" {
+		return abnormalCondition(msg), nil
+	}
+
+	if !isBlock {
+		mountInfos, err := mountutil.ParseMountInfo(mountInfoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", mountInfoPath, err)
+		}
+		if msg := mountMessage(mountInfos, volumePath); msg != "" {
+			return abnormalCondition(msg), nil
+		}
+	}
+
+	return &csi.VolumeCondition{
+		Abnormal: false,
+		Message:  "volume is healthy",
+	}, nil
+}
+
+func abnormalCondition(msg string) *csi.VolumeCondition {
+	return &csi.VolumeCondition{
+		Abnormal: true,
+		Message:  msg,
+	}
+}
+
+// lvHealthMessage returns the reason why the LV is unhealthy, or an empty string.
+func lvHealthMessage(lv *proto.LogicalVolume, pool *proto.ThinPoolItem) string {
+	attrs := lv.GetAttributes()
+	if attrs != nil {
+		if !attrs.GetActive() {
+			return fmt.Sprintf("LV %s is not active", lv.GetName())
+		}
+		switch health := command.LVHealth(attrs.GetHealth()); health {
+		case command.LVHealthPartial, command.LVHealthRefreshNeeded, command.LVHealthFailed:
+			if attrs.GetRaid() {
+				return fmt.Sprintf("RAID LV %s has failed legs: %s", lv.GetName(), health)
+			}
+			return fmt.Sprintf("LV %s is unhealthy: %s", lv.GetName(), health)
+		}
+	}
+
+	if pool == nil || lv.GetPool() == "" {
+		return ""
+	}
+	switch health := command.LVHealth(pool.GetHealth()); health {
+	case command.LVHealthOutOfDataSpace, command.LVHealthMetadataReadOnly, command.LVHealthFailed:
+		return fmt.Sprintf("thin pool %s is unhealthy: %s", lv.GetPool(), health)
+	}
+	if pool.GetDataPercent() >= 100 {
+		return fmt.Sprintf("thin pool %s is out of data space", lv.GetPool())
+	}
+	if pool.GetMetadataPercent() >= 100 {
+		return fmt.Sprintf("thin pool %s is out of metadata space", lv.GetPool())
+	}
+	return ""
+}
+
+// deviceMessage returns the reason why the device file does not point to the LV, or an empty string.
+func deviceMessage(device string, st *unix.Stat_t, lv *proto.LogicalVolume) string {
+	if st.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return fmt.Sprintf("%s is not a block device", device)
+	}
+	if st.Rdev != unix.Mkdev(lv.GetDevMajor(), lv.GetDevMinor()) {
+		return fmt.Sprintf("device %s has wrong device number %d:%d, expected %d:%d",
+			device, unix.Major(st.Rdev), unix.Minor(st.Rdev), lv.GetDevMajor(), lv.GetDevMinor())
+	}
+	return ""
+}
+
+// mountMessage returns the reason why the filesystem mounted on target is unhealthy, or an empty string.
+// A filesystem remounted read-only because of errors has the read-only superblock
+// while the mount point itself is still read-write.
+func mountMessage(mountInfos []mountutil.MountInfo, target string) string {
+	for _, mi := range mountInfos {
+		if mi.MountPoint != target {
+			continue
+		}
+		if hasOption(mi.MountOptions, "rw") && hasOption(mi.SuperOptions, "ro") {
+			return fmt.Sprintf("filesystem mounted on %s is read-only because of errors", target)
+		}
+	}
+	return ""
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
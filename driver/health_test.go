@@ -0,0 +1,206 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+	mountutil "k8s.io/mount-utils"
+)
+
+func TestLVHealthMessage(t *testing.T) {
+	healthy := &proto.LVAttributes{Active: true, Health: "ok"}
+
+	testCases := []struct {
+		name     string
+		lv       *proto.LogicalVolume
+		pool     *proto.ThinPoolItem
+		abnormal bool
+	}{
+		{
+			name: "healthy thick volume",
+			lv:   &proto.LogicalVolume{Name: "lv", Attributes: healthy},
+		},
+		{
+			name: "no attributes",
+			lv:   &proto.LogicalVolume{Name: "lv"},
+		},
+		{
+			name:     "inactive volume",
+			lv:       &proto.LogicalVolume{Name: "lv", Attributes: &proto.LVAttributes{Health: "ok"}},
+			abnormal: true,
+		},
+		{
+			name:     "RAID leg failed",
+			lv:       &proto.LogicalVolume{Name: "lv", Attributes: &proto.LVAttributes{Active: true, Raid: true, Health: "partial"}},
+			abnormal: true,
+		},
+		{
+			name: "healthy thin volume",
+			lv:   &proto.LogicalVolume{Name: "lv", Pool: "pool", Attributes: healthy},
+			pool: &proto.ThinPoolItem{DataPercent: 50, MetadataPercent: 10, Health: "ok"},
+		},
+		{
+			name:     "thin pool out of data space",
+			lv:       &proto.LogicalVolume{Name: "lv", Pool: "pool", Attributes: healthy},
+			pool:     &proto.ThinPoolItem{DataPercent: 100, MetadataPercent: 10, Health: "out-of-data-space"},
+			abnormal: true,
+		},
+		{
+			name:     "thin pool data full",
+			lv:       &proto.LogicalVolume{Name: "lv", Pool: "pool", Attributes: healthy},
+			pool:     &proto.ThinPoolItem{DataPercent: 100, MetadataPercent: 10, Health: "ok"},
+			abnormal: true,
+		},
+		{
+			name:     "thin pool metadata read-only",
+			lv:       &proto.LogicalVolume{Name: "lv", Pool: "pool", Attributes: healthy},
+			pool:     &proto.ThinPoolItem{DataPercent: 50, MetadataPercent: 100, Health: "metadata-read-only"},
+			abnormal: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		msg := lvHealthMessage(tc.lv, tc.pool)
+		if (msg != "") != tc.abnormal {
+			t.Errorf("%s: expected abnormal=%t actual message=%q", tc.name, tc.abnormal, msg)
+		}
+	}
+}
+
+func TestDeviceMessage(t *testing.T) {
+	lv := &proto.LogicalVolume{DevMajor: 253, DevMinor: 1}
+
+	testCases := []struct {
+		name     string
+		st       unix.Stat_t
+		abnormal bool
+	}{
+		{
+			name: "correct device",
+			st:   unix.Stat_t{Mode: unix.S_IFBLK | 0600, Rdev: unix.Mkdev(253, 1)},
+		},
+		{
+			name:     "wrong minor",
+			st:       unix.Stat_t{Mode: unix.S_IFBLK | 0600, Rdev: unix.Mkdev(253, 2)},
+			abnormal: true,
+		},
+		{
+			name:     "not a block device",
+			st:       unix.Stat_t{Mode: unix.S_IFREG | 0600},
+			abnormal: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		msg := deviceMessage("/dev/topolvm/vol", &tc.st, lv)
+		if (msg != "") != tc.abnormal {
+			t.Errorf("%s: expected abnormal=%t actual message=%q", tc.name, tc.abnormal, msg)
+		}
+	}
+}
+
+func TestMountMessage(t *testing.T) {
+	target := "/var/lib/kubelet/pods/uid/volumes/kubernetes.io~csi/pv/mount"
+
+	testCases := []struct {
+		name       string
+		mountInfos []mountutil.MountInfo
+		abnormal   bool
+	}{
+		{
+			name: "read-write",
+			mountInfos: []mountutil.MountInfo{
+				{MountPoint: target, MountOptions: []string{"rw", "relatime"}, SuperOptions: []string{"rw"}},
+			},
+		},
+		{
+			name: "read-only mount",
+			mountInfos: []mountutil.MountInfo{
+				{MountPoint: target, MountOptions: []string{"ro", "relatime"}, SuperOptions: []string{"ro"}},
+			},
+		},
+		{
+			name: "remounted read-only because of errors",
+			mountInfos: []mountutil.MountInfo{
+				{MountPoint: target, MountOptions: []string{"rw", "relatime"}, SuperOptions: []string{"ro", "errors=remount-ro"}},
+			},
+			abnormal: true,
+		},
+		{
+			name: "another mount point",
+			mountInfos: []mountutil.MountInfo{
+				{MountPoint: "/mnt", MountOptions: []string{"rw"}, SuperOptions: []string{"ro"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		msg := mountMessage(tc.mountInfos, target)
+		if (msg != "") != tc.abnormal {
+			t.Errorf("%s: expected abnormal=%t actual message=%q", tc.name, tc.abnormal, msg)
+		}
+	}
+}
+
+type countingVGClient struct {
+	proto.VGServiceClient
+	calls map[string]int
+	err   error
+}
+
+func (c *countingVGClient) GetLVList(_ context.Context, req *proto.GetLVListRequest, _ ...grpc.CallOption) (*proto.GetLVListResponse, error) {
+	c.calls[req.GetDeviceClass()]++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &proto.GetLVListResponse{}, nil
+}
+
+func TestLVListCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := &countingVGClient{calls: make(map[string]int)}
+	cache := newLVListCache()
+	cache.now = func() time.Time { return now }
+
+	get := func(deviceClass string, refresh bool) {
+		t.Helper()
+		if _, err := cache.get(ctx, client, deviceClass, refresh); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expectCalls := func(deviceClass string, expected int) {
+		t.Helper()
+		if client.calls[deviceClass] != expected {
+			t.Errorf("expected %d calls for %q, actual %d", expected, deviceClass, client.calls[deviceClass])
+		}
+	}
+
+	get("ssd", false)
+	get("ssd", false)
+	expectCalls("ssd", 1)
+
+	get("hdd", false)
+	expectCalls("hdd", 1)
+	expectCalls("ssd", 1)
+
+	get("ssd", true)
+	expectCalls("ssd", 2)
+
+	now = now.Add(lvListCacheTTL)
+	get("ssd", false)
+	expectCalls("ssd", 3)
+
+	client.err = errors.New("lvmd is unavailable")
+	if _, err := cache.get(ctx, client, "ssd", true); err == nil {
+		t.Fatal("expected error")
+	}
+	client.err = nil
+	get("ssd", false)
+	expectCalls("ssd", 5)
+}
@@ -0,0 +1,108 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Root is the mount point of the cgroup v2 unified hierarchy.
+var Root = "/sys/fs/cgroup"
+
+var (
+	// ErrNotCgroupV2 represents the cgroup v2 unified hierarchy is not mounted on Root.
+	ErrNotCgroupV2 = errors.New("cgroup v2 is not available")
+
+	// ErrIOControllerDisabled represents the io controller is not enabled for the cgroup.
+	ErrIOControllerDisabled = errors.New("io controller is not enabled")
+
+	// ErrPodCgroupNotFound represents the cgroup of the pod is not found.
+	ErrPodCgroupNotFound = errors.New("pod cgroup is not found")
+)
+
+// IOMax represents a line of io.max for a block device.
+// Zero in limits means unlimited.
+type IOMax struct {
+	Major     uint32
+	Minor     uint32
+	ReadBPS   uint64
+	WriteBPS  uint64
+	ReadIOPS  uint64
+	WriteIOPS uint64
+}
+
+func limitString(v uint64) string {
+	if v == 0 {
+		return "max"
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// String returns the line to be written in io.max.
+func (m IOMax) String() string {
+	return fmt.Sprintf("%d:%d rbps=%s wbps=%s riops=%s wiops=%s",
+		m.Major, m.Minor,
+		limitString(m.ReadBPS), limitString(m.WriteBPS),
+		limitString(m.ReadIOPS), limitString(m.WriteIOPS))
+}
+
+// podCgroupCandidates returns the relative paths of the pod cgroup
+// for both of the cgroupfs and systemd cgroup drivers of kubelet.
+func podCgroupCandidates(podUID string) []string {
+	escaped := strings.ReplaceAll(podUID, "-", "_")
+	return []string{
+		// cgroupfs driver
+		filepath.Join("kubepods", "pod"+podUID),
+		filepath.Join("kubepods", "burstable", "pod"+podUID),
+		filepath.Join("kubepods", "besteffort", "pod"+podUID),
+		// systemd driver
+		filepath.Join("kubepods.slice", "kubepods-pod"+escaped+".slice"),
+		filepath.Join("kubepods.slice", "kubepods-burstable.slice", "kubepods-burstable-pod"+escaped+".slice"),
+		filepath.Join("kubepods.slice", "kubepods-besteffort.slice", "kubepods-besteffort-pod"+escaped+".slice"),
+	}
+}
+
+// FindPodCgroup returns the path of the cgroup of the pod.
+func FindPodCgroup(podUID string) (string, error) {
+	if _, err := os.Stat(filepath.Join(Root, "cgroup.controllers")); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotCgroupV2
+		}
+		return "", err
+	}
+
+	for _, p := range podCgroupCandidates(podUID) {
+		dir := filepath.Join(Root, p)
+		info, err := os.Stat(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		if info.IsDir() {
+			return dir, nil
+		}
+	}
+	return "", ErrPodCgroupNotFound
+}
+
+// SetIOMax writes the limits to io.max of the cgroup.
+// Writing an IOMax with all limits zero removes the limits of the device.
+func SetIOMax(cgroupPath string, m IOMax) error {
+	f, err := os.OpenFile(filepath.Join(cgroupPath, "io.max"), os.O_WRONLY, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrIOControllerDisabled
+		}
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(m.String()); err != nil {
+		return fmt.Errorf("failed to write io.max of %s: %w", cgroupPath, err)
+	}
+	return f.Close()
+}
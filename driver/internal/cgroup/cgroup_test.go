@@ -0,0 +1,96 @@
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIOMaxString(t *testing.T) {
+	testCases := []struct {
+		input    IOMax
+		expected string
+	}{
+		{
+			input:    IOMax{Major: 253, Minor: 1},
+			expected: "253:1 rbps=max wbps=max riops=max wiops=max",
+		},
+		{
+			input:    IOMax{Major: 8, Minor: 16, ReadBPS: 1 << 20, WriteBPS: 2 << 20, ReadIOPS: 100, WriteIOPS: 200},
+			expected: "8:16 rbps=1048576 wbps=2097152 riops=100 wiops=200",
+		},
+		{
+			input:    IOMax{Major: 8, Minor: 16, WriteIOPS: 200},
+			expected: "8:16 rbps=max wbps=max riops=max wiops=200",
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := tc.input.String(); actual != tc.expected {
+			t.Errorf("unexpected io.max: expected=%q, actual=%q", tc.expected, actual)
+		}
+	}
+}
+
+func TestFindPodCgroup(t *testing.T) {
+	defer func(root string) { Root = root }(Root)
+	Root = t.TempDir()
+
+	const uid = "0a8b4d2c-1234-5678-9abc-def012345678"
+	if _, err := FindPodCgroup(uid); err != ErrNotCgroupV2 {
+		t.Fatalf("ErrNotCgroupV2 should be returned: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(Root, "cgroup.controllers"), []byte("cpu io memory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindPodCgroup(uid); err != ErrPodCgroupNotFound {
+		t.Fatalf("ErrPodCgroupNotFound should be returned: %v", err)
+	}
+
+	testCases := []string{
+		"kubepods/pod0a8b4d2c-1234-5678-9abc-def012345678",
+		"kubepods/burstable/pod0a8b4d2c-1234-5678-9abc-def012345678",
+		"kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod0a8b4d2c_1234_5678_9abc_def012345678.slice",
+	}
+	for _, tc := range testCases {
+		dir := filepath.Join(Root, tc)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := FindPodCgroup(uid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != dir {
+			t.Errorf("unexpected cgroup: expected=%s, actual=%s", dir, actual)
+		}
+		if err := os.RemoveAll(filepath.Join(Root, filepath.Dir(tc))); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSetIOMax(t *testing.T) {
+	dir := t.TempDir()
+
+	m := IOMax{Major: 253, Minor: 3, ReadIOPS: 1000}
+	if err := SetIOMax(dir, m); err != ErrIOControllerDisabled {
+		t.Fatalf("ErrIOControllerDisabled should be returned: %v", err)
+	}
+
+	ioMax := filepath.Join(dir, "io.max")
+	if err := os.WriteFile(ioMax, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetIOMax(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(ioMax)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != m.String() {
+		t.Errorf("unexpected content of io.max: %q", string(data))
+	}
+}
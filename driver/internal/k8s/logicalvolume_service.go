@@ -2,8 +2,11 @@ package k8s
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/topolvm/topolvm"
@@ -13,6 +16,7 @@ import (
 	"github.com/topolvm/topolvm/getter"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -118,6 +122,26 @@ func (v *volumeGetter) Get(ctx context.Context, volumeID string) (*topolvmv1.Log
 	return foundLv, nil
 }
 
+// List returns all LogicalVolumes.
+func (v *volumeGetter) List(ctx context.Context) ([]topolvmv1.LogicalVolume, error) {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	err := v.cacheReader.List(ctx, lvList)
+	if err != nil {
+		return nil, err
+	}
+	return lvList.Items, nil
+}
+
+// ListByGroupSnapshot returns LogicalVolumes of the volume group snapshot.
+func (v *volumeGetter) ListByGroupSnapshot(ctx context.Context, group string) ([]topolvmv1.LogicalVolume, error) {
+	lvList := new(topolvmv1.LogicalVolumeList)
+	err := v.apiReader.List(ctx, lvList, client.MatchingLabels{topolvm.GetGroupSnapshotKey(): group})
+	if err != nil {
+		return nil, err
+	}
+	return lvList.Items, nil
+}
+
 //+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
 
@@ -149,15 +173,27 @@ func NewLogicalVolumeService(mgr manager.Manager) (*LogicalVolumeService, error)
 	}, nil
 }
 
-// CreateVolume creates volume
-func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, name, sourceName string, requestGb int64) (string, error) {
+// CreateVolume creates volume.
+// The annotations are added to the LogicalVolume.
+func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, name, sourceName string, requestGb int64, annotations map[string]string) (string, error) {
+	err := s.CreateLogicalVolume(ctx, node, dc, oc, name, sourceName, requestGb, annotations)
+	if err != nil {
+		return "", err
+	}
+	return s.WaitForVolumeID(ctx, name)
+}
+
+// CreateLogicalVolume creates LogicalVolume without waiting for the LV to be created.
+// If the LogicalVolume already exists, it checks that the existing one is compatible.
+func (s *LogicalVolumeService) CreateLogicalVolume(ctx context.Context, node, dc, oc, name, sourceName string, requestGb int64, annotations map[string]string) error {
 	logger.Info("k8s.CreateVolume called", "name", name, "node", node, "size_gb", requestGb, "sourceName", sourceName)
 	var lv *topolvmv1.LogicalVolume
 	// if the create volume request has no source, proceed with regular lv creation.
 	if sourceName == "" {
 		lv = &topolvmv1.LogicalVolume{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
+				Name:        name,
+				Annotations: annotations,
 			},
 			Spec: topolvmv1.LogicalVolumeSpec{
 				Name:                name,
@@ -172,7 +208,8 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 		// On the other hand, if a volume has a datasource, create a thin snapshot of the source volume with READ-WRITE access.
 		lv = &topolvmv1.LogicalVolume{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: name,
+				Name:        name,
+				Annotations: annotations,
 			},
 			Spec: topolvmv1.LogicalVolumeSpec{
 				Name:                name,
@@ -190,12 +227,12 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 	err := s.getter.Get(ctx, client.ObjectKey{Name: name}, existingLV)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
-			return "", err
+			return err
 		}
 
 		err := s.writer.Create(ctx, lv)
 		if err != nil {
-			return "", err
+			return err
 		}
 		logger.Info("created LogicalVolume CR", "name", name, "sourceID", lv.Spec.Source)
 	} else {
@@ -203,16 +240,16 @@ func (s *LogicalVolumeService) CreateVolume(ctx context.Context, node, dc, oc, n
 		// skip check of capabilities because (1) we allow both of two access types, and (2) we allow only one access mode
 		// for ease of comparison, sizes are compared strictly, not by compatibility of ranges
 		if !existingLV.IsCompatibleWith(lv) {
-			return "", status.Error(codes.AlreadyExists, "Incompatible LogicalVolume already exists")
+			return status.Error(codes.AlreadyExists, "Incompatible LogicalVolume already exists")
 		}
 		// compatible LV was found
 	}
-	volumeID, err := s.waitForStatusUpdate(ctx, name)
-	if err != nil {
-		return "", err
-	}
+	return nil
+}
 
-	return volumeID, nil
+// WaitForVolumeID waits for the LV of the LogicalVolume to be created, and returns the volume ID.
+func (s *LogicalVolumeService) WaitForVolumeID(ctx context.Context, name string) (string, error) {
+	return s.waitForStatusUpdate(ctx, name)
 }
 
 // DeleteVolume deletes volume
@@ -257,11 +294,13 @@ func (s *LogicalVolumeService) DeleteVolume(ctx context.Context, volumeID string
 }
 
 // CreateSnapshot creates a snapshot of existing volume.
-func (s *LogicalVolumeService) CreateSnapshot(ctx context.Context, node, dc, sourceVol, sname, accessType string, snapSize resource.Quantity) (string, error) {
+// The annotations are added to the LogicalVolume.
+func (s *LogicalVolumeService) CreateSnapshot(ctx context.Context, node, dc, sourceVol, sname, accessType string, snapSize resource.Quantity, annotations map[string]string) (string, error) {
 	logger.Info("CreateSnapshot called", "name", sname)
 	snapshotLV := &topolvmv1.LogicalVolume{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: sname,
+			Name:        sname,
+			Annotations: annotations,
 		},
 		Spec: topolvmv1.LogicalVolumeSpec{
 			Name:        sname,
@@ -298,6 +337,106 @@ func (s *LogicalVolumeService) CreateSnapshot(ctx context.Context, node, dc, sou
 	return volumeID, nil
 }
 
+// CreateGroupSnapshot creates snapshots of the source volumes as a volume group snapshot.
+// The snapshots are created at the same instant by topolvm-node, so the source volumes
+// must be on the same node and device class.
+func (s *LogicalVolumeService) CreateGroupSnapshot(ctx context.Context, group, accessType string, sources []*topolvmv1.LogicalVolume) error {
+	logger.Info("CreateGroupSnapshot called", "group", group, "count", len(sources))
+
+	names := make([]string, len(sources))
+	for i, source := range sources {
+		names[i] = groupSnapshotMemberName(group, source.Name)
+		snapshotLV := &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        names[i],
+				Labels:      map[string]string{topolvm.GetGroupSnapshotKey(): group},
+				Annotations: map[string]string{topolvm.GetGroupSnapshotSizeKey(): strconv.Itoa(len(sources))},
+			},
+			Spec: topolvmv1.LogicalVolumeSpec{
+				Name:        names[i],
+				NodeName:    source.Spec.NodeName,
+				DeviceClass: source.Spec.DeviceClass,
+				Size:        source.Spec.Size,
+				Source:      source.Spec.Name,
+				AccessType:  accessType,
+			},
+		}
+
+		existingSnapshot := new(topolvmv1.LogicalVolume)
+		err := s.getter.Get(ctx, client.ObjectKey{Name: names[i]}, existingSnapshot)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			err := s.writer.Create(ctx, snapshotLV)
+			if err != nil {
+				return err
+			}
+			logger.Info("created LogicalVolume CR", "name", names[i], "source", snapshotLV.Spec.Source, "group", group)
+		} else {
+			if !existingSnapshot.IsCompatibleWith(snapshotLV) || existingSnapshot.Labels[topolvm.GetGroupSnapshotKey()] != group {
+				return status.Error(codes.AlreadyExists, "Incompatible LogicalVolume already exists")
+			}
+		}
+	}
+
+	for _, name := range names {
+		_, err := s.waitForStatusUpdate(ctx, name)
+		if err != nil {
+			// The snapshots are created at once, so the others are not usable either.
+			if err2 := s.DeleteGroupSnapshot(ctx, group); err2 != nil {
+				logger.Error(err2, "failed to delete volume group snapshot", "group", group)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// GetGroupSnapshot returns the LogicalVolumes of the volume group snapshot.
+func (s *LogicalVolumeService) GetGroupSnapshot(ctx context.Context, group string) ([]topolvmv1.LogicalVolume, error) {
+	return s.volumeGetter.ListByGroupSnapshot(ctx, group)
+}
+
+// DeleteGroupSnapshot deletes all the LogicalVolumes of the volume group snapshot.
+func (s *LogicalVolumeService) DeleteGroupSnapshot(ctx context.Context, group string) error {
+	logger.Info("k8s.DeleteGroupSnapshot called", "group", group)
+
+	lvs, err := s.volumeGetter.ListByGroupSnapshot(ctx, group)
+	if err != nil {
+		return err
+	}
+	for i := range lvs {
+		if err := s.writer.Delete(ctx, &lvs[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	// wait until delete the target volumes
+	for {
+		logger.Info("waiting for delete LogicalVolumes of volume group snapshot", "group", group)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		lvs, err := s.volumeGetter.ListByGroupSnapshot(ctx, group)
+		if err != nil {
+			return err
+		}
+		if len(lvs) == 0 {
+			return nil
+		}
+	}
+}
+
+// groupSnapshotMemberName returns the name of LogicalVolume for the snapshot of the source in the volume group snapshot.
+func groupSnapshotMemberName(group, source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return group + "-" + hex.EncodeToString(sum[:])[:10]
+}
+
 // ExpandVolume expands volume
 func (s *LogicalVolumeService) ExpandVolume(ctx context.Context, volumeID string, requestGb int64) error {
 	logger.Info("k8s.ExpandVolume called", "volumeID", volumeID, "requestGb", requestGb)
@@ -349,6 +488,97 @@ func (s *LogicalVolumeService) GetVolume(ctx context.Context, volumeID string) (
 	return s.volumeGetter.Get(ctx, volumeID)
 }
 
+// ListVolumes returns all LogicalVolumes including snapshots.
+func (s *LogicalVolumeService) ListVolumes(ctx context.Context) ([]topolvmv1.LogicalVolume, error) {
+	return s.volumeGetter.List(ctx)
+}
+
+// GetVolumeByName returns LogicalVolume by name.
+func (s *LogicalVolumeService) GetVolumeByName(ctx context.Context, name string) (*topolvmv1.LogicalVolume, error) {
+	lv := new(topolvmv1.LogicalVolume)
+	if err := s.getter.Get(ctx, client.ObjectKey{Name: name}, lv); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ErrVolumeNotFound
+		}
+		return nil, err
+	}
+	return lv, nil
+}
+
+// UpdateIOLimits updates .Status.IOLimits and .Status.IOLimitsMessage of LogicalVolume.
+func (s *LogicalVolumeService) UpdateIOLimits(ctx context.Context, volumeID string, limits *topolvmv1.IOLimits, message string) error {
+	return s.updateStatus(ctx, volumeID, func(status *topolvmv1.LogicalVolumeStatus) bool {
+		if equality.Semantic.DeepEqual(status.IOLimits, limits) && status.IOLimitsMessage == message {
+			return false
+		}
+		status.IOLimits = limits
+		status.IOLimitsMessage = message
+		return true
+	})
+}
+
+// AddPublishedTarget adds the target to .Status.PublishedTargets of LogicalVolume.
+// The target of the same path is replaced.
+func (s *LogicalVolumeService) AddPublishedTarget(ctx context.Context, volumeID string, target topolvmv1.PublishedTarget) error {
+	return s.updateStatus(ctx, volumeID, func(status *topolvmv1.LogicalVolumeStatus) bool {
+		for i, t := range status.PublishedTargets {
+			if t.Path != target.Path {
+				continue
+			}
+			if t == target {
+				return false
+			}
+			status.PublishedTargets[i] = target
+			return true
+		}
+		status.PublishedTargets = append(status.PublishedTargets, target)
+		return true
+	})
+}
+
+// RemovePublishedTarget removes the target of the path from .Status.PublishedTargets of LogicalVolume.
+func (s *LogicalVolumeService) RemovePublishedTarget(ctx context.Context, volumeID, path string) error {
+	return s.updateStatus(ctx, volumeID, func(status *topolvmv1.LogicalVolumeStatus) bool {
+		for i, t := range status.PublishedTargets {
+			if t.Path == path {
+				status.PublishedTargets = append(status.PublishedTargets[:i], status.PublishedTargets[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// updateStatus updates .Status of LogicalVolume with the function.
+// The function returns false if the status need not be updated.
+func (s *LogicalVolumeService) updateStatus(ctx context.Context, volumeID string, update func(*topolvmv1.LogicalVolumeStatus) bool) error {
+	for {
+		lv, err := s.GetVolume(ctx, volumeID)
+		if err != nil {
+			return err
+		}
+		if !update(&lv.Status) {
+			return nil
+		}
+
+		if err := s.writer.Status().Update(ctx, lv); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Info("detect conflict when LogicalVolume status update", "name", lv.Name)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+				}
+				continue
+			}
+			logger.Error(err, "failed to update LogicalVolume status", "name", lv.Name)
+			return err
+		}
+
+		return nil
+	}
+}
+
 // updateSpecSize updates .Spec.Size of LogicalVolume.
 func (s *LogicalVolumeService) updateSpecSize(ctx context.Context, volumeID string, size *resource.Quantity) error {
 	for {
@@ -382,6 +612,14 @@ func (s *LogicalVolumeService) updateSpecSize(ctx context.Context, volumeID stri
 	}
 }
 
+// isExpanding returns true if topolvm-node has not expanded the volume to the requested size yet.
+func isExpanding(lv *topolvmv1.LogicalVolume) bool {
+	if lv.Status.CurrentSize == nil {
+		return false
+	}
+	return lv.Status.CurrentSize.Cmp(lv.Spec.Size) < 0
+}
+
 // waitForStatusUpdate waits for logical volume creation/failure/timeout, whichever comes first.
 func (s *LogicalVolumeService) waitForStatusUpdate(ctx context.Context, name string) (string, error) {
 	for {
@@ -398,7 +636,8 @@ func (s *LogicalVolumeService) waitForStatusUpdate(ctx context.Context, name str
 			logger.Error(err, "failed to get LogicalVolume", "name", name)
 			return "", err
 		}
-		if newLV.Status.VolumeID != "" {
+		// A volume created from a source is expanded after the creation if it is larger than the source.
+		if newLV.Status.VolumeID != "" && !isExpanding(&newLV) {
 			logger.Info("end k8s.LogicalVolume", "volume_id", newLV.Status.VolumeID)
 			return newLV.Status.VolumeID, nil
 		}
@@ -2,11 +2,16 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -15,6 +20,7 @@ var ErrNodeNotFound = errors.New("node not found")
 var ErrDeviceClassNotFound = errors.New("device class not found")
 
 // NodeService represents node service.
+// The capacities returned by NodeService exclude the space reserved for in-flight LogicalVolumes.
 type NodeService struct {
 	// it is safe to use cache reader because updating node annotations is periodic.
 	reader client.Reader
@@ -45,15 +51,37 @@ func (s NodeService) extractCapacityFromAnnotation(node *corev1.Node, deviceClas
 	return strconv.ParseInt(c, 10, 64)
 }
 
+func (s NodeService) availableCapacity(node *corev1.Node, deviceClass string, ledger reservation.Ledger) (int64, error) {
+	c, err := s.extractCapacityFromAnnotation(node, deviceClass)
+	if err != nil {
+		return 0, err
+	}
+	c -= ledger.Reserved(node.Name, deviceClass)
+	if c < 0 {
+		c = 0
+	}
+	return c, nil
+}
+
 // GetCapacityByName returns VG capacity of specified node by name.
 func (s NodeService) GetCapacityByName(ctx context.Context, name, deviceClass string) (int64, error) {
+	return s.GetAvailableCapacity(ctx, name, deviceClass, "")
+}
+
+// GetAvailableCapacity returns VG capacity of specified node by name.
+// The LogicalVolume named exclude is not counted as a reservation.
+func (s NodeService) GetAvailableCapacity(ctx context.Context, name, deviceClass, exclude string) (int64, error) {
 	n := new(corev1.Node)
 	err := s.reader.Get(ctx, client.ObjectKey{Name: name}, n)
 	if err != nil {
 		return 0, err
 	}
 
-	return s.extractCapacityFromAnnotation(n, deviceClass)
+	ledger, err := reservation.Build(ctx, s.reader, exclude)
+	if err != nil {
+		return 0, err
+	}
+	return s.availableCapacity(n, deviceClass, ledger)
 }
 
 // GetCapacityByTopologyLabel returns VG capacity of specified node by TopoLVM's topology label.
@@ -62,13 +90,17 @@ func (s NodeService) GetCapacityByTopologyLabel(ctx context.Context, topology, d
 	if err != nil {
 		return 0, err
 	}
+	ledger, err := reservation.Build(ctx, s.reader, "")
+	if err != nil {
+		return 0, err
+	}
 
 	for _, node := range nl.Items {
 		if v, ok := node.Labels[topolvm.GetTopologyNodeKey()]; ok {
 			if v != topology {
 				continue
 			}
-			return s.extractCapacityFromAnnotation(&node, dc)
+			return s.availableCapacity(&node, dc, ledger)
 		}
 	}
 
@@ -81,10 +113,14 @@ func (s NodeService) GetTotalCapacity(ctx context.Context, dc string) (int64, er
 	if err != nil {
 		return 0, err
 	}
+	ledger, err := reservation.Build(ctx, s.reader, "")
+	if err != nil {
+		return 0, err
+	}
 
 	capacity := int64(0)
 	for _, node := range nl.Items {
-		c, _ := s.extractCapacityFromAnnotation(&node, dc)
+		c, _ := s.availableCapacity(&node, dc, ledger)
 		capacity += c
 	}
 	return capacity, nil
@@ -96,10 +132,14 @@ func (s NodeService) GetMaxCapacity(ctx context.Context, deviceClass string) (st
 	if err != nil {
 		return "", 0, err
 	}
+	ledger, err := reservation.Build(ctx, s.reader, "")
+	if err != nil {
+		return "", 0, err
+	}
 	var nodeName string
 	var maxCapacity int64
 	for _, node := range nl.Items {
-		c, _ := s.extractCapacityFromAnnotation(&node, deviceClass)
+		c, _ := s.availableCapacity(&node, deviceClass, ledger)
 		if maxCapacity < c {
 			maxCapacity = c
 			nodeName = node.Name
@@ -107,3 +147,93 @@ func (s NodeService) GetMaxCapacity(ctx context.Context, deviceClass string) (st
 	}
 	return nodeName, maxCapacity, nil
 }
+
+// GetDeviceClassesBySelector returns the device-classes of the node whose labels match the selector.
+func (s NodeService) GetDeviceClassesBySelector(ctx context.Context, name string, selector labels.Selector) ([]string, error) {
+	n := new(corev1.Node)
+	err := s.reader.Get(ctx, client.ObjectKey{Name: name}, n)
+	if err != nil {
+		return nil, err
+	}
+	return topolvm.SelectDeviceClasses(n, selector), nil
+}
+
+// GetDefaultFilesystemOptions returns the default mkfs options for the filesystem type and mount options of the device-class on the node.
+func (s NodeService) GetDefaultFilesystemOptions(ctx context.Context, name, deviceClass, fsType string) (string, string, error) {
+	n := new(corev1.Node)
+	err := s.reader.Get(ctx, client.ObjectKey{Name: name}, n)
+	if apierrors.IsNotFound(err) {
+		return "", "", ErrNodeNotFound
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if deviceClass == topolvm.DefaultDeviceClassName {
+		deviceClass = topolvm.DefaultDeviceClassAnnotationName
+	}
+
+	var mkfsOptions map[string]string
+	if data, ok := n.Annotations[topolvm.GetMkfsOptionsKeyPrefix()+deviceClass]; ok {
+		if err := json.Unmarshal([]byte(data), &mkfsOptions); err != nil {
+			return "", "", fmt.Errorf("invalid annotation %s of node %s: %w", topolvm.GetMkfsOptionsKeyPrefix()+deviceClass, name, err)
+		}
+	}
+	return mkfsOptions[fsType], n.Annotations[topolvm.GetMountOptionsKeyPrefix()+deviceClass], nil
+}
+
+// GetMaxCapacityBySelector returns max VG capacity among nodes and the device-classes selected by the selector.
+func (s NodeService) GetMaxCapacityBySelector(ctx context.Context, selector labels.Selector) (string, int64, error) {
+	nl, err := s.getNodes(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	ledger, err := reservation.Build(ctx, s.reader, "")
+	if err != nil {
+		return "", 0, err
+	}
+	var nodeName string
+	var maxCapacity int64
+	for _, node := range nl.Items {
+		for _, dc := range topolvm.SelectDeviceClasses(&node, selector) {
+			c, _ := s.availableCapacity(&node, dc, ledger)
+			if maxCapacity < c {
+				maxCapacity = c
+				nodeName = node.Name
+			}
+		}
+	}
+	return nodeName, maxCapacity, nil
+}
+
+// GetCapacityBySelector returns the total VG capacity and the max VG capacity of the device-classes selected by the selector.
+// If topology is not empty, only the node of TopoLVM's topology label is counted.
+func (s NodeService) GetCapacityBySelector(ctx context.Context, topology string, selector labels.Selector) (int64, int64, error) {
+	nl, err := s.getNodes(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	ledger, err := reservation.Build(ctx, s.reader, "")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var capacity, maxCapacity int64
+	found := false
+	for _, node := range nl.Items {
+		if topology != "" && node.Labels[topolvm.GetTopologyNodeKey()] != topology {
+			continue
+		}
+		found = true
+		for _, dc := range topolvm.SelectDeviceClasses(&node, selector) {
+			c, _ := s.availableCapacity(&node, dc, ledger)
+			capacity += c
+			if maxCapacity < c {
+				maxCapacity = c
+			}
+		}
+	}
+	if topology != "" && !found {
+		return 0, 0, ErrNodeNotFound
+	}
+	return capacity, maxCapacity, nil
+}
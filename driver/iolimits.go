@@ -0,0 +1,104 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/topolvm/topolvm"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ioLimitsFromParameters parses the I/O limits in StorageClass parameters or volume context.
+// It returns nil if no limit is specified.
+func ioLimitsFromParameters(params map[string]string) (*v1.IOLimits, error) {
+	var limits v1.IOLimits
+	var found bool
+
+	for _, p := range []struct {
+		key      string
+		quantity bool
+		target   *uint64
+	}{
+		{topolvm.GetReadIOPSKey(), false, &limits.ReadIOPS},
+		{topolvm.GetWriteIOPSKey(), false, &limits.WriteIOPS},
+		{topolvm.GetReadBPSKey(), true, &limits.ReadBPS},
+		{topolvm.GetWriteBPSKey(), true, &limits.WriteBPS},
+	} {
+		str, ok := params[p.key]
+		if !ok {
+			continue
+		}
+
+		var value uint64
+		if p.quantity {
+			q, err := resource.ParseQuantity(str)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s: %w", p.key, str, err)
+			}
+			if q.Sign() <= 0 {
+				return nil, fmt.Errorf("%s must be positive: %s", p.key, str)
+			}
+			value = uint64(q.Value())
+		} else {
+			v, err := strconv.ParseUint(str, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s: %w", p.key, str, err)
+			}
+			if v == 0 {
+				return nil, fmt.Errorf("%s must be positive: %s", p.key, str)
+			}
+			value = v
+		}
+		*p.target = value
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return &limits, nil
+}
+
+// ioLimitsToVolumeContext returns the volume context to pass the I/O limits to the node service.
+func ioLimitsToVolumeContext(limits *v1.IOLimits) map[string]string {
+	if limits == nil {
+		return nil
+	}
+	ctx := make(map[string]string)
+	for key, value := range map[string]uint64{
+		topolvm.GetReadIOPSKey():  limits.ReadIOPS,
+		topolvm.GetWriteIOPSKey(): limits.WriteIOPS,
+		topolvm.GetReadBPSKey():   limits.ReadBPS,
+		topolvm.GetWriteBPSKey():  limits.WriteBPS,
+	} {
+		if value != 0 {
+			ctx[key] = strconv.FormatUint(value, 10)
+		}
+	}
+	return ctx
+}
+
+// podUIDFromTargetPath returns the UID of the pod from target_path of NodePublishVolume.
+// kubelet uses the following paths:
+//   - filesystem volumes: <kubelet-dir>/pods/<pod-uid>/volumes/kubernetes.io~csi/<pv-name>/mount
+//   - block volumes: <kubelet-dir>/plugins/kubernetes.io/csi/volumeDevices/publish/<pv-name>/<pod-uid>
+func podUIDFromTargetPath(targetPath string) (string, error) {
+	elems := strings.Split(filepath.Clean(targetPath), string(filepath.Separator))
+	for i := len(elems) - 1; i >= 0; i-- {
+		switch elems[i] {
+		case "pods":
+			if i+2 < len(elems) && elems[i+2] == "volumes" {
+				return elems[i+1], nil
+			}
+		case "publish":
+			if i+2 == len(elems)-1 && i > 0 && elems[i-1] == "volumeDevices" {
+				return elems[i+2], nil
+			}
+		}
+	}
+	return "", errors.New("failed to find pod UID in target path: " + targetPath)
+}
@@ -0,0 +1,116 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	v1 "github.com/topolvm/topolvm/api/v1"
+)
+
+func TestIOLimitsFromParameters(t *testing.T) {
+	testCases := []struct {
+		params   map[string]string
+		expected *v1.IOLimits
+		isErr    bool
+	}{
+		{
+			params:   map[string]string{topolvm.GetDeviceClassKey(): "ssd"},
+			expected: nil,
+		},
+		{
+			params: map[string]string{
+				topolvm.GetReadIOPSKey():  "1000",
+				topolvm.GetWriteIOPSKey(): "500",
+				topolvm.GetReadBPSKey():   "100Mi",
+				topolvm.GetWriteBPSKey():  "1000000",
+			},
+			expected: &v1.IOLimits{ReadIOPS: 1000, WriteIOPS: 500, ReadBPS: 100 << 20, WriteBPS: 1000000},
+		},
+		{
+			params:   map[string]string{topolvm.GetWriteBPSKey(): "1G"},
+			expected: &v1.IOLimits{WriteBPS: 1000000000},
+		},
+		{
+			params: map[string]string{topolvm.GetReadIOPSKey(): "1k"},
+			isErr:  true,
+		},
+		{
+			params: map[string]string{topolvm.GetWriteIOPSKey(): "0"},
+			isErr:  true,
+		},
+		{
+			params: map[string]string{topolvm.GetReadBPSKey(): "-1Mi"},
+			isErr:  true,
+		},
+		{
+			params: map[string]string{topolvm.GetWriteBPSKey(): "fast"},
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		actual, err := ioLimitsFromParameters(tc.params)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("should be error: %v", tc.params)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %v: %v", tc.params, err)
+			continue
+		}
+		if (actual == nil) != (tc.expected == nil) || (actual != nil && *actual != *tc.expected) {
+			t.Errorf("unexpected limits: %v: expected=%v, actual=%v", tc.params, tc.expected, actual)
+			continue
+		}
+
+		// limits passed through the volume context should be the same
+		roundTrip, err := ioLimitsFromParameters(ioLimitsToVolumeContext(actual))
+		if err != nil {
+			t.Errorf("unexpected error in volume context: %v", err)
+			continue
+		}
+		if (roundTrip == nil) != (actual == nil) || (roundTrip != nil && *roundTrip != *actual) {
+			t.Errorf("limits in volume context mismatch: expected=%v, actual=%v", actual, roundTrip)
+		}
+	}
+}
+
+func TestPodUIDFromTargetPath(t *testing.T) {
+	testCases := []struct {
+		targetPath string
+		expected   string
+		isErr      bool
+	}{
+		{
+			targetPath: "/var/lib/kubelet/pods/0a8b4d2c-1234-5678-9abc-def012345678/volumes/kubernetes.io~csi/pvc-abc/mount",
+			expected:   "0a8b4d2c-1234-5678-9abc-def012345678",
+		},
+		{
+			targetPath: "/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/pvc-abc/0a8b4d2c-1234-5678-9abc-def012345678",
+			expected:   "0a8b4d2c-1234-5678-9abc-def012345678",
+		},
+		{
+			targetPath: "/tmp/target",
+			isErr:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		actual, err := podUIDFromTargetPath(tc.targetPath)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("should be error: %s", tc.targetPath)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %s: %v", tc.targetPath, err)
+			continue
+		}
+		if actual != tc.expected {
+			t.Errorf("unexpected pod UID: %s: expected=%s, actual=%s", tc.targetPath, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package driver
+
+import (
+	"github.com/topolvm/topolvm/lvmd/proto"
+	mountutil "k8s.io/mount-utils"
+)
+
+// Volumes published before NodeStageVolume was supported are mounted directly on their target paths.
+// While such a volume is in use, its filesystem must not be checked, grown or formatted,
+// and new staging and target paths are bind-mounted from the existing mount.
+
+// deviceMounts returns the mounts of the root of the filesystem on the device except for stagingPath.
+// btrfs reports an anonymous device number in mountinfo, so the mount source is compared as well.
+func deviceMounts(mountInfos []mountutil.MountInfo, device string, lv *proto.LogicalVolume, stagingPath string) []mountutil.MountInfo {
+	var mounts []mountutil.MountInfo
+	for _, mi := range mountInfos {
+		if mi.Root != "/" || mi.MountPoint == stagingPath {
+			continue
+		}
+		if mi.Source == device || (mi.Major == int(lv.DevMajor) && mi.Minor == int(lv.DevMinor)) {
+			mounts = append(mounts, mi)
+		}
+	}
+	return mounts
+}
+
+// bindSource returns the mount point in mounts from which a new mount can be bind-mounted, or an empty string.
+// A bind mount inherits the read-only flag of the source, so a read-write mount is required unless readOnly is true.
+func bindSource(mounts []mountutil.MountInfo, readOnly bool) string {
+	for _, mi := range mounts {
+		if readOnly || (hasOption(mi.MountOptions, "rw") && !hasOption(mi.SuperOptions, "ro")) {
+			return mi.MountPoint
+		}
+	}
+	return ""
+}
+
+// findDeviceMounts reads mountinfo and returns the mounts of the device except for stagingPath.
+func findDeviceMounts(device string, lv *proto.LogicalVolume, stagingPath string) ([]mountutil.MountInfo, error) {
+	mountInfos, err := mountutil.ParseMountInfo(mountInfoPath)
+	if err != nil {
+		return nil, err
+	}
+	return deviceMounts(mountInfos, device, lv, stagingPath), nil
+}
+
+// mountInUse mounts the filesystem of the device, which is already mounted elsewhere, on target
+// without checking, growing or formatting it.
+func (s *nodeServerNoLocked) mountInUse(device, target, fsType string, options []string, mounts []mountutil.MountInfo, readOnly bool) error {
+	if source := bindSource(mounts, readOnly); source != "" {
+		bindOptions := []string{"bind"}
+		if readOnly {
+			bindOptions = append(bindOptions, "ro")
+		}
+		return s.mounter.Mount(source, target, "", bindOptions)
+	}
+	// Mounting the device again shares the superblock with the existing mounts.
+	return s.mounter.Mount(device, target, fsType, options)
+}
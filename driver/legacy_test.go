@@ -0,0 +1,103 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/proto"
+	mountutil "k8s.io/mount-utils"
+)
+
+func TestDeviceMounts(t *testing.T) {
+	const (
+		device      = "/dev/topolvm/vol"
+		stagingPath = "/var/lib/kubelet/plugins/kubernetes.io/csi/topolvm.io/hash/globalmount"
+		legacyPath  = "/var/lib/kubelet/pods/uid/volumes/kubernetes.io~csi/pvc/mount"
+		otherPath   = "/var/lib/kubelet/pods/uid2/volumes/kubernetes.io~csi/pvc/mount"
+	)
+	lv := &proto.LogicalVolume{Name: "vol", DevMajor: 253, DevMinor: 3}
+	rw := []string{"rw", "relatime"}
+	ro := []string{"ro", "relatime"}
+
+	testCases := []struct {
+		name       string
+		mountInfos []mountutil.MountInfo
+		mounts     []string
+		rwSource   string
+		roSource   string
+	}{
+		{
+			name: "not mounted",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 253, Minor: 4, Root: "/", Source: "/dev/topolvm/other", MountPoint: otherPath, MountOptions: rw, SuperOptions: rw},
+			},
+		},
+		{
+			name: "staged only",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 253, Minor: 3, Root: "/", Source: device, MountPoint: stagingPath, MountOptions: rw, SuperOptions: rw},
+			},
+		},
+		{
+			name: "published by an older version",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 253, Minor: 3, Root: "/", Source: device, MountPoint: legacyPath, MountOptions: rw, SuperOptions: rw},
+			},
+			mounts:   []string{legacyPath},
+			rwSource: legacyPath,
+			roSource: legacyPath,
+		},
+		{
+			name: "btrfs with anonymous device number",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 0, Minor: 52, Root: "/", Source: device, MountPoint: legacyPath, MountOptions: rw, SuperOptions: rw},
+			},
+			mounts:   []string{legacyPath},
+			rwSource: legacyPath,
+			roSource: legacyPath,
+		},
+		{
+			name: "published read-only by an older version",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 253, Minor: 3, Root: "/", Source: device, MountPoint: legacyPath, MountOptions: ro, SuperOptions: rw},
+				{Major: 253, Minor: 3, Root: "/", Source: device, MountPoint: otherPath, MountOptions: rw, SuperOptions: rw},
+			},
+			mounts:   []string{legacyPath, otherPath},
+			rwSource: otherPath,
+			roSource: legacyPath,
+		},
+		{
+			name: "read-only superblock",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 253, Minor: 3, Root: "/", Source: device, MountPoint: legacyPath, MountOptions: rw, SuperOptions: ro},
+			},
+			mounts:   []string{legacyPath},
+			roSource: legacyPath,
+		},
+		{
+			name: "subdirectory bind mount",
+			mountInfos: []mountutil.MountInfo{
+				{Major: 253, Minor: 3, Root: "/dir", Source: device, MountPoint: otherPath, MountOptions: rw, SuperOptions: rw},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mounts := deviceMounts(tc.mountInfos, device, lv, stagingPath)
+			if len(mounts) != len(tc.mounts) {
+				t.Fatalf("expected %v, actual %v", tc.mounts, mounts)
+			}
+			for i, mi := range mounts {
+				if mi.MountPoint != tc.mounts[i] {
+					t.Errorf("expected %v, actual %v", tc.mounts, mounts)
+				}
+			}
+			if source := bindSource(mounts, false); source != tc.rwSource {
+				t.Errorf("read-write bind source: expected %q, actual %q", tc.rwSource, source)
+			}
+			if source := bindSource(mounts, true); source != tc.roSource {
+				t.Errorf("read-only bind source: expected %q, actual %q", tc.roSource, source)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/topolvm/topolvm"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/driver/internal/k8s"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *controllerServer) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	// This reads kube-apiserver only, it is unnecessary to take lock.
+	return s.server.ListVolumes(ctx, req)
+}
+
+func (s *controllerServer) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	// This reads kube-apiserver only, it is unnecessary to take lock.
+	return s.server.ListSnapshots(ctx, req)
+}
+
+func (s *controllerServer) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	s.lockByVolumeID.LockByID(req.GetVolumeId())
+	defer s.lockByVolumeID.UnlockByID(req.GetVolumeId())
+
+	return s.server.ControllerGetVolume(ctx, req)
+}
+
+// ListVolumes lists the volumes in the order of the names of LogicalVolumes.
+// The token is the index of the first entry to be returned.
+func (s controllerServerNoLocked) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	ctrlLogger.Info("ListVolumes called",
+		"max_entries", req.GetMaxEntries(),
+		"starting_token", req.GetStartingToken())
+
+	lvs, err := s.lvService.ListVolumes(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sortByName(lvs)
+
+	var volumes []*v1.LogicalVolume
+	for i := range lvs {
+		lv := &lvs[i]
+		if lv.Status.VolumeID == "" || isSnapshot(lv) {
+			continue
+		}
+		volumes = append(volumes, lv)
+	}
+
+	start, end, nextToken, err := paginate(len(volumes), req.GetMaxEntries(), req.GetStartingToken())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*csi.ListVolumesResponse_Entry, 0, end-start)
+	for _, lv := range volumes[start:end] {
+		entries = append(entries, &csi.ListVolumesResponse_Entry{
+			Volume: volumeOf(lv),
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				PublishedNodeIds: publishedNodeIDs(lv),
+				VolumeCondition:  volumeConditionOf(lv),
+			},
+		})
+	}
+	return &csi.ListVolumesResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
+}
+
+// ListSnapshots lists the snapshots in the order of the names of LogicalVolumes.
+// The token is the index of the first entry to be returned.
+func (s controllerServerNoLocked) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	ctrlLogger.Info("ListSnapshots called",
+		"max_entries", req.GetMaxEntries(),
+		"starting_token", req.GetStartingToken(),
+		"source_volume_id", req.GetSourceVolumeId(),
+		"snapshot_id", req.GetSnapshotId(),
+		"num_secrets", len(req.GetSecrets()))
+
+	lvs, err := s.lvService.ListVolumes(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sortByName(lvs)
+
+	// The source of a snapshot is the name of LogicalVolume, not the volume ID.
+	volumeIDs := make(map[string]string)
+	for _, lv := range lvs {
+		volumeIDs[lv.Name] = lv.Status.VolumeID
+	}
+
+	var snapshots []*v1.LogicalVolume
+	for i := range lvs {
+		lv := &lvs[i]
+		if lv.Status.VolumeID == "" || !isSnapshot(lv) {
+			continue
+		}
+		if req.GetSnapshotId() != "" && lv.Status.VolumeID != req.GetSnapshotId() {
+			continue
+		}
+		if req.GetSourceVolumeId() != "" && volumeIDs[lv.Spec.Source] != req.GetSourceVolumeId() {
+			continue
+		}
+		snapshots = append(snapshots, lv)
+	}
+
+	start, end, nextToken, err := paginate(len(snapshots), req.GetMaxEntries(), req.GetStartingToken())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*csi.ListSnapshotsResponse_Entry, 0, end-start)
+	for _, lv := range snapshots[start:end] {
+		entries = append(entries, &csi.ListSnapshotsResponse_Entry{
+			Snapshot: &csi.Snapshot{
+				SnapshotId:      lv.Status.VolumeID,
+				SourceVolumeId:  volumeIDs[lv.Spec.Source],
+				SizeBytes:       lv.Spec.Size.Value(),
+				CreationTime:    timestamppb.New(lv.CreationTimestamp.Time),
+				ReadyToUse:      true,
+				GroupSnapshotId: lv.Labels[topolvm.GetGroupSnapshotKey()],
+			},
+		})
+	}
+	return &csi.ListSnapshotsResponse{
+		Entries:   entries,
+		NextToken: nextToken,
+	}, nil
+}
+
+func (s controllerServerNoLocked) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
+	ctrlLogger.Info("ControllerGetVolume called",
+		"volume_id", req.GetVolumeId())
+
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing volume id")
+	}
+
+	lv, err := s.lvService.GetVolume(ctx, req.GetVolumeId())
+	if err != nil {
+		if errors.Is(err, k8s.ErrVolumeNotFound) {
+			return nil, status.Errorf(codes.NotFound, "LogicalVolume for volume id %s is not found", req.GetVolumeId())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if isSnapshot(lv) {
+		return nil, status.Errorf(codes.NotFound, "volume id %s is a snapshot", req.GetVolumeId())
+	}
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: volumeOf(lv),
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: publishedNodeIDs(lv),
+			VolumeCondition:  volumeConditionOf(lv),
+		},
+	}, nil
+}
+
+// isSnapshot returns true if the LogicalVolume is a snapshot created by CreateSnapshot.
+// Volumes cloned or restored from a source are writable.
+func isSnapshot(lv *v1.LogicalVolume) bool {
+	return lv.Spec.Source != "" && lv.Spec.AccessType == "ro"
+}
+
+func volumeOf(lv *v1.LogicalVolume) *csi.Volume {
+	size := lv.Spec.Size
+	if lv.Status.CurrentSize != nil {
+		size = *lv.Status.CurrentSize
+	}
+	return &csi.Volume{
+		CapacityBytes: size.Value(),
+		VolumeId:      lv.Status.VolumeID,
+		AccessibleTopology: []*csi.Topology{
+			{
+				Segments: map[string]string{topolvm.GetTopologyNodeKey(): lv.Spec.NodeName},
+			},
+		},
+	}
+}
+
+// publishedNodeIDs returns the node where the volume is published.
+// TopoLVM volumes are published only on the node where they are created.
+func publishedNodeIDs(lv *v1.LogicalVolume) []string {
+	if len(lv.Status.PublishedTargets) == 0 {
+		return nil
+	}
+	return []string{lv.Spec.NodeName}
+}
+
+// volumeConditionOf returns the condition of the volume derived from the status of LogicalVolume.
+func volumeConditionOf(lv *v1.LogicalVolume) *csi.VolumeCondition {
+	if lv.Status.Code != codes.OK {
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  lv.Status.Code.String() + ": " + lv.Status.Message,
+		}
+	}
+	if lv.Status.CurrentSize != nil && lv.Status.CurrentSize.Cmp(lv.Spec.Size) < 0 {
+		return &csi.VolumeCondition{
+			Abnormal: false,
+			Message:  "volume is being expanded",
+		}
+	}
+	return &csi.VolumeCondition{
+		Abnormal: false,
+		Message:  "volume is healthy",
+	}
+}
+
+// paginate returns the range of entries to be returned and the next token.
+// The token is the index of the first entry.
+func paginate(total int, maxEntries int32, startingToken string) (int, int, string, error) {
+	if maxEntries < 0 {
+		return 0, 0, "", status.Error(codes.InvalidArgument, "max_entries must not be negative")
+	}
+
+	start := 0
+	if startingToken != "" {
+		var err error
+		start, err = strconv.Atoi(startingToken)
+		if err != nil || start < 0 || start > total {
+			return 0, 0, "", status.Errorf(codes.Aborted, "invalid starting token %s", startingToken)
+		}
+	}
+
+	end := total
+	if maxEntries > 0 && start+int(maxEntries) < total {
+		end = start + int(maxEntries)
+	}
+
+	var nextToken string
+	if end < total {
+		nextToken = strconv.Itoa(end)
+	}
+	return start, end, nextToken, nil
+}
+
+// sortByName sorts the LogicalVolumes by their names to make the pagination stable.
+func sortByName(lvs []v1.LogicalVolume) {
+	sort.Slice(lvs, func(i, j int) bool {
+		return lvs[i].Name < lvs[j].Name
+	})
+}
@@ -0,0 +1,143 @@
+package driver
+
+import (
+	"testing"
+
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPaginate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		total         int
+		maxEntries    int32
+		startingToken string
+		start         int
+		end           int
+		nextToken     string
+		code          codes.Code
+	}{
+		{
+			name:  "all entries",
+			total: 3,
+			start: 0,
+			end:   3,
+		},
+		{
+			name:       "first page",
+			total:      3,
+			maxEntries: 2,
+			start:      0,
+			end:        2,
+			nextToken:  "2",
+		},
+		{
+			name:          "last page",
+			total:         3,
+			maxEntries:    2,
+			startingToken: "2",
+			start:         2,
+			end:           3,
+		},
+		{
+			name:          "max entries equal to the rest",
+			total:         4,
+			maxEntries:    2,
+			startingToken: "2",
+			start:         2,
+			end:           4,
+		},
+		{
+			name:          "token at the end",
+			total:         3,
+			startingToken: "3",
+			start:         3,
+			end:           3,
+		},
+		{
+			name:          "token beyond the end",
+			total:         3,
+			startingToken: "4",
+			code:          codes.Aborted,
+		},
+		{
+			name:          "invalid token",
+			total:         3,
+			startingToken: "abc",
+			code:          codes.Aborted,
+		},
+		{
+			name:       "negative max entries",
+			total:      3,
+			maxEntries: -1,
+			code:       codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		start, end, nextToken, err := paginate(tc.total, tc.maxEntries, tc.startingToken)
+		if code := status.Code(err); code != tc.code {
+			t.Errorf("%s: expected code=%s actual=%s", tc.name, tc.code, code)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if start != tc.start || end != tc.end || nextToken != tc.nextToken {
+			t.Errorf("%s: expected=(%d, %d, %q) actual=(%d, %d, %q)",
+				tc.name, tc.start, tc.end, tc.nextToken, start, end, nextToken)
+		}
+	}
+}
+
+func TestVolumeConditionOf(t *testing.T) {
+	size := resource.MustParse("1Gi")
+	smaller := resource.MustParse("512Mi")
+
+	testCases := []struct {
+		name     string
+		lv       *v1.LogicalVolume
+		abnormal bool
+	}{
+		{
+			name: "healthy",
+			lv: &v1.LogicalVolume{
+				Spec:   v1.LogicalVolumeSpec{Size: size},
+				Status: v1.LogicalVolumeStatus{CurrentSize: &size},
+			},
+			abnormal: false,
+		},
+		{
+			name: "expanding",
+			lv: &v1.LogicalVolume{
+				Spec:   v1.LogicalVolumeSpec{Size: size},
+				Status: v1.LogicalVolumeStatus{CurrentSize: &smaller},
+			},
+			abnormal: false,
+		},
+		{
+			name: "error",
+			lv: &v1.LogicalVolume{
+				Spec: v1.LogicalVolumeSpec{Size: size},
+				Status: v1.LogicalVolumeStatus{
+					Code:    codes.Internal,
+					Message: "failed to create LV",
+				},
+			},
+			abnormal: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		condition := volumeConditionOf(tc.lv)
+		if condition.GetAbnormal() != tc.abnormal {
+			t.Errorf("%s: expected abnormal=%t actual=%t", tc.name, tc.abnormal, condition.GetAbnormal())
+		}
+		if condition.GetMessage() == "" {
+			t.Errorf("%s: message should not be empty", tc.name)
+		}
+	}
+}
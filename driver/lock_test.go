@@ -54,3 +54,35 @@ func TestAllowConcurrentExecutionByDifferentID(t *testing.T) {
 		t.Error("Failed to allow concurrent execution")
 	}
 }
+
+func TestLockCapacity(t *testing.T) {
+	s := controllerServerNoLocked{lockByCapacity: NewLockWithID()}
+
+	unlock := s.lockCapacity("node1", []string{"ssd", "hdd", "ssd"})
+
+	c := make(chan struct{})
+	go func() {
+		// the locks are taken in the same order regardless of the given order.
+		unlock := s.lockCapacity("node1", []string{"hdd"})
+		defer unlock()
+
+		close(c)
+	}()
+
+	// another node is not blocked.
+	unlock2 := s.lockCapacity("node2", []string{"ssd"})
+	unlock2()
+
+	select {
+	case <-c:
+		t.Error("Failed to prevent concurrent capacity check on the same device-class")
+	case <-time.After(time.Second):
+		// success
+	}
+
+	unlock()
+	// calling twice must not panic.
+	unlock()
+
+	<-c
+}
@@ -2,6 +2,7 @@ package driver
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/topolvm/topolvm"
+	v1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/driver/internal/cgroup"
 	"github.com/topolvm/topolvm/driver/internal/k8s"
 	"github.com/topolvm/topolvm/filesystem"
 	"github.com/topolvm/topolvm/lvmd/proto"
@@ -18,9 +21,12 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	mountutil "k8s.io/mount-utils"
 	utilexec "k8s.io/utils/exec"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
@@ -48,10 +54,13 @@ func NewNodeServer(nodeName string, conn *grpc.ClientConn, mgr manager.Manager)
 			client:       proto.NewVGServiceClient(conn),
 			lvService:    proto.NewLVServiceClient(conn),
 			k8sLVService: lvService,
+			apiReader:    mgr.GetAPIReader(),
+			recorder:     mgr.GetEventRecorderFor("topolvm-node"),
 			mounter: mountutil.SafeFormatAndMount{
 				Interface: mountutil.New(""),
 				Exec:      utilexec.New(),
 			},
+			lvLists: newLVListCache(),
 		},
 	}, nil
 }
@@ -67,6 +76,20 @@ type nodeServer struct {
 	server *nodeServerNoLocked
 }
 
+func (s *nodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.server.NodeStageVolume(ctx, req)
+}
+
+func (s *nodeServer) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.server.NodeUnstageVolume(ctx, req)
+}
+
 func (s *nodeServer) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -115,7 +138,256 @@ type nodeServerNoLocked struct {
 	client       proto.VGServiceClient
 	lvService    proto.LVServiceClient
 	k8sLVService *k8s.LogicalVolumeService
+	apiReader    client.Reader
+	recorder     record.EventRecorder
 	mounter      mountutil.SafeFormatAndMount
+	lvLists      *lvListCache
+}
+
+func (s *nodeServerNoLocked) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+
+	nodeLogger.Info("NodeStageVolume called",
+		"volume_id", volumeID,
+		"publish_context", req.GetPublishContext(),
+		"staging_target_path", req.GetStagingTargetPath(),
+		"volume_capability", req.GetVolumeCapability(),
+		"num_secrets", len(req.GetSecrets()),
+		"volume_context", req.GetVolumeContext())
+
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no volume_id is provided")
+	}
+	if len(req.GetStagingTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no staging_target_path is provided")
+	}
+	if req.GetVolumeCapability() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no volume_capability is provided")
+	}
+	isBlockVol := req.GetVolumeCapability().GetBlock() != nil
+	isFsVol := req.GetVolumeCapability().GetMount() != nil
+	if !(isBlockVol || isFsVol) {
+		return nil, status.Errorf(codes.InvalidArgument, "no supported volume capability: %v", req.GetVolumeCapability())
+	}
+	accessMode := req.GetVolumeCapability().GetAccessMode().GetMode()
+	if !isSupportedAccessMode(accessMode) {
+		modeName := csi.VolumeCapability_AccessMode_Mode_name[int32(accessMode)]
+		return nil, status.Errorf(codes.FailedPrecondition, "unsupported access mode: %s (%d)", modeName, accessMode)
+	}
+
+	// Block volumes are published as device files, so there is nothing to stage.
+	if isBlockVol {
+		nodeLogger.Info("NodeStageVolume(block) is skipped",
+			"volume_id", volumeID,
+			"staging_target_path", req.GetStagingTargetPath())
+		return &csi.NodeStageVolumeResponse{}, nil
+	}
+
+	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	lv, err := s.getLvFromContext(ctx, lvr.Spec.DeviceClass, volumeID)
+	if err != nil {
+		return nil, err
+	}
+	if lv == nil {
+		return nil, status.Errorf(codes.NotFound, "failed to find LV: %s", volumeID)
+	}
+
+	err = s.nodeStageFilesystemVolume(ctx, req, lvr, lv, isReadOnlyAccessMode(accessMode))
+	if err != nil {
+		return nil, err
+	}
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+// nodeStageFilesystemVolume formats the volume if needed and mounts it on the staging path.
+// The staged filesystem is bind-mounted on each target path by NodePublishVolume.
+// The mkfs and mount options, and the filesystem check policy recorded in LogicalVolume are used for every staging.
+func (s *nodeServerNoLocked) nodeStageFilesystemVolume(ctx context.Context, req *csi.NodeStageVolumeRequest, lvr *v1.LogicalVolume, lv *proto.LogicalVolume, readOnly bool) error {
+	// Check request
+	mountOption := req.GetVolumeCapability().GetMount()
+	if mountOption.FsType == "" {
+		mountOption.FsType = defaultFsType
+	}
+	stagingPath := req.GetStagingTargetPath()
+
+	mkfsArgs, err := filesystem.ParseMkfsOptions(mountOption.FsType, lvr.Annotations[topolvm.GetMkfsOptionsKey()])
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid mkfs options of volume %s: %v", req.GetVolumeId(), err)
+	}
+	profileMountOptions, err := filesystem.ParseMountOptions(lvr.Annotations[topolvm.GetMountOptionsKey()])
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid mount options of volume %s: %v", req.GetVolumeId(), err)
+	}
+
+	// Find lv and create a block device with it
+	device := filepath.Join(DeviceDirectory, req.GetVolumeId())
+	err = s.createDeviceIfNeeded(device, lv)
+	if err != nil {
+		return err
+	}
+
+	var mountOptions []string
+	if readOnly {
+		mountOptions = append(mountOptions, "ro")
+	}
+	mountOptions = append(mountOptions, profileMountOptions...)
+
+	for _, f := range mountOption.MountFlags {
+		if f == "rw" && readOnly {
+			return status.Error(codes.InvalidArgument, "mount option \"rw\" is specified even though read only mode is specified")
+		}
+		mountOptions = append(mountOptions, f)
+	}
+
+	err = os.MkdirAll(stagingPath, 0755)
+	if err != nil {
+		return status.Errorf(codes.Internal, "mkdir failed: target=%s, error=%v", stagingPath, err)
+	}
+
+	fsType, err := filesystem.DetectFilesystem(device)
+	if err != nil {
+		return status.Errorf(codes.Internal, "filesystem check failed: volume=%s, error=%v", req.GetVolumeId(), err)
+	}
+
+	if fsType != "" && fsType != mountOption.FsType {
+		return status.Errorf(codes.Internal, "target device is already formatted with different filesystem: volume=%s, current=%s, new:%s", req.GetVolumeId(), fsType, mountOption.FsType)
+	}
+
+	// avoid duplicate UUIDs
+	if mountOption.FsType == "xfs" {
+		mountOptions = append(mountOptions, "nouuid")
+	}
+
+	mounted, err := filesystem.IsMounted(device, stagingPath)
+	if err != nil {
+		return status.Errorf(codes.Internal, "mount check failed: target=%s, error=%v", stagingPath, err)
+	}
+	// A volume published before NodeStageVolume was supported is mounted on its target path.
+	mounts, err := findDeviceMounts(device, lv, stagingPath)
+	if err != nil {
+		return status.Errorf(codes.Internal, "mount check failed: volume=%s, error=%v", req.GetVolumeId(), err)
+	}
+
+	if !mounted && len(mounts) != 0 {
+		if err := s.mountInUse(device, stagingPath, mountOption.FsType, mountOptions, mounts, readOnly); err != nil {
+			return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
+		}
+		nodeLogger.Info("staged the volume in use without checking the filesystem",
+			"volume_id", req.GetVolumeId(),
+			"mount_point", mounts[0].MountPoint)
+	} else if !mounted {
+		if fsType != "" && !readOnly {
+			if err := s.checkFilesystem(ctx, lvr, device, fsType); err != nil {
+				return err
+			}
+		}
+		// f2fs cannot be resized online, so it is grown before it is mounted.
+		if fsType == "f2fs" && !readOnly {
+			if err := growF2fsIfNeeded(device); err != nil {
+				return status.Errorf(codes.Internal, "failed to resize filesystem: volume=%s, error=%v", req.GetVolumeId(), err)
+			}
+		}
+		// FormatAndMount formats the device with the default arguments,
+		// so the device is formatted in advance if mkfs options are specified.
+		if fsType == "" && len(mkfsArgs) != 0 && !readOnly {
+			if err := s.formatDevice(device, mountOption.FsType, mkfsArgs); err != nil {
+				return status.Errorf(codes.Internal, "format failed: volume=%s, error=%v", req.GetVolumeId(), err)
+			}
+		}
+		// A formatted device is mounted without FormatAndMount because it runs fsck regardless of the policy.
+		if fsType == "" {
+			err = s.mounter.FormatAndMount(device, stagingPath, mountOption.FsType, mountOptions)
+		} else {
+			err = s.mounter.Mount(device, stagingPath, mountOption.FsType, mountOptions)
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
+		}
+		if err := os.Chmod(stagingPath, 0777|os.ModeSetgid); err != nil {
+			return status.Errorf(codes.Internal, "chmod 2777 failed: target=%s, error=%v", stagingPath, err)
+		}
+		// A volume cloned or restored into a larger size has the filesystem of the source size.
+		if fsType != "" && fsType != "f2fs" && !readOnly {
+			if err := s.growFilesystemIfNeeded(device, stagingPath); err != nil {
+				return status.Errorf(codes.Internal, "failed to resize filesystem: volume=%s, error=%v", req.GetVolumeId(), err)
+			}
+		}
+	}
+
+	nodeLogger.Info("NodeStageVolume(fs) succeeded",
+		"volume_id", req.GetVolumeId(),
+		"staging_target_path", stagingPath,
+		"fstype", mountOption.FsType)
+
+	return nil
+}
+
+// formatDevice creates a filesystem on the device with the mkfs options.
+func (s *nodeServerNoLocked) formatDevice(device, fsType string, options []string) error {
+	args := mkfsArgs(device, fsType, options)
+	out, err := s.mounter.Exec.Command("mkfs."+fsType, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mkfs.%s failed: args=%v, error=%w, output=%s", fsType, args, err, strings.TrimSpace(string(out)))
+	}
+	nodeLogger.Info("device is formatted",
+		"device", device,
+		"fstype", fsType,
+		"args", args)
+	return nil
+}
+
+func (s *nodeServerNoLocked) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	stagingPath := req.GetStagingTargetPath()
+	nodeLogger.Info("NodeUnstageVolume called",
+		"volume_id", volumeID,
+		"staging_target_path", stagingPath)
+
+	if len(volumeID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no volume_id is provided")
+	}
+	if len(stagingPath) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no staging_target_path is provided")
+	}
+
+	// The staging path is not mounted for block volumes and volumes published before
+	// NodeStageVolume was supported, so a missing or unmounted path is not an error.
+	notMnt, err := s.mounter.IsLikelyNotMountPoint(stagingPath)
+	switch {
+	case os.IsNotExist(err):
+		notMnt = true
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "mount check failed: target=%s, error=%v", stagingPath, err)
+	}
+	if !notMnt {
+		if err := s.mounter.Unmount(stagingPath); err != nil {
+			return nil, status.Errorf(codes.Internal, "unmount failed for %s: error=%v", stagingPath, err)
+		}
+	}
+
+	// The device file is still needed if the volume is published without staging.
+	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
+	switch {
+	case err == k8s.ErrVolumeNotFound:
+		lvr = nil
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to get LogicalVolume: volume=%s, error=%v", volumeID, err)
+	}
+	if lvr == nil || len(livePublishedTargets(lvr.Status.PublishedTargets)) == 0 {
+		device := filepath.Join(DeviceDirectory, volumeID)
+		err = os.Remove(device)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, status.Errorf(codes.Internal, "remove device failed for %s: error=%v", device, err)
+		}
+	}
+
+	nodeLogger.Info("NodeUnstageVolume is succeeded",
+		"volume_id", volumeID,
+		"staging_target_path", stagingPath)
+	return &csi.NodeUnstageVolumeResponse{}, nil
 }
 
 func (s *nodeServerNoLocked) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
@@ -145,14 +417,18 @@ func (s *nodeServerNoLocked) NodePublishVolume(ctx context.Context, req *csi.Nod
 	if !(isBlockVol || isFsVol) {
 		return nil, status.Errorf(codes.InvalidArgument, "no supported volume capability: %v", req.GetVolumeCapability())
 	}
-	// we only support SINGLE_NODE_WRITER
+	if isFsVol && len(req.GetStagingTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no staging_target_path is provided")
+	}
 	accessMode := req.GetVolumeCapability().GetAccessMode().GetMode()
-	switch accessMode {
-	case csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:
-	default:
+	if !isSupportedAccessMode(accessMode) {
 		modeName := csi.VolumeCapability_AccessMode_Mode_name[int32(accessMode)]
 		return nil, status.Errorf(codes.FailedPrecondition, "unsupported access mode: %s (%d)", modeName, accessMode)
 	}
+	readOnly := req.GetReadonly() || isReadOnlyAccessMode(accessMode)
+	if err := checkReadOnlyBlock(isBlockVol, readOnly); err != nil {
+		return nil, err
+	}
 
 	var lv *proto.LogicalVolume
 	var err error
@@ -169,41 +445,114 @@ func (s *nodeServerNoLocked) NodePublishVolume(ctx context.Context, req *csi.Nod
 		return nil, status.Errorf(codes.NotFound, "failed to find LV: %s", volumeID)
 	}
 
+	// The volume may be published at other target paths on this node.
+	err = checkPublishedTargets(livePublishedTargets(lvr.Status.PublishedTargets), req.GetTargetPath(), accessMode, readOnly)
+	if err != nil {
+		return nil, err
+	}
+
 	if isBlockVol {
 		err = s.nodePublishBlockVolume(req, lv)
 	} else if isFsVol {
-		err = s.nodePublishFilesystemVolume(req, lv)
+		err = s.nodePublishFilesystemVolume(req, lv, readOnly)
+	}
+	if err != nil {
+		return nil, err
 	}
+
+	err = s.k8sLVService.AddPublishedTarget(ctx, volumeID, v1.PublishedTarget{Path: req.GetTargetPath(), ReadOnly: readOnly})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update LogicalVolume status: volume=%s, error=%v", volumeID, err)
+	}
+
+	err = s.applyIOLimits(ctx, req, lvr, lv)
 	if err != nil {
 		return nil, err
 	}
 	return &csi.NodePublishVolumeResponse{}, nil
 }
 
-func (s *nodeServerNoLocked) nodePublishFilesystemVolume(req *csi.NodePublishVolumeRequest, lv *proto.LogicalVolume) error {
-	// Check request
-	mountOption := req.GetVolumeCapability().GetMount()
-	if mountOption.FsType == "" {
-		mountOption.FsType = "ext4"
+// applyIOLimits sets the I/O limits in the volume context to io.max of the pod cgroup.
+// This is called on every publish, so the limits are applied again to new pods or after node restarts.
+// If the node cannot limit I/O of the pod, the volume is published without the limits,
+// and the reason is recorded in the LogicalVolume status and an event of the PVC.
+func (s *nodeServerNoLocked) applyIOLimits(ctx context.Context, req *csi.NodePublishVolumeRequest, lvr *v1.LogicalVolume, lv *proto.LogicalVolume) error {
+	limits, err := ioLimitsFromParameters(req.GetVolumeContext())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if limits == nil {
+		return nil
 	}
 
-	// Find lv and create a block device with it
-	device := filepath.Join(DeviceDirectory, req.GetVolumeId())
-	err := s.createDeviceIfNeeded(device, lv)
+	podUID, err := podUIDFromTargetPath(req.GetTargetPath())
 	if err != nil {
-		return err
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	dir, err := cgroup.FindPodCgroup(podUID)
+	switch err {
+	case nil:
+	case cgroup.ErrPodCgroupNotFound:
+		// kubelet may publish volumes before the pod cgroup becomes visible.
+		return status.Errorf(codes.Unavailable, "failed to apply I/O limits: pod=%s, error=%v", podUID, err)
+	case cgroup.ErrNotCgroupV2:
+		return s.skipIOLimits(ctx, req.GetVolumeId(), lvr, fmt.Sprintf("I/O limits are not applied to pod %s: %v", podUID, err))
+	default:
+		return status.Errorf(codes.Internal, "failed to find cgroup: pod=%s, error=%v", podUID, err)
+	}
+	err = cgroup.SetIOMax(dir, cgroup.IOMax{
+		Major:     lv.DevMajor,
+		Minor:     lv.DevMinor,
+		ReadBPS:   limits.ReadBPS,
+		WriteBPS:  limits.WriteBPS,
+		ReadIOPS:  limits.ReadIOPS,
+		WriteIOPS: limits.WriteIOPS,
+	})
+	if err != nil {
+		if err == cgroup.ErrIOControllerDisabled {
+			return s.skipIOLimits(ctx, req.GetVolumeId(), lvr, fmt.Sprintf("I/O limits are not applied to pod %s: %v", podUID, err))
+		}
+		return status.Errorf(codes.Internal, "failed to set io.max: cgroup=%s, error=%v", dir, err)
 	}
 
-	var mountOptions []string
-	if req.GetReadonly() {
-		mountOptions = append(mountOptions, "ro")
+	if err := s.k8sLVService.UpdateIOLimits(ctx, req.GetVolumeId(), limits, ""); err != nil {
+		return status.Errorf(codes.Internal, "failed to update LogicalVolume status: volume=%s, error=%v", req.GetVolumeId(), err)
 	}
 
+	nodeLogger.Info("I/O limits are applied",
+		"volume_id", req.GetVolumeId(),
+		"cgroup", dir,
+		"limits", limits)
+	return nil
+}
+
+// skipIOLimits records why the I/O limits are not applied so that the volume can be published without them.
+// The limits already applied to other pods are kept in the status.
+func (s *nodeServerNoLocked) skipIOLimits(ctx context.Context, volumeID string, lvr *v1.LogicalVolume, message string) error {
+	nodeLogger.Info("I/O limits are not applied", "volume_id", volumeID, "message", message)
+	if err := s.k8sLVService.UpdateIOLimits(ctx, volumeID, lvr.Status.IOLimits, message); err != nil {
+		return status.Errorf(codes.Internal, "failed to update LogicalVolume status: volume=%s, error=%v", volumeID, err)
+	}
+	s.recordPVCEvent(ctx, lvr, corev1.EventTypeWarning, "IOLimitsNotApplied", message)
+	return nil
+}
+
+func (s *nodeServerNoLocked) nodePublishFilesystemVolume(req *csi.NodePublishVolumeRequest, lv *proto.LogicalVolume, readOnly bool) error {
+	// Check request
+	mountOption := req.GetVolumeCapability().GetMount()
+	if mountOption.FsType == "" {
+		mountOption.FsType = defaultFsType
+	}
 	for _, f := range mountOption.MountFlags {
-		if f == "rw" && req.GetReadonly() {
+		if f == "rw" && readOnly {
 			return status.Error(codes.InvalidArgument, "mount option \"rw\" is specified even though read only mode is specified")
 		}
-		mountOptions = append(mountOptions, f)
+	}
+
+	device := filepath.Join(DeviceDirectory, req.GetVolumeId())
+	err := s.createDeviceIfNeeded(device, lv)
+	if err != nil {
+		return err
 	}
 
 	err = os.MkdirAll(req.GetTargetPath(), 0755)
@@ -215,35 +564,127 @@ func (s *nodeServerNoLocked) nodePublishFilesystemVolume(req *csi.NodePublishVol
 	if err != nil {
 		return status.Errorf(codes.Internal, "filesystem check failed: volume=%s, error=%v", req.GetVolumeId(), err)
 	}
-
 	if fsType != "" && fsType != mountOption.FsType {
 		return status.Errorf(codes.Internal, "target device is already formatted with different filesystem: volume=%s, current=%s, new:%s", req.GetVolumeId(), fsType, mountOption.FsType)
 	}
 
-	// avoid duplicate UUIDs
-	if mountOption.FsType == "xfs" {
-		mountOptions = append(mountOptions, "nouuid")
-	}
-
+	// Volumes published before NodeStageVolume was supported are mounted directly on the target path.
+	// They are left as they are until they are unpublished.
 	mounted, err := filesystem.IsMounted(device, req.GetTargetPath())
 	if err != nil {
 		return status.Errorf(codes.Internal, "mount check failed: target=%s, error=%v", req.GetTargetPath(), err)
 	}
+	if mounted {
+		nodeLogger.Info("NodePublishVolume(fs) is already done",
+			"volume_id", req.GetVolumeId(),
+			"target_path", req.GetTargetPath())
+		return nil
+	}
 
-	if !mounted {
-		if err := s.mounter.FormatAndMount(device, req.GetTargetPath(), mountOption.FsType, mountOptions); err != nil {
-			return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
-		}
-		if err := os.Chmod(req.GetTargetPath(), 0777|os.ModeSetgid); err != nil {
-			return status.Errorf(codes.Internal, "chmod 2777 failed: target=%s, error=%v", req.GetTargetPath(), err)
-		}
+	staged, err := s.isStaged(device, req.GetStagingTargetPath())
+	if err != nil {
+		return err
+	}
+	if !staged {
+		return s.publishUnstagedVolume(req, device, lv, mountOption, readOnly)
+	}
+
+	// The read-only option is honoured for each publish, because the staged filesystem is shared.
+	mountOptions := []string{"bind"}
+	if readOnly {
+		mountOptions = append(mountOptions, "ro")
+	}
+	if err := s.mounter.Mount(req.GetStagingTargetPath(), req.GetTargetPath(), "", mountOptions); err != nil {
+		return status.Errorf(codes.Internal, "bind mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
 	}
 
 	nodeLogger.Info("NodePublishVolume(fs) succeeded",
 		"volume_id", req.GetVolumeId(),
+		"staging_target_path", req.GetStagingTargetPath(),
+		"target_path", req.GetTargetPath(),
+		"fstype", mountOption.FsType)
+
+	return nil
+}
+
+// publishUnstagedVolume mounts a volume that is not staged but is published on another target path
+// by an older version of TopoLVM.
+func (s *nodeServerNoLocked) publishUnstagedVolume(req *csi.NodePublishVolumeRequest, device string, lv *proto.LogicalVolume, mountOption *csi.VolumeCapability_MountVolume, readOnly bool) error {
+	mounts, err := findDeviceMounts(device, lv, req.GetStagingTargetPath())
+	if err != nil {
+		return status.Errorf(codes.Internal, "mount check failed: volume=%s, error=%v", req.GetVolumeId(), err)
+	}
+	if len(mounts) == 0 {
+		return status.Errorf(codes.FailedPrecondition, "volume is not staged: volume=%s, staging_target_path=%s", req.GetVolumeId(), req.GetStagingTargetPath())
+	}
+
+	var mountOptions []string
+	if readOnly {
+		mountOptions = append(mountOptions, "ro")
+	}
+	mountOptions = append(mountOptions, mountOption.MountFlags...)
+	if mountOption.FsType == "xfs" {
+		mountOptions = append(mountOptions, "nouuid")
+	}
+	if err := s.mountInUse(device, req.GetTargetPath(), mountOption.FsType, mountOptions, mounts, readOnly); err != nil {
+		return status.Errorf(codes.Internal, "mount failed: volume=%s, error=%v", req.GetVolumeId(), err)
+	}
+
+	nodeLogger.Info("NodePublishVolume(fs) succeeded with the volume in use",
+		"volume_id", req.GetVolumeId(),
+		"mount_point", mounts[0].MountPoint,
 		"target_path", req.GetTargetPath(),
 		"fstype", mountOption.FsType)
+	return nil
+}
+
+// isStaged returns true if device is mounted on stagingPath.
+func (s *nodeServerNoLocked) isStaged(device, stagingPath string) (bool, error) {
+	_, err := os.Stat(stagingPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "stat failed for %s: %v", stagingPath, err)
+	}
+	staged, err := filesystem.IsMounted(device, stagingPath)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "mount check failed: target=%s, error=%v", stagingPath, err)
+	}
+	return staged, nil
+}
 
+func (s *nodeServerNoLocked) growFilesystemIfNeeded(device, targetPath string) error {
+	r := mountutil.NewResizeFs(s.mounter.Exec)
+	needResize, err := r.NeedResize(device, targetPath)
+	if err != nil {
+		return err
+	}
+	if !needResize {
+		return nil
+	}
+	if _, err := r.Resize(device, targetPath); err != nil {
+		return err
+	}
+	nodeLogger.Info("filesystem is grown to the size of the volume",
+		"device", device,
+		"target_path", targetPath)
+	return nil
+}
+
+func growF2fsIfNeeded(device string) error {
+	needResize, err := filesystem.F2fsNeedResize(device)
+	if err != nil {
+		return err
+	}
+	if !needResize {
+		return nil
+	}
+	if err := filesystem.ResizeF2fs(device); err != nil {
+		return err
+	}
+	nodeLogger.Info("filesystem is grown to the size of the volume",
+		"device", device)
 	return nil
 }
 
@@ -325,25 +766,104 @@ func (s *nodeServerNoLocked) NodeUnpublishVolume(ctx context.Context, req *csi.N
 
 	device := filepath.Join(DeviceDirectory, volumeID)
 
+	// The device file for mount-type PV is removed by NodeUnstageVolume.
 	info, err := os.Stat(targetPath)
-	if os.IsNotExist(err) {
-		// target_path does not exist, but device for mount-type PV may still exist.
-		_ = os.Remove(device)
-		return &csi.NodeUnpublishVolumeResponse{}, nil
-	} else if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return nil, status.Errorf(codes.Internal, "stat failed for %s: %v", targetPath, err)
 	}
+	if err == nil {
+		// remove device file if target_path is device, unmount target_path otherwise
+		if info.IsDir() {
+			err = s.nodeUnpublishFilesystemVolume(req, device)
+		} else {
+			err = s.nodeUnpublishBlockVolume(req)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// remove device file if target_path is device, unmount target_path otherwise
-	if info.IsDir() {
-		err = s.nodeUnpublishFilesystemVolume(req, device)
-	} else {
-		err = s.nodeUnpublishBlockVolume(req)
+	// The target is unpublished regardless of the API server, so the cleanup of
+	// LogicalVolume is best effort.  A target left in the status is ignored
+	// because its path no longer exists.
+	if err := s.cleanupPublishedTarget(ctx, volumeID, targetPath); err != nil {
+		nodeLogger.Error(err, "failed to clean up LogicalVolume after unpublishing",
+			"volume_id", volumeID,
+			"target_path", targetPath)
+	}
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// cleanupPublishedTarget removes the I/O limits and the target from LogicalVolume.
+func (s *nodeServerNoLocked) cleanupPublishedTarget(ctx context.Context, volumeID, targetPath string) error {
+	lvr, err := s.k8sLVService.GetVolume(ctx, volumeID)
+	if err == k8s.ErrVolumeNotFound {
+		return nil
 	}
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to get LogicalVolume: %w", err)
 	}
-	return &csi.NodeUnpublishVolumeResponse{}, nil
+
+	// The status is shared with the other target paths on this node.
+	lastTarget := true
+	for _, t := range livePublishedTargets(lvr.Status.PublishedTargets) {
+		if t.Path != targetPath {
+			lastTarget = false
+		}
+	}
+
+	if err := s.removeIOLimits(ctx, volumeID, lvr, targetPath, lastTarget); err != nil {
+		return err
+	}
+	err = s.k8sLVService.RemovePublishedTarget(ctx, volumeID, targetPath)
+	if err != nil && err != k8s.ErrVolumeNotFound {
+		return fmt.Errorf("failed to update LogicalVolume status: %w", err)
+	}
+	return nil
+}
+
+// livePublishedTargets returns the published targets whose paths still exist.
+// A target may be left in the status if NodeUnpublishVolume could not update it.
+func livePublishedTargets(targets []v1.PublishedTarget) []v1.PublishedTarget {
+	var ret []v1.PublishedTarget
+	for _, t := range targets {
+		if _, err := os.Lstat(t.Path); err == nil {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// removeIOLimits removes the I/O limits applied by applyIOLimits.
+// The pod cgroup is usually removed before unpublishing, so this does nothing if it is not found.
+// The limits in the status are kept until the last target is unpublished.
+func (s *nodeServerNoLocked) removeIOLimits(ctx context.Context, volumeID string, lvr *v1.LogicalVolume, targetPath string, lastTarget bool) error {
+	if lvr.Status.IOLimits == nil && lvr.Status.IOLimitsMessage == "" {
+		return nil
+	}
+
+	lv, err := s.getLvFromContext(ctx, lvr.Spec.DeviceClass, volumeID)
+	if err != nil {
+		return err
+	}
+	podUID, err := podUIDFromTargetPath(targetPath)
+	if lv != nil && err == nil {
+		dir, err := cgroup.FindPodCgroup(podUID)
+		if err == nil {
+			err = cgroup.SetIOMax(dir, cgroup.IOMax{Major: lv.DevMajor, Minor: lv.DevMinor})
+		}
+		if err != nil && err != cgroup.ErrPodCgroupNotFound {
+			nodeLogger.Error(err, "failed to remove I/O limits", "volume_id", volumeID, "pod_uid", podUID)
+		}
+	}
+
+	if !lastTarget {
+		return nil
+	}
+	if err := s.k8sLVService.UpdateIOLimits(ctx, volumeID, nil, ""); err != nil {
+		return fmt.Errorf("failed to update I/O limits in LogicalVolume status: %w", err)
+	}
+	return nil
 }
 
 func (s *nodeServerNoLocked) nodeUnpublishFilesystemVolume(req *csi.NodeUnpublishVolumeRequest, device string) error {
@@ -363,11 +883,6 @@ func (s *nodeServerNoLocked) nodeUnpublishFilesystemVolume(req *csi.NodeUnpublis
 		return status.Errorf(codes.Internal, "remove dir failed for %s: error=%v", targetPath, err)
 	}
 
-	err = os.Remove(device)
-	if err != nil && !os.IsNotExist(err) {
-		return status.Errorf(codes.Internal, "remove device failed for %s: error=%v", device, err)
-	}
-
 	nodeLogger.Info("NodeUnpublishVolume(fs) is succeeded",
 		"volume_id", req.GetVolumeId(),
 		"target_path", targetPath)
@@ -415,7 +930,8 @@ func (s *nodeServerNoLocked) NodeGetVolumeStats(ctx context.Context, req *csi.No
 			return nil, status.Errorf(codes.Internal, "seek on %s was failed: %v", volumePath, err)
 		}
 		return &csi.NodeGetVolumeStatsResponse{
-			Usage: []*csi.VolumeUsage{{Total: pos, Unit: csi.VolumeUsage_BYTES}},
+			Usage:           []*csi.VolumeUsage{{Total: pos, Unit: csi.VolumeUsage_BYTES}},
+			VolumeCondition: s.getVolumeCondition(ctx, volumeID, volumePath, true),
 		}, nil
 	}
 
@@ -445,7 +961,24 @@ func (s *nodeServerNoLocked) NodeGetVolumeStats(ctx context.Context, req *csi.No
 			Available: int64(sfs.Ffree),
 		})
 	}
-	return &csi.NodeGetVolumeStatsResponse{Usage: usage}, nil
+	return &csi.NodeGetVolumeStatsResponse{
+		Usage:           usage,
+		VolumeCondition: s.getVolumeCondition(ctx, volumeID, volumePath, false),
+	}, nil
+}
+
+// getVolumeCondition returns nil if the condition cannot be checked
+// so that the usage is reported even if lvmd is unavailable.
+func (s *nodeServerNoLocked) getVolumeCondition(ctx context.Context, volumeID, volumePath string, isBlock bool) *csi.VolumeCondition {
+	condition, err := s.volumeCondition(ctx, volumeID, volumePath, isBlock)
+	if err != nil {
+		nodeLogger.Error(err, "failed to check volume condition", "volume_id", volumeID, "volume_path", volumePath)
+		return nil
+	}
+	if condition.GetAbnormal() {
+		nodeLogger.Info("volume is abnormal", "volume_id", volumeID, "message", condition.GetMessage())
+	}
+	return condition
 }
 
 func (s *nodeServerNoLocked) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
@@ -523,9 +1056,25 @@ func (s *nodeServerNoLocked) NodeExpandVolume(ctx context.Context, req *csi.Node
 		return nil, status.Errorf(codes.Internal, "filesystem %s is not mounted at %s", volumeID, volumePath)
 	}
 
-	r := mountutil.NewResizeFs(s.mounter.Exec)
-	if _, err := r.Resize(device, volumePath); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to resize filesystem %s (mounted at: %s): %v", volumeID, volumePath, err)
+	fsType, err := filesystem.DetectFilesystem(device)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "filesystem check failed: volume=%s, error=%v", volumeID, err)
+	}
+
+	if fsType == "f2fs" {
+		// f2fs is grown by NodeStageVolume because it cannot be resized online.
+		needResize, err := filesystem.F2fsNeedResize(device)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check filesystem size %s: %v", volumeID, err)
+		}
+		if needResize {
+			return nil, status.Errorf(codes.FailedPrecondition, "f2fs %s cannot be resized while it is mounted at %s; it is resized when the volume is staged again", volumeID, volumePath)
+		}
+	} else {
+		r := mountutil.NewResizeFs(s.mounter.Exec)
+		if _, err := r.Resize(device, volumePath); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to resize filesystem %s (mounted at: %s): %v", volumeID, volumePath, err)
+		}
 	}
 
 	nodeLogger.Info("NodeExpandVolume(fs) is succeeded",
@@ -542,8 +1091,11 @@ func (s *nodeServerNoLocked) NodeExpandVolume(ctx context.Context, req *csi.Node
 
 func (s *nodeServerNoLocked) NodeGetCapabilities(context.Context, *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
 	capabilities := []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
 		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
 	}
 
 	csiCaps := make([]*csi.NodeServiceCapability, len(capabilities))
@@ -0,0 +1,25 @@
+package driver
+
+import (
+	"fmt"
+
+	"github.com/topolvm/topolvm"
+)
+
+var (
+	fsFreezePolicies = []string{topolvm.FsFreezeRequired, topolvm.FsFreezeBestEffort, topolvm.FsFreezeDisabled}
+	fsckPolicies     = []string{topolvm.FsckNever, topolvm.FsckPreen, topolvm.FsckFullIfDirty}
+)
+
+// policyFromParameters returns the policy given by the key in StorageClass or VolumeSnapshotClass parameters.
+// It returns an empty string if the policy is not specified, and an error if it is not one of policies.
+func policyFromParameters(params map[string]string, key string, policies []string) (string, error) {
+	policy, ok := params[key]
+	if !ok {
+		return "", nil
+	}
+	if !containsString(policies, policy) {
+		return "", fmt.Errorf("invalid %s: %s", key, policy)
+	}
+	return policy, nil
+}
@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/topolvm/topolvm"
+)
+
+func TestPolicyFromParameters(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   map[string]string
+		key      string
+		policies []string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "not specified",
+			params:   map[string]string{topolvm.GetFsckKey(): topolvm.FsckNever},
+			key:      topolvm.GetFsFreezeKey(),
+			policies: fsFreezePolicies,
+			expected: "",
+		},
+		{
+			name:     "fsfreeze",
+			params:   map[string]string{topolvm.GetFsFreezeKey(): "required"},
+			key:      topolvm.GetFsFreezeKey(),
+			policies: fsFreezePolicies,
+			expected: topolvm.FsFreezeRequired,
+		},
+		{
+			name:     "fsck",
+			params:   map[string]string{topolvm.GetFsckKey(): "full-if-dirty"},
+			key:      topolvm.GetFsckKey(),
+			policies: fsckPolicies,
+			expected: topolvm.FsckFullIfDirty,
+		},
+		{
+			name:     "policy of another key",
+			params:   map[string]string{topolvm.GetFsckKey(): "required"},
+			key:      topolvm.GetFsckKey(),
+			policies: fsckPolicies,
+			isErr:    true,
+		},
+		{
+			name:     "empty",
+			params:   map[string]string{topolvm.GetFsFreezeKey(): ""},
+			key:      topolvm.GetFsFreezeKey(),
+			policies: fsFreezePolicies,
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		policy, err := policyFromParameters(tc.params, tc.key, tc.policies)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("%s: error is expected", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if policy != tc.expected {
+			t.Errorf("%s: expected=%q actual=%q", tc.name, tc.expected, policy)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package driver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/topolvm/topolvm/quota"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys of the parameters given by external-provisioner with --extra-create-metadata.
+const (
+	pvcNameKey      = "csi.storage.k8s.io/pvc/name"
+	pvcNamespaceKey = "csi.storage.k8s.io/pvc/namespace"
+)
+
+// checkQuota checks TopoLVMQuotas for the PVC requesting requestBytes of the device-class.
+// This does nothing if the PVC is unknown.
+func checkQuota(ctx context.Context, r client.Reader, namespace, pvcName, deviceClass string, requestBytes int64) error {
+	if namespace == "" || pvcName == "" {
+		return nil
+	}
+
+	err := quota.Check(ctx, r, namespace, pvcName, []string{deviceClass}, requestBytes)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, quota.ErrExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "failed to check TopoLVMQuota: %v", err)
+	}
+}
+
+// boundPVC returns the namespace and the name of the PVC bound to the PersistentVolume.
+// Empty strings are returned if the PersistentVolume is not found or not bound.
+func boundPVC(ctx context.Context, r client.Reader, pvName string) (string, string, error) {
+	var pv corev1.PersistentVolume
+	err := r.Get(ctx, client.ObjectKey{Name: pvName}, &pv)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", "", nil
+		}
+		return "", "", err
+	}
+	if pv.Spec.ClaimRef == nil {
+		return "", "", nil
+	}
+	return pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name, nil
+}
@@ -28,6 +28,8 @@ var podWithMountOptionPVCYAML []byte
 type cleanup struct {
 	// key is volumeID, value is target path
 	volumes map[string]string
+	// key is volumeID, value is staging target path
+	stagedVolumes map[string]string
 }
 
 func (c *cleanup) register(volumeID, targetPath string) {
@@ -45,6 +47,21 @@ func (c *cleanup) unregister(volumeID, targetPath string) {
 	}
 }
 
+func (c *cleanup) registerStaged(volumeID, stagingTargetPath string) {
+	By("[cleanup] registerStaged")
+	if c.stagedVolumes == nil {
+		c.stagedVolumes = make(map[string]string)
+	}
+	c.stagedVolumes[volumeID] = stagingTargetPath
+}
+
+func (c *cleanup) unregisterStaged(volumeID string) {
+	By("[cleanup] unregisterStaged")
+	if c.stagedVolumes != nil {
+		delete(c.stagedVolumes, volumeID)
+	}
+}
+
 func (c *cleanup) unpublishVolumes(nc csi.NodeClient) {
 	By("[cleanup] unpublishVolumes")
 	for volumeID, targetPath := range c.volumes {
@@ -58,6 +75,18 @@ func (c *cleanup) unpublishVolumes(nc csi.NodeClient) {
 		}
 	}
 	c.volumes = nil
+
+	for volumeID, stagingTargetPath := range c.stagedVolumes {
+		req := &csi.NodeUnstageVolumeRequest{
+			VolumeId:          volumeID,
+			StagingTargetPath: stagingTargetPath,
+		}
+		_, err := nc.NodeUnstageVolume(context.Background(), req)
+		if err != nil {
+			fmt.Printf("failed to unstage volume: %v", req)
+		}
+	}
+	c.stagedVolumes = nil
 }
 
 func testPublishVolume() {
@@ -100,6 +129,7 @@ func testPublishVolume() {
 
 	It("should publish filesystem", func() {
 		mountTargetPath := "/mnt/csi-node-test"
+		stagingTargetPath := "/mnt/csi-node-test-staging"
 
 		nodeName := "topolvm-e2e-worker"
 		if isDaemonsetLvmdEnvSet() {
@@ -141,6 +171,7 @@ func testPublishVolume() {
 			return nil
 		}).Should(Succeed())
 
+		cl.registerStaged(volumeID, stagingTargetPath)
 		cl.register(volumeID, mountTargetPath)
 
 		By("creating Filesystem volume")
@@ -154,11 +185,34 @@ func testPublishVolume() {
 		}
 
 		req := &csi.NodePublishVolumeRequest{
-			PublishContext:   map[string]string{},
-			TargetPath:       mountTargetPath,
-			VolumeCapability: mountVolCap,
-			VolumeId:         volumeID,
+			PublishContext:    map[string]string{},
+			StagingTargetPath: stagingTargetPath,
+			TargetPath:        mountTargetPath,
+			VolumeCapability:  mountVolCap,
+			VolumeId:          volumeID,
+		}
+
+		By("publishing Filesystem volume before staging")
+		_, err = nc.NodePublishVolume(context.Background(), req)
+		Expect(err).Should(HaveOccurred())
+
+		By("staging Filesystem volume")
+		stageReq := &csi.NodeStageVolumeRequest{
+			PublishContext:    map[string]string{},
+			StagingTargetPath: stagingTargetPath,
+			VolumeCapability:  mountVolCap,
+			VolumeId:          volumeID,
 		}
+		stageResp, err := nc.NodeStageVolume(context.Background(), stageReq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stageResp).ShouldNot(BeNil())
+
+		By("staging Filesystem volume again to check idempotency")
+		stageResp, err = nc.NodeStageVolume(context.Background(), stageReq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(stageResp).ShouldNot(BeNil())
+
+		By("publishing Filesystem volume")
 		resp, err := nc.NodePublishVolume(context.Background(), req)
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(resp).ShouldNot(BeNil())
@@ -170,8 +224,9 @@ func testPublishVolume() {
 
 		By("publishing volume on same target path, but requested volume and existing one are different")
 		_, err = nc.NodePublishVolume(context.Background(), &csi.NodePublishVolumeRequest{
-			PublishContext: map[string]string{},
-			TargetPath:     mountTargetPath,
+			PublishContext:    map[string]string{},
+			StagingTargetPath: stagingTargetPath,
+			TargetPath:        mountTargetPath,
 			VolumeCapability: &csi.VolumeCapability{
 				AccessType: &csi.VolumeCapability_Mount{
 					Mount: &csi.VolumeCapability_MountVolume{FsType: "ext4"},
@@ -200,6 +255,22 @@ func testPublishVolume() {
 
 		cl.unregister(volumeID, mountTargetPath)
 
+		By("unstaging the volume")
+		unstageReq := csi.NodeUnstageVolumeRequest{
+			VolumeId:          volumeID,
+			StagingTargetPath: stagingTargetPath,
+		}
+		unstageResp, err := nc.NodeUnstageVolume(context.Background(), &unstageReq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unstageResp).ShouldNot(BeNil())
+
+		By("unstaging the volume again to check idempotency")
+		unstageResp, err = nc.NodeUnstageVolume(context.Background(), &unstageReq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unstageResp).ShouldNot(BeNil())
+
+		cl.unregisterStaged(volumeID)
+
 		By("cleaning logicalvolume")
 		_, _, err = kubectl("delete", "logicalvolumes", "csi-node-test-fs")
 		Expect(err).ShouldNot(HaveOccurred())
@@ -402,11 +473,12 @@ func testPublishVolume() {
 		}
 
 		req := &csi.NodePublishVolumeRequest{
-			PublishContext:   map[string]string{},
-			TargetPath:       mountTargetPath,
-			VolumeCapability: mountVolCap,
-			VolumeId:         volumeID,
-			Readonly:         true,
+			PublishContext:    map[string]string{},
+			StagingTargetPath: "/mnt/csi-node-test-staging",
+			TargetPath:        mountTargetPath,
+			VolumeCapability:  mountVolCap,
+			VolumeId:          volumeID,
+			Readonly:          true,
 		}
 		_, err = nc.NodePublishVolume(context.Background(), req)
 		Expect(err).Should(HaveOccurred())
@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const resizeF2fsCmd = "/sbin/resize.f2fs"
+
+const (
+	f2fsSuperblockOffset = 1024
+	f2fsMagic            = 0xF2F52010
+)
+
+// f2fsSize returns the size of the f2fs filesystem on device and the size of its segment in bytes.
+// The values are read from the superblock defined in include/linux/f2fs_fs.h of Linux.
+func f2fsSize(device string) (uint64, uint64, error) {
+	f, err := os.Open(device)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	sb := make([]byte, 44)
+	if _, err := f.ReadAt(sb, f2fsSuperblockOffset); err != nil {
+		return 0, 0, fmt.Errorf("failed to read f2fs superblock of %s: %w", device, err)
+	}
+	if magic := binary.LittleEndian.Uint32(sb[0:4]); magic != f2fsMagic {
+		return 0, 0, fmt.Errorf("%s does not have f2fs: magic=%#x", device, magic)
+	}
+	logBlockSize := binary.LittleEndian.Uint32(sb[16:20])
+	logBlocksPerSeg := binary.LittleEndian.Uint32(sb[20:24])
+	blockCount := binary.LittleEndian.Uint64(sb[36:44])
+
+	blockSize := uint64(1) << logBlockSize
+	return blockCount * blockSize, blockSize << logBlocksPerSeg, nil
+}
+
+// deviceSize returns the size of device in bytes.
+func deviceSize(device string) (uint64, error) {
+	f, err := os.Open(device)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the size of %s: %w", device, err)
+	}
+	return uint64(size), nil
+}
+
+// F2fsNeedResize returns true if the f2fs filesystem on device is smaller than the device.
+// A difference smaller than a segment is tolerated because f2fs is laid out in segments.
+func F2fsNeedResize(device string) (bool, error) {
+	fsSize, segmentSize, err := f2fsSize(device)
+	if err != nil {
+		return false, err
+	}
+	devSize, err := deviceSize(device)
+	if err != nil {
+		return false, err
+	}
+	return devSize >= fsSize+segmentSize, nil
+}
+
+// ResizeF2fs grows the f2fs filesystem on device to the size of the device.
+// f2fs cannot be resized online, so the filesystem must not be mounted.
+func ResizeF2fs(device string) error {
+	out, err := exec.Command(resizeF2fsCmd, device).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("resize.f2fs failed for %s: %w: %s", device, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
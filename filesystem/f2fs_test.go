@@ -0,0 +1,113 @@
+package filesystem
+
+import (
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func writeF2fsSuperblock(t *testing.T, path string, blockCount uint64) {
+	sb := make([]byte, 44)
+	binary.LittleEndian.PutUint32(sb[0:4], f2fsMagic)
+	binary.LittleEndian.PutUint32(sb[16:20], 12) // 4KiB blocks
+	binary.LittleEndian.PutUint32(sb[20:24], 9)  // 2MiB segments
+	binary.LittleEndian.PutUint64(sb[36:44], blockCount)
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteAt(sb, f2fsSuperblockOffset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestF2fsNeedResizeSuperblock(t *testing.T) {
+	f, err := os.CreateTemp("", "test-f2fs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if err := f.Truncate(1 << 30); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if _, err := F2fsNeedResize(f.Name()); err == nil {
+		t.Error("error is expected for a device without f2fs")
+	}
+
+	testCases := []struct {
+		name       string
+		blockCount uint64
+		needResize bool
+	}{
+		{name: "whole device", blockCount: (1 << 30) / 4096, needResize: false},
+		{name: "less than a segment", blockCount: (1<<30)/4096 - 256, needResize: false},
+		{name: "half of device", blockCount: (1 << 29) / 4096, needResize: true},
+	}
+
+	for _, tc := range testCases {
+		writeF2fsSuperblock(t, f.Name(), tc.blockCount)
+		needResize, err := F2fsNeedResize(f.Name())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if needResize != tc.needResize {
+			t.Errorf("%s: expected=%t actual=%t", tc.name, tc.needResize, needResize)
+		}
+	}
+}
+
+func TestResizeF2fs(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("run as root")
+	}
+	if _, err := exec.LookPath("mkfs.f2fs"); err != nil {
+		t.Skip("mkfs.f2fs is not installed")
+	}
+
+	dev, err := createDevice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exec.Command("losetup", "-d", dev).Run()
+
+	if out, err := exec.Command("mkfs.f2fs", "-q", dev).CombinedOutput(); err != nil {
+		t.Fatal(err, string(out))
+	}
+
+	needResize, err := F2fsNeedResize(dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needResize {
+		t.Error("a new filesystem should not need resize")
+	}
+
+	// reflect the size of the backing file truncated to 2GiB
+	if err := exec.Command("losetup", "-c", dev).Run(); err != nil {
+		t.Fatal(err)
+	}
+	needResize, err = F2fsNeedResize(dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !needResize {
+		t.Fatal("the filesystem should need resize")
+	}
+
+	if err := ResizeF2fs(dev); err != nil {
+		t.Fatal(err)
+	}
+	needResize, err = F2fsNeedResize(dev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needResize {
+		t.Error("the filesystem should be resized")
+	}
+}
@@ -0,0 +1,104 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	fsckCmd     = "/sbin/fsck"
+	dumpe2fsCmd = "/sbin/dumpe2fs"
+)
+
+// Exit codes of fsck, which are ORed together.
+const (
+	fsckErrorsCorrected   = 1
+	fsckRebootRequired    = 2
+	fsckErrorsUncorrected = 4
+	fsckOperationalError  = 8
+)
+
+// ErrNeedsRepair is returned by Check if the filesystem has errors that cannot be repaired automatically.
+var ErrNeedsRepair = errors.New("filesystem has errors that need manual repair")
+
+// CheckResult is the result of Check.
+type CheckResult struct {
+	// Output is the combined output of fsck.
+	Output string
+	// Repaired is true if fsck has corrected errors in the filesystem.
+	Repaired bool
+}
+
+// Check checks the unmounted filesystem on device with fsck, and repairs it automatically if possible.
+// If full is true, the filesystem is checked even if it seems clean.
+// An error wrapping ErrNeedsRepair is returned with the result if the filesystem needs manual repair.
+func Check(device string, full bool) (*CheckResult, error) {
+	args := []string{"-a"}
+	if full {
+		args = append(args, "-f")
+	}
+	args = append(args, device)
+
+	out, err := exec.Command(fsckCmd, args...).CombinedOutput()
+	result := &CheckResult{Output: strings.TrimSpace(string(out))}
+	if err == nil {
+		return result, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("fsck failed for %s: %w", device, err)
+	}
+	code := exitErr.ExitCode()
+	switch {
+	case code >= fsckOperationalError:
+		return nil, fmt.Errorf("fsck failed for %s: exit code=%d: %s", device, code, result.Output)
+	case code&fsckErrorsUncorrected != 0:
+		return result, fmt.Errorf("%w: device=%s", ErrNeedsRepair, device)
+	}
+	result.Repaired = code&(fsckErrorsCorrected|fsckRebootRequired) != 0
+	return result, nil
+}
+
+// IsDirty returns true if the filesystem on device is marked as not cleanly unmounted or as having errors.
+// Only ext2, ext3 and ext4 have the mark, so false is returned for the other filesystems.
+func IsDirty(device, fsType string) (bool, error) {
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+	default:
+		return false, nil
+	}
+
+	out, err := exec.Command(dumpe2fsCmd, "-h", device).Output()
+	if err != nil {
+		return false, fmt.Errorf("dumpe2fs failed for %s: %w", device, err)
+	}
+	return isExtDirty(string(out)), nil
+}
+
+// isExtDirty parses the output of dumpe2fs -h.
+func isExtDirty(out string) bool {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Filesystem state":
+			if value != "clean" {
+				return true
+			}
+		case "Filesystem features":
+			// the journal has not been replayed since the filesystem was mounted.
+			for _, f := range strings.Fields(value) {
+				if f == "needs_recovery" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
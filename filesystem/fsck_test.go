@@ -0,0 +1,113 @@
+package filesystem
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestIsExtDirty(t *testing.T) {
+	testCases := []struct {
+		name  string
+		out   string
+		dirty bool
+	}{
+		{
+			name: "clean",
+			out: `Filesystem volume name:   <none>
+Filesystem features:      has_journal ext_attr resize_inode dir_index filetype extent 64bit flex_bg
+Filesystem state:         clean
+Errors behavior:          Continue
+`,
+		},
+		{
+			name: "needs recovery",
+			out: `Filesystem features:      has_journal ext_attr resize_inode dir_index filetype needs_recovery extent
+Filesystem state:         clean
+`,
+			dirty: true,
+		},
+		{
+			name: "not clean",
+			out: `Filesystem features:      has_journal ext_attr resize_inode dir_index filetype extent
+Filesystem state:         not clean
+`,
+			dirty: true,
+		},
+		{
+			name: "clean with errors",
+			out: `Filesystem features:      has_journal ext_attr resize_inode dir_index filetype extent
+Filesystem state:         clean with errors
+`,
+			dirty: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		if dirty := isExtDirty(tc.out); dirty != tc.dirty {
+			t.Errorf("%s: expected=%t actual=%t", tc.name, tc.dirty, dirty)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("run as root")
+	}
+
+	dev, err := createDevice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exec.Command("losetup", "-d", dev).Run()
+
+	if err := exec.Command("mkfs.ext4", "-q", dev).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	dirty, err := IsDirty(dev, "ext4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirty {
+		t.Error("a new filesystem should not be dirty")
+	}
+	result, err := Check(dev, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Repaired {
+		t.Error("a new filesystem should not be repaired", result.Output)
+	}
+
+	// mark the filesystem as having errors
+	if out, err := exec.Command("tune2fs", "-E", "force_fsck", dev).CombinedOutput(); err != nil {
+		t.Fatal(err, string(out))
+	}
+	dirty, err = IsDirty(dev, "ext4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dirty {
+		t.Fatal("the filesystem should be dirty")
+	}
+
+	if _, err := Check(dev, true); err != nil {
+		t.Fatal(err)
+	}
+	dirty, err = IsDirty(dev, "ext4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirty {
+		t.Error("the filesystem should be clean after the check")
+	}
+
+	dirty, err = IsDirty(dev, "xfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dirty {
+		t.Error("xfs should not be regarded as dirty")
+	}
+}
@@ -0,0 +1,128 @@
+package filesystem
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mkfsFlags are the flags of mkfs allowed to be specified by users for each filesystem type.
+// The value is true if the flag takes an argument.
+// The flags to force formatting, or to set labels and UUIDs are not allowed
+// because they are managed by TopoLVM or break the uniqueness of volumes.
+var mkfsFlags = map[string]map[string]bool{
+	"ext4": {
+		"-b": true, // block size
+		"-C": true, // cluster size
+		"-E": true, // extended options
+		"-g": true, // blocks per group
+		"-G": true, // groups per flex group
+		"-i": true, // bytes per inode
+		"-I": true, // inode size
+		"-j": false,
+		"-J": true, // journal options
+		"-m": true, // reserved blocks percentage
+		"-N": true, // number of inodes
+		"-O": true, // features
+		"-T": true, // usage type
+	},
+	"xfs": {
+		"-b": true, // block size options
+		"-d": true, // data section options
+		"-i": true, // inode options
+		"-l": true, // log section options
+		"-m": true, // metadata options
+		"-n": true, // naming options
+		"-r": true, // realtime section options
+		"-s": true, // sector size options
+	},
+	"btrfs": {
+		"-d": true, // data profile
+		"-K": false,
+		"-m": true, // metadata profile
+		"-M": false,
+		"-n": true, // node size
+		"-O": true, // features
+		"-R": true, // runtime features
+		"-s": true, // sector size
+	},
+	"f2fs": {
+		"-a": true, // heap-based allocation
+		"-C": true, // encoding
+		"-e": true, // cold file extensions
+		"-E": true, // hot file extensions
+		"-i": false,
+		"-o": true, // overprovision ratio
+		"-O": true, // features
+		"-s": true, // segments per section
+		"-t": true, // discard
+		"-w": true, // sector size
+		"-z": true, // sections per zone
+	},
+}
+
+// mountOptionsNotAllowed are the mount options managed by TopoLVM.
+var mountOptionsNotAllowed = map[string]bool{
+	"ro":      true,
+	"rw":      true,
+	"bind":    true,
+	"rbind":   true,
+	"remount": true,
+}
+
+// ParseMkfsOptions splits mkfs options separated by spaces, and validates them for the filesystem type.
+func ParseMkfsOptions(fsType, options string) ([]string, error) {
+	args := strings.Fields(options)
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	flags, ok := mkfsFlags[fsType]
+	if !ok {
+		return nil, fmt.Errorf("mkfs options are not supported for %s", fsType)
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' {
+			return nil, fmt.Errorf("unexpected mkfs argument for %s: %s", fsType, arg)
+		}
+		takesArg, ok := flags[arg[:2]]
+		if !ok {
+			return nil, fmt.Errorf("mkfs option is not allowed for %s: %s", fsType, arg[:2])
+		}
+		switch {
+		case !takesArg && len(arg) > 2:
+			return nil, fmt.Errorf("mkfs option %s for %s does not take a value: %s", arg[:2], fsType, arg)
+		case takesArg && len(arg) == 2:
+			// the value is given as the next argument like "-m 0".
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("mkfs option %s for %s requires a value", arg, fsType)
+			}
+			i++
+		}
+	}
+	return args, nil
+}
+
+// ParseMountOptions splits mount options separated by commas, and validates them.
+func ParseMountOptions(options string) ([]string, error) {
+	if strings.TrimSpace(options) == "" {
+		return nil, nil
+	}
+
+	var ret []string
+	for _, o := range strings.Split(options, ",") {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			return nil, fmt.Errorf("empty mount option: %q", options)
+		}
+		if strings.ContainsAny(o, " \t\n") {
+			return nil, fmt.Errorf("mount option should not contain spaces: %q", o)
+		}
+		if mountOptionsNotAllowed[o] {
+			return nil, fmt.Errorf("mount option is not allowed: %s", o)
+		}
+		ret = append(ret, o)
+	}
+	return ret, nil
+}
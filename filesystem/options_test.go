@@ -0,0 +1,85 @@
+package filesystem
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMkfsOptions(t *testing.T) {
+	testCases := []struct {
+		fsType   string
+		options  string
+		expected []string
+		hasErr   bool
+	}{
+		{fsType: "ext4", options: "", expected: nil},
+		{fsType: "ext4", options: "-E lazy_itable_init=0 -m 0", expected: []string{"-E", "lazy_itable_init=0", "-m", "0"}},
+		{fsType: "ext4", options: "-m0 -I 512 -b 4096", expected: []string{"-m0", "-I", "512", "-b", "4096"}},
+		{fsType: "ext4", options: "-j", expected: []string{"-j"}},
+		{fsType: "xfs", options: "-m reflink=1 -i size=512", expected: []string{"-m", "reflink=1", "-i", "size=512"}},
+		{fsType: "btrfs", options: "-d single -m dup -K", expected: []string{"-d", "single", "-m", "dup", "-K"}},
+		{fsType: "f2fs", options: "-O extra_attr,compression -i", expected: []string{"-O", "extra_attr,compression", "-i"}},
+		{fsType: "ext4", options: "-F", hasErr: true},
+		{fsType: "btrfs", options: "-f", hasErr: true},
+		{fsType: "btrfs", options: "-U 00000000-0000-0000-0000-000000000000", hasErr: true},
+		{fsType: "f2fs", options: "-l label", hasErr: true},
+		{fsType: "ext4", options: "-L label", hasErr: true},
+		{fsType: "ext4", options: "-m", hasErr: true},
+		{fsType: "ext4", options: "-jx", hasErr: true},
+		{fsType: "ext4", options: "/dev/sda", hasErr: true},
+		{fsType: "xfs", options: "-f", hasErr: true},
+		{fsType: "xfs", options: "-E lazy_itable_init=0", hasErr: true},
+		{fsType: "vfat", options: "-F 32", hasErr: true},
+	}
+
+	for _, tc := range testCases {
+		args, err := ParseMkfsOptions(tc.fsType, tc.options)
+		if tc.hasErr {
+			if err == nil {
+				t.Errorf("%s %q: error is expected", tc.fsType, tc.options)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tc.fsType, tc.options, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, tc.expected) {
+			t.Errorf("%s %q: expected=%v actual=%v", tc.fsType, tc.options, tc.expected, args)
+		}
+	}
+}
+
+func TestParseMountOptions(t *testing.T) {
+	testCases := []struct {
+		options  string
+		expected []string
+		hasErr   bool
+	}{
+		{options: "", expected: nil},
+		{options: "discard", expected: []string{"discard"}},
+		{options: "discard, noatime", expected: []string{"discard", "noatime"}},
+		{options: "data=ordered,noatime", expected: []string{"data=ordered", "noatime"}},
+		{options: "discard,,noatime", hasErr: true},
+		{options: "ro", hasErr: true},
+		{options: "noatime,bind", hasErr: true},
+		{options: "no atime", hasErr: true},
+	}
+
+	for _, tc := range testCases {
+		opts, err := ParseMountOptions(tc.options)
+		if tc.hasErr {
+			if err == nil {
+				t.Errorf("%q: error is expected", tc.options)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.options, err)
+			continue
+		}
+		if !reflect.DeepEqual(opts, tc.expected) {
+			t.Errorf("%q: expected=%v actual=%v", tc.options, tc.expected, opts)
+		}
+	}
+}
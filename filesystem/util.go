@@ -12,7 +12,8 @@ import (
 )
 
 const (
-	blkidCmd = "/sbin/blkid"
+	blkidCmd    = "/sbin/blkid"
+	fsfreezeCmd = "/sbin/fsfreeze"
 )
 
 type temporaryer interface {
@@ -119,6 +120,25 @@ func DetectFilesystem(device string) (string, error) {
 	return "", nil
 }
 
+// Freeze freezes the filesystem mounted on target.
+// Writes to the filesystem are blocked until Thaw is called.
+func Freeze(target string) error {
+	out, err := exec.Command(fsfreezeCmd, "--freeze", target).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fsfreeze --freeze failed for %s: %w: %s", target, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Thaw thaws the filesystem mounted on target frozen by Freeze.
+func Thaw(target string) error {
+	out, err := exec.Command(fsfreezeCmd, "--unfreeze", target).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("fsfreeze --unfreeze failed for %s: %w: %s", target, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
 // Stat wrapped a golang.org/x/sys/unix.Stat function to handle EINTR signal for Go 1.14+
 func Stat(path string, stat *unix.Stat_t) error {
 	for {
@@ -5,6 +5,9 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+
+	mountutil "k8s.io/mount-utils"
+	utilexec "k8s.io/utils/exec"
 )
 
 func createDevice() (string, error) {
@@ -67,3 +70,91 @@ func TestDetectFilesystem(t *testing.T) {
 		t.Error("fs is not ext4", fs)
 	}
 }
+
+func TestDetectFilesystemBtrfsF2fs(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("run as root")
+	}
+
+	for _, fsType := range []string{"btrfs", "f2fs"} {
+		if _, err := exec.LookPath("mkfs." + fsType); err != nil {
+			t.Logf("mkfs.%s is not installed", fsType)
+			continue
+		}
+
+		func() {
+			dev, err := createDevice()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer exec.Command("losetup", "-d", dev).Run()
+
+			if out, err := exec.Command("mkfs."+fsType, "-f", dev).CombinedOutput(); err != nil {
+				t.Fatal(err, string(out))
+			}
+
+			fs, err := DetectFilesystem(dev)
+			if err != nil {
+				t.Error(err)
+			}
+			if fs != fsType {
+				t.Errorf("fs is not %s: %s", fsType, fs)
+			}
+		}()
+	}
+}
+
+func TestResizeBtrfs(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("run as root")
+	}
+	if _, err := exec.LookPath("mkfs.btrfs"); err != nil {
+		t.Skip("mkfs.btrfs is not installed")
+	}
+
+	dev, err := createDevice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exec.Command("losetup", "-d", dev).Run()
+
+	if out, err := exec.Command("mkfs.btrfs", "-f", dev).CombinedOutput(); err != nil {
+		t.Fatal(err, string(out))
+	}
+
+	target, err := os.MkdirTemp("", "test-filesystem-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(target)
+
+	if out, err := exec.Command("mount", "-o", "compress=zstd", dev, target).CombinedOutput(); err != nil {
+		t.Skip("btrfs cannot be mounted: ", string(out))
+	}
+	defer exec.Command("umount", target).Run()
+
+	// reflect the size of the backing file truncated to 2GiB
+	if err := exec.Command("losetup", "-c", dev).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// btrfs is grown online by k8s.io/mount-utils in NodeExpandVolume.
+	r := mountutil.NewResizeFs(utilexec.New())
+	needResize, err := r.NeedResize(dev, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !needResize {
+		t.Fatal("the filesystem should need resize")
+	}
+	if _, err := r.Resize(dev, target); err != nil {
+		t.Fatal(err)
+	}
+	needResize, err = r.NeedResize(dev, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needResize {
+		t.Error("the filesystem should be resized")
+	}
+}
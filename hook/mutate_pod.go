@@ -3,8 +3,10 @@ package hook
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/topolvm/topolvm"
 	"github.com/topolvm/topolvm/getter"
@@ -12,6 +14,7 @@ import (
 	storagev1 "k8s.io/api/storage/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -64,7 +67,7 @@ func (m *podMutator) Handle(ctx context.Context, req admission.Request) admissio
 		pod.Namespace = req.Namespace
 	}
 
-	capacities, err := m.volumesCapacity(ctx, pod)
+	capacities, fallbacks, err := m.volumesCapacity(ctx, pod)
 	if err != nil {
 		pmLogger.Error(err, "volumesCapacity failed")
 		return admission.Errored(http.StatusInternalServerError, err)
@@ -91,6 +94,9 @@ func (m *podMutator) Handle(ctx context.Context, req admission.Request) admissio
 	for dc, capacity := range capacities {
 		pod.Annotations[topolvm.GetCapacityKeyPrefix()+dc] = strconv.FormatInt(capacity, 10)
 	}
+	for k, v := range fallbacks {
+		pod.Annotations[k] = v
+	}
 
 	marshaledPod, err := json.Marshal(pod)
 	if err != nil {
@@ -127,38 +133,78 @@ func (t *targetSC) Get(ctx context.Context, name string) (*storagev1.StorageClas
 	return &sc, nil
 }
 
-func (m *podMutator) volumesCapacity(ctx context.Context, pod *corev1.Pod) (map[string]int64, error) {
+// volumesCapacity returns the requested capacity of each device-class or fallback group,
+// and the annotations that describe the fallback groups.
+func (m *podMutator) volumesCapacity(ctx context.Context, pod *corev1.Pod) (map[string]int64, map[string]string, error) {
 	targetSC := targetSC{m.getter, map[string]*storagev1.StorageClass{}}
 	capacities := make(map[string]int64)
+	fallbacks := make(map[string]string)
 	for _, vol := range pod.Spec.Volumes {
+		var sc *storagev1.StorageClass
+		var requested int64
 		switch {
 		case vol.PersistentVolumeClaim != nil:
-			dc, requested, isAlreadyBound, err := m.pvcCapacity(ctx, pod, vol, targetSC)
+			var isAlreadyBound bool
+			var err error
+			sc, requested, isAlreadyBound, err = m.pvcCapacity(ctx, pod, vol, targetSC)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if isAlreadyBound {
 				// If there is a TopoLVM volume that has been bound, scheduling will not be performed because the node to be scheduled is already fixed.
-				return nil, nil
-			}
-			if len(dc) == 0 {
-				continue
+				return nil, nil, nil
 			}
-			capacities[dc] += requested
 		case vol.Ephemeral != nil && vol.Ephemeral.VolumeClaimTemplate != nil:
-			dc, requested, err := m.ephemeralCapacity(ctx, pod, vol, targetSC)
+			var err error
+			sc, requested, err = m.ephemeralCapacity(ctx, pod, vol, targetSC)
 			if err != nil {
-				return nil, err
-			}
-			if len(dc) == 0 {
-				continue
+				return nil, nil, err
 			}
-			capacities[dc] += requested
 		default:
 			continue
 		}
+		if sc == nil {
+			continue
+		}
+
+		dc, fallback, err := deviceClassOf(sc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(dc) == 0 {
+			continue
+		}
+		capacities[dc] += requested
+		for k, v := range fallback {
+			fallbacks[k] = v
+		}
+	}
+	return capacities, fallbacks, nil
+}
+
+// deviceClassOf returns the name of the device-class used in the capacity annotation for the StorageClass.
+// If the StorageClass lists several device-classes or selects them by labels, the name of
+// the fallback group is returned with the annotation that describes the group.
+func deviceClassOf(sc *storagev1.StorageClass) (string, map[string]string, error) {
+	if param, ok := sc.Parameters[topolvm.GetDeviceClassSelectorKey()]; ok {
+		selector, err := labels.Parse(param)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid %s parameter of StorageClass %s: %w", topolvm.GetDeviceClassSelectorKey(), sc.Name, err)
+		}
+		group := topolvm.GetSelectorGroupName(selector)
+		return group, map[string]string{topolvm.GetDeviceClassSelectorKeyPrefix() + group: selector.String()}, nil
+	}
+
+	param, ok := sc.Parameters[topolvm.GetDeviceClassKey()]
+	if !ok {
+		return topolvm.DefaultDeviceClassAnnotationName, nil, nil
 	}
-	return capacities, nil
+	dcs := topolvm.DeviceClasses(param)
+	if len(dcs) == 1 {
+		return dcs[0], nil, nil
+	}
+	group := topolvm.GetFallbackGroupName(dcs)
+	return group, map[string]string{topolvm.GetDeviceClassesKeyPrefix() + group: strings.Join(dcs, ",")}, nil
 }
 
 func (m *podMutator) pvcCapacity(
@@ -166,7 +212,7 @@ func (m *podMutator) pvcCapacity(
 	pod *corev1.Pod,
 	vol corev1.Volume,
 	targetSC targetSC,
-) (string, int64, bool, error) {
+) (*storagev1.StorageClass, int64, bool, error) {
 	pvcName := vol.PersistentVolumeClaim.ClaimName
 	name := types.NamespacedName{
 		Namespace: pod.Namespace,
@@ -181,31 +227,31 @@ func (m *podMutator) pvcCapacity(
 				"namespace", pod.Namespace,
 				"pvc", pvcName,
 			)
-			return "", 0, false, err
+			return nil, 0, false, err
 		}
 		// Pods should be created even if their PVCs do not exist yet.
 		// TopoLVM does not care about such pods after they are created, though.
-		return "", 0, false, nil
+		return nil, 0, false, nil
 	}
 
 	if pvc.Spec.StorageClassName == nil {
 		// empty class name may appear when DefaultStorageClass admission plugin
 		// is turned off, or there are no default StorageClass.
 		// https://kubernetes.io/docs/concepts/storage/persistent-volumes/#class-1
-		return "", 0, false, nil
+		return nil, 0, false, nil
 	}
 	sc, err := targetSC.Get(ctx, *pvc.Spec.StorageClassName)
 	if err != nil {
-		return "", 0, false, err
+		return nil, 0, false, err
 	}
 	if sc == nil {
-		return "", 0, false, nil
+		return nil, 0, false, nil
 	}
 
 	// If the Pod has a bound PVC of TopoLVM, the pod will be scheduled
 	// to the node of the existing PV.
 	if pvc.Status.Phase != corev1.ClaimPending {
-		return "", 0, true, nil
+		return nil, 0, true, nil
 	}
 
 	var requested int64 = topolvm.DefaultSize
@@ -214,11 +260,7 @@ func (m *podMutator) pvcCapacity(
 			requested = ((req.Value()-1)>>30 + 1) << 30
 		}
 	}
-	dc, ok := sc.Parameters[topolvm.GetDeviceClassKey()]
-	if !ok {
-		dc = topolvm.DefaultDeviceClassAnnotationName
-	}
-	return dc, requested, false, nil
+	return sc, requested, false, nil
 }
 
 func (m *podMutator) ephemeralCapacity(
@@ -226,20 +268,20 @@ func (m *podMutator) ephemeralCapacity(
 	pod *corev1.Pod,
 	vol corev1.Volume,
 	targetSC targetSC,
-) (string, int64, error) {
+) (*storagev1.StorageClass, int64, error) {
 	volumeClaimTemplate := vol.Ephemeral.VolumeClaimTemplate
 	if volumeClaimTemplate.Spec.StorageClassName == nil {
 		// empty class name may appear when DefaultStorageClass admission plugin
 		// is turned off, or there are no default StorageClass.
 		// https://kubernetes.io/docs/concepts/storage/persistent-volumes/#class-1
-		return "", 0, nil
+		return nil, 0, nil
 	}
 	sc, err := targetSC.Get(ctx, *volumeClaimTemplate.Spec.StorageClassName)
 	if err != nil {
-		return "", 0, err
+		return nil, 0, err
 	}
 	if sc == nil {
-		return "", 0, nil
+		return nil, 0, nil
 	}
 
 	var requested int64 = topolvm.DefaultSize
@@ -248,9 +290,5 @@ func (m *podMutator) ephemeralCapacity(
 			requested = ((req.Value()-1)>>30 + 1) << 30
 		}
 	}
-	dc, ok := sc.Parameters[topolvm.GetDeviceClassKey()]
-	if !ok {
-		dc = topolvm.DefaultDeviceClassAnnotationName
-	}
-	return dc, requested, nil
+	return sc, requested, nil
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/topolvm/topolvm"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -102,6 +103,28 @@ func setupMutatePodResources() {
 	err = k8sClient.Create(testCtx, pvc4)
 	Expect(err).ShouldNot(HaveOccurred())
 
+	fallbackPVC := &corev1.PersistentVolumeClaim{}
+	fallbackPVC.Namespace = mutatePodNamespace
+	fallbackPVC.Name = "fallback-pvc"
+	fallbackPVC.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	fallbackPVC.Spec.StorageClassName = strPtr(topolvmProvisionerFallbackStorageClassName)
+	fallbackPVC.Spec.Resources.Requests = corev1.ResourceList{
+		"storage": *resource.NewQuantity(5<<30, resource.DecimalSI),
+	}
+	err = k8sClient.Create(testCtx, fallbackPVC)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	selectorPVC := &corev1.PersistentVolumeClaim{}
+	selectorPVC.Namespace = mutatePodNamespace
+	selectorPVC.Name = "selector-pvc"
+	selectorPVC.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	selectorPVC.Spec.StorageClassName = strPtr(topolvmProvisionerSelectorStorageClassName)
+	selectorPVC.Spec.Resources.Requests = corev1.ResourceList{
+		"storage": *resource.NewQuantity(3<<30, resource.DecimalSI),
+	}
+	err = k8sClient.Create(testCtx, selectorPVC)
+	Expect(err).ShouldNot(HaveOccurred())
+
 	defaultPVC := &corev1.PersistentVolumeClaim{}
 	defaultPVC.Namespace = mutatePodNamespace
 	defaultPVC.Name = "default-pvc"
@@ -197,6 +220,46 @@ var _ = Describe("pod mutation webhook", func() {
 		Expect(capacity).Should(Equal(strconv.Itoa(1 << 30)))
 	})
 
+	It("should mutate pod w/ TopoLVM PVC of fallback device-classes", func() {
+		pod := testPod()
+		pod.Spec.Volumes = []corev1.Volume{
+			{
+				Name: "vol1",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: pvcSource("fallback-pvc"),
+				},
+			},
+		}
+		err := k8sClient.Create(testCtx, pod)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		pod = getPod()
+		group := topolvm.GetFallbackGroupName([]string{"nvme", "ssd"})
+		Expect(pod.Annotations).Should(HaveKeyWithValue(topolvm.GetCapacityKeyPrefix()+group, strconv.Itoa(5<<30)))
+		Expect(pod.Annotations).Should(HaveKeyWithValue(topolvm.GetDeviceClassesKeyPrefix()+group, "nvme,ssd"))
+	})
+
+	It("should mutate pod w/ TopoLVM PVC of device-classes selected by labels", func() {
+		pod := testPod()
+		pod.Spec.Volumes = []corev1.Volume{
+			{
+				Name: "vol1",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: pvcSource("selector-pvc"),
+				},
+			},
+		}
+		err := k8sClient.Create(testCtx, pod)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		pod = getPod()
+		selector, err := labels.Parse("media=ssd,redundancy=raid1")
+		Expect(err).ShouldNot(HaveOccurred())
+		group := topolvm.GetSelectorGroupName(selector)
+		Expect(pod.Annotations).Should(HaveKeyWithValue(topolvm.GetCapacityKeyPrefix()+group, strconv.Itoa(3<<30)))
+		Expect(pod.Annotations).Should(HaveKeyWithValue(topolvm.GetDeviceClassSelectorKeyPrefix()+group, "media=ssd,redundancy=raid1"))
+	})
+
 	It("should mutate pod w/ TopoLVM PVC on multiple volume groups", func() {
 		pod := testPod()
 		pod.Spec.Volumes = []corev1.Volume{
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
 
 	admissionv1 "k8s.io/api/admissionregistration/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -40,11 +41,14 @@ const (
 	topolvmProvisioner2StorageClassName         = "topolvm-provisioner2"
 	topolvmProvisioner3StorageClassName         = "topolvm-provisioner3"
 	topolvmProvisionerImmediateStorageClassName = "topolvm-provisioner-immediate"
+	topolvmProvisionerFallbackStorageClassName  = "topolvm-provisioner-fallback"
+	topolvmProvisionerSelectorStorageClassName  = "topolvm-provisioner-selector"
 	hostLocalStorageClassName                   = "host-local"
 	missingStorageClassName                     = "missing-storageclass"
 
-	podMutatingWebhookPath = "/pod/mutate"
-	pvcMutatingWebhookPath = "/pvc/mutate"
+	podMutatingWebhookPath   = "/pod/mutate"
+	pvcMutatingWebhookPath   = "/pvc/mutate"
+	pvcValidatingWebhookPath = "/pvc/validate"
 )
 
 func strPtr(s string) *string { return &s }
@@ -105,6 +109,32 @@ func setupCommonResources() {
 	err = k8sClient.Create(testCtx, sc)
 	Expect(err).ShouldNot(HaveOccurred())
 
+	sc = &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: topolvmProvisionerFallbackStorageClassName,
+		},
+		Provisioner:       "topolvm.io",
+		VolumeBindingMode: modePtr(storagev1.VolumeBindingWaitForFirstConsumer),
+		Parameters: map[string]string{
+			topolvm.GetDeviceClassKey(): "nvme,ssd",
+		},
+	}
+	err = k8sClient.Create(testCtx, sc)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	sc = &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: topolvmProvisionerSelectorStorageClassName,
+		},
+		Provisioner:       "topolvm.io",
+		VolumeBindingMode: modePtr(storagev1.VolumeBindingWaitForFirstConsumer),
+		Parameters: map[string]string{
+			topolvm.GetDeviceClassSelectorKey(): "media=ssd,redundancy=raid1",
+		},
+	}
+	err = k8sClient.Create(testCtx, sc)
+	Expect(err).ShouldNot(HaveOccurred())
+
 	sc = &storagev1.StorageClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: hostLocalStorageClassName,
@@ -188,6 +218,43 @@ var _ = BeforeSuite(func() {
 				},
 			},
 		},
+		ValidatingWebhooks: []*admissionv1.ValidatingWebhookConfiguration{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "topolvm-validating-hook",
+				},
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "ValidatingWebhookConfiguration",
+					APIVersion: "admissionregistration.k8s.io/v1",
+				},
+				Webhooks: []admissionv1.ValidatingWebhook{
+					{
+						Name:                    "pvc-validate-hook.topolvm.io",
+						AdmissionReviewVersions: []string{"v1", "v1beta1"},
+						FailurePolicy:           &failPolicy,
+						ClientConfig: admissionv1.WebhookClientConfig{
+							Service: &admissionv1.ServiceReference{
+								Path: strPtr(pvcValidatingWebhookPath),
+							},
+						},
+						Rules: []admissionv1.RuleWithOperations{
+							{
+								Operations: []admissionv1.OperationType{
+									admissionv1.Create,
+									admissionv1.Update,
+								},
+								Rule: admissionv1.Rule{
+									APIGroups:   []string{""},
+									APIVersions: []string{"v1"},
+									Resources:   []string{"persistentvolumeclaims"},
+								},
+							},
+						},
+						SideEffects: &sideEffects,
+					},
+				},
+			},
+		},
 	}
 
 	testEnv = &envtest.Environment{
@@ -203,6 +270,8 @@ var _ = BeforeSuite(func() {
 	scheme := runtime.NewScheme()
 	err = clientgoscheme.AddToScheme(scheme)
 	Expect(err).ToNot(HaveOccurred())
+	err = topolvmv1.AddToScheme(scheme)
+	Expect(err).ToNot(HaveOccurred())
 
 	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
 	Expect(err).ToNot(HaveOccurred())
@@ -227,6 +296,7 @@ var _ = BeforeSuite(func() {
 	setupCommonResources()
 	setupMutatePodResources()
 	setupMutatePVCResources()
+	setupValidatePVCResources()
 })
 
 var _ = AfterSuite(func() {
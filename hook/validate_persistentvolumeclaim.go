@@ -0,0 +1,102 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/topolvm/topolvm/getter"
+	"github.com/topolvm/topolvm/quota"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type persistentVolumeClaimValidator struct {
+	apiReader client.Reader
+	getter    *getter.RetryMissingGetter
+	decoder   *admission.Decoder
+}
+
+// PVCValidator creates a validating webhook for PVCs.
+func PVCValidator(r client.Reader, apiReader client.Reader, dec *admission.Decoder) http.Handler {
+	return &webhook.Admission{
+		Handler: &persistentVolumeClaimValidator{
+			apiReader: apiReader,
+			getter:    getter.NewRetryMissingGetter(r, apiReader),
+			decoder:   dec,
+		},
+	}
+}
+
+//+kubebuilder:webhook:failurePolicy=fail,matchPolicy=equivalent,groups=core,resources=persistentvolumeclaims,verbs=create;update,versions=v1,name=pvc-validate-hook.topolvm.io,path=/pvc/validate,mutating=false,sideEffects=none,admissionReviewVersions={v1,v1beta1}
+//+kubebuilder:rbac:groups=topolvm.io,resources=topolvmquotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups=topolvm.io,resources=logicalvolumes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+
+// Handle implements admission.Handler interface.
+func (v *persistentVolumeClaimValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := v.decoder.Decode(req, pvc)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return admission.Allowed("no request for TopoLVM")
+	}
+
+	requested := pvc.Spec.Resources.Requests.Storage().Value()
+	if req.Operation == admissionv1.Update {
+		oldPVC := &corev1.PersistentVolumeClaim{}
+		err := v.decoder.DecodeRaw(req.OldObject, oldPVC)
+		if err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Only expansions need to be checked.
+		// Other updates, e.g. removing finalizers, must not be denied even if quotas are exceeded.
+		if requested <= oldPVC.Spec.Resources.Requests.Storage().Value() {
+			return admission.Allowed("no expansion")
+		}
+	}
+
+	var sc storagev1.StorageClass
+	err = v.getter.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, &sc)
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		return admission.Allowed("no request for TopoLVM")
+	default:
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	deviceClasses, ok, err := quota.DeviceClasses(ctx, v.apiReader, &sc)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !ok {
+		return admission.Allowed("no request for TopoLVM")
+	}
+	// The device-class of an expanded PVC is decided when the volume was created.
+	deviceClasses, err = quota.ResolveDeviceClasses(ctx, v.apiReader, pvc.Spec.VolumeName, deviceClasses)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	// query directly to API server to count PVCs created just before
+	err = quota.Check(ctx, v.apiReader, req.Namespace, pvc.Name, deviceClasses, requested)
+	switch {
+	case err == nil:
+	case errors.Is(err, quota.ErrExceeded):
+		return admission.Denied(err.Error())
+	default:
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.Allowed("within TopoLVMQuota")
+}
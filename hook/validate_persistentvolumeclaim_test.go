@@ -0,0 +1,85 @@
+package hook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const validatePVCNamespace = "test-validate-pvc"
+
+func setupValidatePVCResources() {
+	ns := &corev1.Namespace{}
+	ns.Name = validatePVCNamespace
+	err := k8sClient.Create(testCtx, ns)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	storage := resource.MustParse("10Gi")
+	volumes := int64(2)
+	quota := &topolvmv1.TopoLVMQuota{}
+	quota.Namespace = validatePVCNamespace
+	quota.Name = "quota"
+	quota.Spec.Limits = []topolvmv1.DeviceClassQuota{
+		{
+			DeviceClass: "ssd",
+			Storage:     &storage,
+			Volumes:     &volumes,
+		},
+	}
+	err = k8sClient.Create(testCtx, quota)
+	Expect(err).ShouldNot(HaveOccurred())
+}
+
+func createPVCWithSize(sc, pvcName string, size int64) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	pvc.Namespace = validatePVCNamespace
+	pvc.Name = pvcName
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	pvc.Spec.StorageClassName = &sc
+	pvc.Spec.Resources.Requests = corev1.ResourceList{
+		"storage": *resource.NewQuantity(size, resource.BinarySI),
+	}
+	return k8sClient.Create(testCtx, pvc)
+}
+
+var _ = Describe("pvc validation webhook", func() {
+	It("should enforce TopoLVMQuota", func() {
+		By("creating a PVC within the quota")
+		err := createPVCWithSize(topolvmProvisionerStorageClassName, "ssd-1", 5<<30)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("denying a PVC exceeding the storage quota")
+		err = createPVCWithSize(topolvmProvisionerStorageClassName, "ssd-2", 6<<30)
+		Expect(err).Should(MatchError(ContainSubstring("TopoLVMQuota exceeded")))
+
+		By("creating a PVC of another device-class")
+		err = createPVCWithSize(topolvmProvisioner2StorageClassName, "hdd-1", 100<<30)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("creating a PVC of another StorageClass with the same device-class")
+		err = createPVCWithSize(topolvmProvisionerImmediateStorageClassName, "ssd-3", 5<<30)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("denying a PVC exceeding the volumes quota")
+		err = createPVCWithSize(topolvmProvisionerStorageClassName, "ssd-4", 1)
+		Expect(err).Should(MatchError(ContainSubstring("TopoLVMQuota exceeded")))
+
+		By("creating a PVC of StorageClass not for TopoLVM")
+		err = createPVCWithSize(hostLocalStorageClassName, "host-local", 100<<30)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		By("denying a PVC of a StorageClass falling back to the device-class")
+		sc := &storagev1.StorageClass{}
+		sc.Name = "topolvm-provisioner-fallback"
+		sc.Provisioner = topolvm.GetPluginName()
+		sc.Parameters = map[string]string{topolvm.GetDeviceClassKey(): "nvme,ssd"}
+		err = k8sClient.Create(testCtx, sc)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = createPVCWithSize(sc.Name, "fallback-1", 1)
+		Expect(err).Should(MatchError(ContainSubstring("TopoLVMQuota exceeded")))
+	})
+})
@@ -16,6 +16,7 @@ const (
 	nsenter  = "/usr/bin/nsenter"
 	lvm      = "/sbin/lvm"
 	blockdev = "/sbin/blockdev"
+	dmsetup  = "/sbin/dmsetup"
 	cowMin   = 50
 	cowMax   = 300
 )
@@ -102,6 +103,11 @@ func (g *VolumeGroup) Free() (uint64, error) {
 	return g.state.free, nil
 }
 
+// PVCount returns the number of physical volumes in the volume group.
+func (g *VolumeGroup) PVCount() uint64 {
+	return g.state.pvCount
+}
+
 // CreateVolumeGroup calls "vgcreate" to create a volume group.
 // name is for creating volume name. device is path to a PV.
 func CreateVolumeGroup(name, device string) (*VolumeGroup, error) {
@@ -193,7 +199,7 @@ func (g *VolumeGroup) ListVolumes() []*LogicalVolume {
 				size = lv.originSize
 			}
 
-			ret = append(ret, newLogicalVolume(
+			volume := newLogicalVolume(
 				lv.name,
 				lv.path,
 				g,
@@ -203,7 +209,12 @@ func (g *VolumeGroup) ListVolumes() []*LogicalVolume {
 				uint32(lv.major),
 				uint32(lv.minor),
 				lv.tags,
-			))
+			)
+			volume.uuid = lv.uuid
+			volume.attr = LVAttr(lv.attr)
+			volume.dataPercent = lv.dataPercent
+			volume.createdAt = lv.createdAt
+			ret = append(ret, volume)
 		}
 	}
 	return ret
@@ -318,6 +329,11 @@ func (t *ThinPool) Size() uint64 {
 	return t.state.size
 }
 
+// Attr returns lv_attr of the thin pool.
+func (t *ThinPool) Attr() LVAttr {
+	return LVAttr(t.state.attr)
+}
+
 // Resize the thin pool capacity.
 func (t *ThinPool) Resize(newSize uint64) error {
 	if t.state.size == newSize {
@@ -405,21 +421,25 @@ type LogicalVolume struct {
 	devMajor uint32
 	devMinor uint32
 	tags     []string
+
+	uuid        string
+	attr        LVAttr
+	dataPercent float64
+	createdAt   time.Time
 }
 
 func newLogicalVolume(name, path string, vg *VolumeGroup, size uint64, origin, pool *string, major, minor uint32, tags []string) *LogicalVolume {
-	fullname := fullName(name, vg)
 	return &LogicalVolume{
-		fullname,
-		name,
-		path,
-		vg,
-		size,
-		origin,
-		pool,
-		major,
-		minor,
-		tags,
+		fullname: fullName(name, vg),
+		name:     name,
+		path:     path,
+		vg:       vg,
+		size:     size,
+		origin:   origin,
+		pool:     pool,
+		devMajor: major,
+		devMinor: minor,
+		tags:     tags,
 	}
 }
 
@@ -461,6 +481,14 @@ func (l *LogicalVolume) Origin() (*LogicalVolume, error) {
 	return l.vg.FindVolume(*l.origin)
 }
 
+// OriginName returns the name of the origin volume if this is a snapshot, or an empty string if not.
+func (l *LogicalVolume) OriginName() string {
+	if l.origin == nil {
+		return ""
+	}
+	return *l.origin
+}
+
 // IsThin checks if the volume is thin volume or not.
 func (l *LogicalVolume) IsThin() bool {
 	return l.pool != nil
@@ -474,6 +502,14 @@ func (l *LogicalVolume) Pool() (*ThinPool, error) {
 	return l.vg.FindPool(*l.pool)
 }
 
+// PoolName returns the name of the thin pool if this is a thin volume, or an empty string if not.
+func (l *LogicalVolume) PoolName() string {
+	if l.pool == nil {
+		return ""
+	}
+	return *l.pool
+}
+
 // MajorNumber returns the device major number.
 func (l *LogicalVolume) MajorNumber() uint32 {
 	return l.devMajor
@@ -489,6 +525,27 @@ func (l *LogicalVolume) Tags() []string {
 	return l.tags
 }
 
+// UUID returns the UUID of the volume.
+func (l *LogicalVolume) UUID() string {
+	return l.uuid
+}
+
+// Attr returns lv_attr of the volume.
+func (l *LogicalVolume) Attr() LVAttr {
+	return l.attr
+}
+
+// DataPercent returns the percentage of the data space used.
+// This is only meaningful for thin volumes and snapshots.
+func (l *LogicalVolume) DataPercent() float64 {
+	return l.dataPercent
+}
+
+// CreatedAt returns the creation time of the volume.
+func (l *LogicalVolume) CreatedAt() time.Time {
+	return l.createdAt
+}
+
 // Snapshot takes a snapshot of this volume.
 //
 // If this is a thin-provisioning volume, snapshots can be
@@ -567,6 +624,29 @@ func (l *LogicalVolume) Activate(access string) error {
 	return err
 }
 
+// Suspend suspends the device of this volume.
+// I/O to the device is queued and the filesystem on the device is frozen until Resume is called.
+// Creating a thin snapshot of the volume resumes it.
+func (l *LogicalVolume) Suspend() error {
+	return l.callDMSetup("suspend")
+}
+
+// Resume resumes the device of this volume.
+// It does nothing if the device is not suspended.
+func (l *LogicalVolume) Resume() error {
+	return l.callDMSetup("resume")
+}
+
+func (l *LogicalVolume) callDMSetup(cmd string) error {
+	args := []string{cmd, "-j", fmt.Sprint(l.devMajor), "-m", fmt.Sprint(l.devMinor)}
+	c := wrapExecCommand(dmsetup, args...)
+	c.Stderr = os.Stderr
+	log.Info("invoking dmsetup command", map[string]interface{}{
+		"args": args,
+	})
+	return c.Run()
+}
+
 // Resize this volume.
 // newSize is a new size of this volume in bytes.
 func (l *LogicalVolume) Resize(newSize uint64) error {
@@ -0,0 +1,112 @@
+package command
+
+// LVHealth represents the health of a logical volume decoded from lv_attr.
+type LVHealth string
+
+// Health of logical volumes.  See lvs(8) for details.
+const (
+	LVHealthOK               = LVHealth("ok")
+	LVHealthPartial          = LVHealth("partial")
+	LVHealthRefreshNeeded    = LVHealth("refresh-needed")
+	LVHealthMismatches       = LVHealth("mismatches")
+	LVHealthWriteMostly      = LVHealth("writemostly")
+	LVHealthReshaping        = LVHealth("reshaping")
+	LVHealthReshapeRemoved   = LVHealth("reshape-removed")
+	LVHealthFailed           = LVHealth("failed")
+	LVHealthOutOfDataSpace   = LVHealth("out-of-data-space")
+	LVHealthMetadataReadOnly = LVHealth("metadata-read-only")
+	LVHealthUnknown          = LVHealth("unknown")
+)
+
+// LVAttr is lv_attr of a logical volume reported by lvm.
+//
+// The bits are:
+//  1. volume type
+//  2. permissions
+//  3. allocation policy
+//  4. fixed minor
+//  5. state
+//  6. device open
+//  7. target type
+//  8. newly-allocated data blocks are overwritten with zeros before use
+//  9. volume health
+//  10. skip activation
+type LVAttr string
+
+func (a LVAttr) bit(i int) byte {
+	if len(a) <= i {
+		return '-'
+	}
+	return a[i]
+}
+
+// IsActive returns true if the volume is active.
+func (a LVAttr) IsActive() bool {
+	return a.bit(4) == 'a'
+}
+
+// IsSuspended returns true if the device of the volume is suspended.
+func (a LVAttr) IsSuspended() bool {
+	switch a.bit(4) {
+	case 's', 'S', 'M', 'C':
+		return true
+	}
+	return false
+}
+
+// IsOpen returns true if the device of the volume is open.
+func (a LVAttr) IsOpen() bool {
+	return a.bit(5) == 'o'
+}
+
+// IsReadOnly returns true if the volume is read-only or activated as read-only.
+func (a LVAttr) IsReadOnly() bool {
+	p := a.bit(1)
+	return p == 'r' || p == 'R'
+}
+
+// IsSnapshot returns true if the volume is a thick snapshot.
+// Note that thin snapshots are thin volumes that have origins.
+func (a LVAttr) IsSnapshot() bool {
+	t := a.bit(0)
+	return t == 's' || t == 'S'
+}
+
+// IsThin returns true if the volume is a thin volume.
+func (a LVAttr) IsThin() bool {
+	return a.bit(0) == 'V'
+}
+
+// IsRAID returns true if the volume is a RAID volume.
+func (a LVAttr) IsRAID() bool {
+	t := a.bit(0)
+	return t == 'r' || t == 'R' || a.bit(6) == 'r'
+}
+
+// Health returns the health of the volume.
+func (a LVAttr) Health() LVHealth {
+	switch a.bit(8) {
+	case '-':
+		return LVHealthOK
+	case 'p':
+		return LVHealthPartial
+	case 'r':
+		return LVHealthRefreshNeeded
+	case 'm':
+		return LVHealthMismatches
+	case 'w':
+		return LVHealthWriteMostly
+	case 's':
+		return LVHealthReshaping
+	case 'R':
+		return LVHealthReshapeRemoved
+	case 'F':
+		return LVHealthFailed
+	case 'D':
+		return LVHealthOutOfDataSpace
+	case 'M':
+		return LVHealthMetadataReadOnly
+	default:
+		return LVHealthUnknown
+	}
+}
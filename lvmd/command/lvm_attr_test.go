@@ -0,0 +1,60 @@
+package command
+
+import "testing"
+
+func TestLVAttr(t *testing.T) {
+	cases := []struct {
+		attr      LVAttr
+		active    bool
+		suspended bool
+		open      bool
+		readOnly  bool
+		snapshot  bool
+		thin      bool
+		raid      bool
+		health    LVHealth
+	}{
+		{attr: "-wi-a-----", active: true, health: LVHealthOK},
+		{attr: "-wi-ao----", active: true, open: true, health: LVHealthOK},
+		{attr: "-wi-------", health: LVHealthOK},
+		{attr: "Vwi-aotz--", active: true, open: true, thin: true, health: LVHealthOK},
+		{attr: "Vri---tz-k", readOnly: true, thin: true, health: LVHealthOK},
+		{attr: "Vwi-sotz--", suspended: true, open: true, thin: true, health: LVHealthOK},
+		{attr: "twi-Cotz--", suspended: true, open: true, health: LVHealthOK},
+		{attr: "swi-a-s---", active: true, snapshot: true, health: LVHealthOK},
+		{attr: "rwi-a-r---", active: true, raid: true, health: LVHealthOK},
+		{attr: "rwi-aor-p-", active: true, open: true, raid: true, health: LVHealthPartial},
+		{attr: "rwi-a-r-r-", active: true, raid: true, health: LVHealthRefreshNeeded},
+		{attr: "Vwi-aotzF-", active: true, open: true, thin: true, health: LVHealthFailed},
+		{attr: "twi-aotzD-", active: true, open: true, health: LVHealthOutOfDataSpace},
+		{attr: "twi-aotzX-", active: true, open: true, health: LVHealthUnknown},
+		{attr: "", health: LVHealthOK},
+	}
+
+	for _, c := range cases {
+		if c.attr.IsActive() != c.active {
+			t.Errorf("%q: unexpected active: %v", c.attr, c.attr.IsActive())
+		}
+		if c.attr.IsSuspended() != c.suspended {
+			t.Errorf("%q: unexpected suspended: %v", c.attr, c.attr.IsSuspended())
+		}
+		if c.attr.IsOpen() != c.open {
+			t.Errorf("%q: unexpected open: %v", c.attr, c.attr.IsOpen())
+		}
+		if c.attr.IsReadOnly() != c.readOnly {
+			t.Errorf("%q: unexpected read-only: %v", c.attr, c.attr.IsReadOnly())
+		}
+		if c.attr.IsSnapshot() != c.snapshot {
+			t.Errorf("%q: unexpected snapshot: %v", c.attr, c.attr.IsSnapshot())
+		}
+		if c.attr.IsThin() != c.thin {
+			t.Errorf("%q: unexpected thin: %v", c.attr, c.attr.IsThin())
+		}
+		if c.attr.IsRAID() != c.raid {
+			t.Errorf("%q: unexpected raid: %v", c.attr, c.attr.IsRAID())
+		}
+		if c.attr.Health() != c.health {
+			t.Errorf("%q: unexpected health: %s", c.attr, c.attr.Health())
+		}
+	}
+}
@@ -4,13 +4,20 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/cybozu-go/log"
 )
 
+// lvTimeLayout is the layout of lv_time reported by lvm.
+const lvTimeLayout = "2006-01-02 15:04:05 -0700"
+
 type vg struct {
-	name string
-	uuid string
-	size uint64
-	free uint64
+	name    string
+	uuid    string
+	size    uint64
+	free    uint64
+	pvCount uint64
 }
 
 type lv struct {
@@ -29,6 +36,7 @@ type lv struct {
 	size            uint64
 	dataPercent     float64
 	metaDataPercent float64
+	createdAt       time.Time
 }
 
 func (u *lv) isThinPool() bool {
@@ -37,10 +45,11 @@ func (u *lv) isThinPool() bool {
 
 func (u *vg) UnmarshalJSON(data []byte) error {
 	type vgInternal struct {
-		Name string `json:"vg_name"`
-		UUID string `json:"vg_uuid"`
-		Size string `json:"vg_size"`
-		Free string `json:"vg_free"`
+		Name    string `json:"vg_name"`
+		UUID    string `json:"vg_uuid"`
+		Size    string `json:"vg_size"`
+		Free    string `json:"vg_free"`
+		PVCount string `json:"pv_count"`
 	}
 
 	var temp vgInternal
@@ -60,6 +69,12 @@ func (u *vg) UnmarshalJSON(data []byte) error {
 	if convErr != nil {
 		return convErr
 	}
+	if len(temp.PVCount) > 0 {
+		u.pvCount, convErr = strconv.ParseUint(temp.PVCount, 10, 64)
+		if convErr != nil {
+			return convErr
+		}
+	}
 
 	return nil
 }
@@ -81,6 +96,7 @@ func (u *lv) UnmarshalJSON(data []byte) error {
 		Size            string `json:"lv_size"`
 		DataPercent     string `json:"data_percent"`
 		MetaDataPercent string `json:"metadata_percent"`
+		Time            string `json:"lv_time"`
 	}
 
 	var temp lvInternal
@@ -132,6 +148,21 @@ func (u *lv) UnmarshalJSON(data []byte) error {
 			return convErr
 		}
 	}
+
+	// lv_time is formatted according to report/time_format of lvm.conf.
+	// If it is customized, the creation time is regarded as unknown.
+	if len(temp.Time) > 0 {
+		createdAt, err := time.Parse(lvTimeLayout, temp.Time)
+		if err != nil {
+			log.Warn("failed to parse lv_time", map[string]interface{}{
+				log.FnError: err,
+				"lv_name":   temp.Name,
+				"lv_time":   temp.Time,
+			})
+		} else {
+			u.createdAt = createdAt
+		}
+	}
 	return nil
 }
 
@@ -162,10 +193,10 @@ func getLVMState() ([]vg, []lv, error) {
 	args := []string{
 		"--reportformat", "json",
 		"--units", "b", "--nosuffix",
-		"--configreport", "vg", "-o", "vg_name,vg_uuid,vg_size,vg_free",
+		"--configreport", "vg", "-o", "vg_name,vg_uuid,vg_size,vg_free,pv_count",
 		"--configreport", "lv", "-o", "lv_uuid,lv_name,lv_full_name,lv_path,lv_size," +
 			"lv_kernel_major,lv_kernel_minor,origin,origin_size,pool_lv,lv_tags," +
-			"lv_attr,vg_name,data_percent,metadata_percent,pool_lv",
+			"lv_attr,vg_name,data_percent,metadata_percent,pool_lv,lv_time",
 		// fullreport doesn't have an option to omit an entire section, so we
 		// omit all fields instead.
 		"--configreport", "pv", "-o,",
@@ -3,6 +3,7 @@ package command
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/topolvm/topolvm/lvmd/testutils"
 )
@@ -17,7 +18,8 @@ func TestLvmJSON(t *testing.T) {
 				"vg_name": "myvg1",
 				"vg_uuid": "P8en82-LNUe-MERd-mOTT-XlAS-fkp8-1bleiB",
 				"vg_size": "2199014866944",
-				"vg_free": "2198482190336"
+				"vg_free": "2198482190336",
+				"pv_count": "2"
 			  }
 			],
 			"pv": [
@@ -40,7 +42,8 @@ func TestLvmJSON(t *testing.T) {
 				"lv_attr": "twi-a-tz--",
 				"vg_name": "myvg1",
 				"data_percent": "0.00",
-				"metadata_percent": "10.84"
+				"metadata_percent": "10.84",
+				"lv_time": "2022-06-01 12:34:56 +0900"
 			  }
 			],
 			"pvseg": [
@@ -79,6 +82,10 @@ func TestLvmJSON(t *testing.T) {
 		t.Fatal("Incorrect number of VGs returned: ", len(vgs))
 	}
 
+	if vgs[0].pvCount != 2 {
+		t.Fatal("Incorrect PV count: ", vgs[0].pvCount)
+	}
+
 	lv := lvs[0]
 
 	if lv.uuid != "n3eoy5-R1B3-9S6A-rBwo-3n9f-mIxA-Dy4nnw" {
@@ -145,6 +152,10 @@ func TestLvmJSON(t *testing.T) {
 		t.Fatal("Incorrect meta data percent:", lv.metaDataPercent)
 	}
 
+	if !lv.createdAt.Equal(time.Date(2022, 6, 1, 3, 34, 56, 0, time.UTC)) {
+		t.Fatal("Incorrect creation time:", lv.createdAt)
+	}
+
 	vg := vgs[0]
 	if vg.name != "myvg1" {
 		t.Fatal("Incorrect vg.name: ", vg.name)
@@ -246,6 +257,51 @@ func TestLvmInactiveMajorMinor(t *testing.T) {
 	}
 }
 
+func TestLvmCustomTimeFormat(t *testing.T) {
+	customTimeFormat := `
+	{
+	  "report": [
+		{
+		  "vg": [
+			{
+			  "vg_name": "myvg1",
+			  "vg_uuid": "P8en82-LNUe-MERd-mOTT-XlAS-fkp8-1bleiB",
+			  "vg_size": "2199014866944",
+			  "vg_free": "2198482190336"
+			}
+		  ],
+		  "lv": [
+			{
+			  "lv_uuid": "n3eoy5-R1B3-9S6A-rBwo-3n9f-mIxA-Dy4nnw",
+			  "lv_name": "thinpool",
+			  "lv_full_name": "myvg1/thinpool",
+			  "lv_size": "524288000",
+			  "lv_kernel_major": "253",
+			  "lv_kernel_minor": "2",
+			  "lv_attr": "twi-a-tz--",
+			  "vg_name": "myvg1",
+			  "lv_time": "Wed Jun  1 12:34:56 2022"
+			}
+		  ]
+		}
+	  ]
+	}
+  `
+	_, lvs, err := parseFullReportResult([]byte(customTimeFormat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 1 {
+		t.Fatal("Incorrect number of LVs returned: ", len(lvs))
+	}
+	if !lvs[0].createdAt.IsZero() {
+		t.Fatal("creation time should be unknown:", lvs[0].createdAt)
+	}
+	if lvs[0].size != 524288000 {
+		t.Fatal("Incorrect size: ", lvs[0].size)
+	}
+}
+
 func TestLvmJSONBad(t *testing.T) {
 	truncatedJSON := `
 	  {
@@ -4,8 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/filesystem"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // ErrNotFound is returned when a VG or LV is not found.
@@ -56,6 +60,12 @@ type DeviceClass struct {
 	Type DeviceType `json:"type"`
 	// ThinPoolConfig holds the configuration for thinpool in this volume group corresponding to the device-class
 	ThinPoolConfig *ThinPoolConfig `json:"thin-pool"`
+	// Labels are arbitrary labels of the device-class to select it by a label selector
+	Labels map[string]string `json:"labels"`
+	// MkfsOptions are default mkfs options separated by spaces for each filesystem type
+	MkfsOptions map[string]string `json:"mkfs-options"`
+	// MountOptions are default options to mount filesystems
+	MountOptions []string `json:"mount-options"`
 }
 
 // GetSpare returns spare in bytes for the device-class
@@ -130,6 +140,17 @@ func ValidateDeviceClasses(deviceClasses []*DeviceClass) error {
 		if dc.StripeSize != "" && !stripeSizeRegexp.MatchString(dc.StripeSize) {
 			return fmt.Errorf("stripe-size format is \"Size[k|UNIT]\": %s", dc.Name)
 		}
+		if err := validation.ValidateLabels(dc.Labels, field.NewPath("labels")); len(err) != 0 {
+			return fmt.Errorf("invalid labels of device-class %s: %w", dc.Name, err.ToAggregate())
+		}
+		for fsType, options := range dc.MkfsOptions {
+			if _, err := filesystem.ParseMkfsOptions(fsType, options); err != nil {
+				return fmt.Errorf("invalid mkfs-options of device-class %s: %w", dc.Name, err)
+			}
+		}
+		if _, err := filesystem.ParseMountOptions(strings.Join(dc.MountOptions, ",")); err != nil {
+			return fmt.Errorf("invalid mount-options of device-class %s: %w", dc.Name, err)
+		}
 	}
 	if countDefault != 1 {
 		return errors.New("should have only one default device-class")
@@ -370,6 +370,73 @@ func TestValidateDeviceClasses(t *testing.T) {
 			},
 			valid: false,
 		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "ssd",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					Labels:      map[string]string{"media": "ssd", "example.com/redundancy": "raid1"},
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "ssd",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					Labels:      map[string]string{"media": "solid state"},
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "ssd",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					Labels:      map[string]string{"-media": "ssd"},
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:         "ssd",
+					VolumeGroup:  "node1-myvg1",
+					Default:      true,
+					MkfsOptions:  map[string]string{"ext4": "-E lazy_itable_init=0 -m 0", "xfs": "-m reflink=1"},
+					MountOptions: []string{"discard", "noatime"},
+				},
+			},
+			valid: true,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:        "ssd",
+					VolumeGroup: "node1-myvg1",
+					Default:     true,
+					MkfsOptions: map[string]string{"xfs": "-f"},
+				},
+			},
+			valid: false,
+		},
+		{
+			deviceClasses: []*DeviceClass{
+				{
+					Name:         "ssd",
+					VolumeGroup:  "node1-myvg1",
+					Default:      true,
+					MountOptions: []string{"ro"},
+				},
+			},
+			valid: false,
+		},
 	}
 
 	for i, c := range cases {
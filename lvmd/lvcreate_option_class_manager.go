@@ -1,5 +1,10 @@
 package lvmd
 
+import (
+	"errors"
+	"fmt"
+)
+
 type LvcreateOptionClass struct {
 	// Name for the lvcreate-option-class name
 	Name string `json:"name"`
@@ -7,6 +12,26 @@ type LvcreateOptionClass struct {
 	Options []string `json:"options"`
 }
 
+// ValidateLvcreateOptionClasses validates lvcreate-option-classes
+func ValidateLvcreateOptionClasses(lvcreateOptionClasses []*LvcreateOptionClass) error {
+	names := make(map[string]bool)
+	for _, oc := range lvcreateOptionClasses {
+		if len(oc.Name) == 0 {
+			return errors.New("lvcreate-option-class name should not be empty")
+		} else if len(oc.Name) > 63 {
+			return fmt.Errorf("lvcreate-option-class name is too long: %s", oc.Name)
+		}
+		if !qualifiedNameRegexp.MatchString(oc.Name) {
+			return fmt.Errorf("lvcreate-option-class name should consist of alphanumeric characters, '-', '_' or '.', and should start and end with an alphanumeric character: %s", oc.Name)
+		}
+		if names[oc.Name] {
+			return fmt.Errorf("duplicate lvcreate-option-class name: %s", oc.Name)
+		}
+		names[oc.Name] = true
+	}
+	return nil
+}
+
 type LvcreateOptionClassManager struct {
 	LvcreateOptionClassByName map[string]*LvcreateOptionClass
 }
@@ -41,3 +41,64 @@ func TestLvcreateOptionClassManager(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateLvcreateOptionClasses(t *testing.T) {
+	cases := []struct {
+		lvcreateOptionClasses []*LvcreateOptionClass
+		valid                 bool
+	}{
+		{
+			lvcreateOptionClasses: nil,
+			valid:                 true,
+		},
+		{
+			lvcreateOptionClasses: []*LvcreateOptionClass{
+				{
+					Name:    "raid1",
+					Options: []string{"--type=raid1"},
+				},
+				{
+					Name:    "mirror",
+					Options: []string{"--mirrors=1"},
+				},
+			},
+			valid: true,
+		},
+		{
+			lvcreateOptionClasses: []*LvcreateOptionClass{
+				{
+					Name: "",
+				},
+			},
+			valid: false,
+		},
+		{
+			lvcreateOptionClasses: []*LvcreateOptionClass{
+				{
+					Name: "raid1!",
+				},
+			},
+			valid: false,
+		},
+		{
+			lvcreateOptionClasses: []*LvcreateOptionClass{
+				{
+					Name: "raid1",
+				},
+				{
+					Name: "raid1",
+				},
+			},
+			valid: false,
+		},
+	}
+
+	for i, c := range cases {
+		err := ValidateLvcreateOptionClasses(c.lvcreateOptionClasses)
+		if c.valid && err != nil {
+			t.Fatal(strconv.Itoa(i) + ": should be valid: " + err.Error())
+		} else if !c.valid && err == nil {
+			t.Fatal(strconv.Itoa(i) + ": should be invalid")
+		}
+	}
+}
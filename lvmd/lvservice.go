@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/cybozu-go/log"
 	"github.com/topolvm/topolvm/lvmd/command"
@@ -269,14 +270,197 @@ func (s *lvService) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnaps
 
 	return &proto.CreateLVSnapshotResponse{
 		Snapshot: &proto.LogicalVolume{
-			Name:     snapLV.Name(),
-			SizeGb:   snapLV.Size() >> 30,
-			DevMajor: snapLV.MajorNumber(),
-			DevMinor: snapLV.MinorNumber(),
+			Name:      snapLV.Name(),
+			SizeGb:    snapLV.Size() >> 30,
+			SizeBytes: snapLV.Size(),
+			DevMajor:  snapLV.MajorNumber(),
+			DevMinor:  snapLV.MinorNumber(),
 		},
 	}, nil
 }
 
+// snapshotSuspendTimeout is the maximum duration of the suspension of the source volumes in CreateLVSnapshots.
+const snapshotSuspendTimeout = 30 * time.Second
+
+// ResumeSuspendedVolumes resumes the volumes of the device-classes left suspended.
+// CreateLVSnapshots suspends the source volumes, so they are left suspended if lvmd is killed during it.
+func ResumeSuspendedVolumes(dcs []*DeviceClass) {
+	seen := make(map[string]bool)
+	for _, dc := range dcs {
+		if seen[dc.VolumeGroup] {
+			continue
+		}
+		seen[dc.VolumeGroup] = true
+
+		vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+		if err != nil {
+			log.Error("failed to find volume group", map[string]interface{}{
+				log.FnError:    err,
+				"volume_group": dc.VolumeGroup,
+			})
+			continue
+		}
+		for _, lv := range vg.ListVolumes() {
+			if !lv.Attr().IsSuspended() {
+				continue
+			}
+			log.Warn("resuming suspended volume", map[string]interface{}{
+				"name": lv.FullName(),
+			})
+			if err := lv.Resume(); err != nil {
+				log.Error("failed to resume volume", map[string]interface{}{
+					log.FnError: err,
+					"name":      lv.FullName(),
+				})
+			}
+		}
+	}
+}
+
+func (s *lvService) CreateLVSnapshots(_ context.Context, req *proto.CreateLVSnapshotsRequest) (*proto.CreateLVSnapshotsResponse, error) {
+	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s: %s", err.Error(), req.DeviceClass)
+	}
+	if dc.Type != TypeThin {
+		return nil, status.Error(codes.Unimplemented, "device class is not thin. Thick snapshots are not implemented yet")
+	}
+	if len(req.GetSnapshots()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no snapshots are requested")
+	}
+
+	vg, err := command.FindVolumeGroup(dc.VolumeGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]*command.LogicalVolume, len(req.GetSnapshots()))
+	for i, snap := range req.GetSnapshots() {
+		sourceLV, err := vg.FindVolume(snap.GetSourceVolume())
+		if err == command.ErrNotFound {
+			return nil, status.Errorf(codes.NotFound, "source logical volume %s is not found", snap.GetSourceVolume())
+		}
+		if err != nil {
+			log.Error("failed to find source volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      snap.GetSourceVolume(),
+			})
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if !sourceLV.IsThin() {
+			return nil, status.Error(codes.Unimplemented, "snapshot can be created for only thin volumes")
+		}
+		if snap.GetAccessType() != "ro" && snap.GetAccessType() != "rw" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid access type: %s", snap.GetAccessType())
+		}
+		sources[i] = sourceLV
+	}
+
+	// Suspend all the sources so that no writes reach them until their snapshots are created.
+	// Each source is resumed right after its snapshot is created, so that the snapshots
+	// are consistent with each other at the time of the suspension.
+	// lvcreate suspends and resumes the source by itself, but whether it resumes a source
+	// suspended by dmsetup depends on the version of LVM, so the source is resumed explicitly.
+	// The I/O of the applications is blocked during the suspension, so the sources are resumed
+	// after snapshotSuspendTimeout even if the snapshots are not created yet.
+	// The sources left suspended by a crash of lvmd are resumed by ResumeSuspendedVolumes.
+	resumeSources := func() {
+		for _, sourceLV := range sources {
+			if err := sourceLV.Resume(); err != nil {
+				log.Error("failed to resume source volume", map[string]interface{}{
+					log.FnError: err,
+					"name":      sourceLV.Name(),
+				})
+			}
+		}
+	}
+	defer resumeSources()
+	timer := time.AfterFunc(snapshotSuspendTimeout, func() {
+		log.Warn("resuming source volumes because snapshots are not created in time", map[string]interface{}{
+			"timeout": snapshotSuspendTimeout.String(),
+		})
+		resumeSources()
+	})
+	defer timer.Stop()
+	for _, sourceLV := range sources {
+		if err := sourceLV.Suspend(); err != nil {
+			log.Error("failed to suspend source volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      sourceLV.Name(),
+			})
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	var snapLVs []*command.LogicalVolume
+	removeSnapshots := func() {
+		for _, snapLV := range snapLVs {
+			if err := snapLV.Remove(); err != nil {
+				log.Error("failed to delete snapshot", map[string]interface{}{
+					log.FnError: err,
+					"name":      snapLV.Name(),
+				})
+			}
+		}
+	}
+	for i, snap := range req.GetSnapshots() {
+		snapLV, err := sources[i].Snapshot(snap.GetName(), sources[i].Size(), snap.GetTags())
+		if err != nil {
+			log.Error("failed to create snapshot volume", map[string]interface{}{
+				log.FnError: err,
+				"name":      snap.GetName(),
+			})
+			removeSnapshots()
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		snapLVs = append(snapLVs, snapLV)
+
+		if err := sources[i].Resume(); err != nil {
+			log.Error("failed to resume source volume, deleting snapshots", map[string]interface{}{
+				log.FnError: err,
+				"name":      sources[i].Name(),
+			})
+			removeSnapshots()
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	if !timer.Stop() {
+		// some of the snapshots may have been taken after the sources were resumed.
+		removeSnapshots()
+		return nil, status.Errorf(codes.DeadlineExceeded, "snapshots are not created within %s", snapshotSuspendTimeout)
+	}
+	for i, snap := range req.GetSnapshots() {
+		if err := snapLVs[i].Activate(snap.GetAccessType()); err != nil {
+			log.Error("failed to activate snap volume, deleting snapshots", map[string]interface{}{
+				log.FnError: err,
+				"name":      snap.GetName(),
+			})
+			removeSnapshots()
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	s.notify()
+
+	resp := &proto.CreateLVSnapshotsResponse{}
+	for i, snapLV := range snapLVs {
+		log.Info("created a new snapshot LV", map[string]interface{}{
+			"name":       snapLV.Name(),
+			"size":       snapLV.Size(),
+			"accessType": req.GetSnapshots()[i].GetAccessType(),
+			"sourceID":   sources[i].Name(),
+		})
+		resp.Snapshots = append(resp.Snapshots, &proto.LogicalVolume{
+			Name:      snapLV.Name(),
+			SizeGb:    snapLV.Size() >> 30,
+			SizeBytes: snapLV.Size(),
+			DevMajor:  snapLV.MajorNumber(),
+			DevMinor:  snapLV.MinorNumber(),
+		})
+	}
+	return resp, nil
+}
+
 func (s *lvService) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*proto.Empty, error) {
 	dc, err := s.dcmapper.DeviceClass(req.DeviceClass)
 	if err != nil {
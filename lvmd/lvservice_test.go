@@ -4,7 +4,9 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/topolvm/topolvm/lvmd/command"
 	"github.com/topolvm/topolvm/lvmd/proto"
@@ -401,4 +403,81 @@ func TestLVService(t *testing.T) {
 	if lv.Tags()[1] != "testrestoretag2" {
 		t.Errorf(`testsnaptag1 not present on snapshot`)
 	}
+
+	// create snapshots of multiple volumes at once.
+
+	_, err = lvService.CreateLV(context.Background(), &proto.CreateLVRequest{
+		Name:        "sourceVol2",
+		DeviceClass: thindev,
+		SizeGb:      1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 4 {
+		t.Errorf("is not notified: %d", count)
+	}
+
+	start := time.Now()
+	snapsRes, err := lvService.CreateLVSnapshots(context.Background(), &proto.CreateLVSnapshotsRequest{
+		DeviceClass: thindev,
+		Snapshots: []*proto.CreateLVSnapshotRequest{
+			{Name: "groupsnap1", SourceVolume: "sourceVol", AccessType: "ro"},
+			{Name: "groupsnap2", SourceVolume: "sourceVol2", AccessType: "ro"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the sources must be resumed by CreateLVSnapshots, not by the timeout.
+	if elapsed := time.Since(start); elapsed >= snapshotSuspendTimeout {
+		t.Errorf("sources are resumed by the timeout: %s", elapsed)
+	}
+	if count != 5 {
+		t.Errorf("is not notified: %d", count)
+	}
+	if len(snapsRes.GetSnapshots()) != 2 {
+		t.Fatalf("unexpected number of snapshots: %d", len(snapsRes.GetSnapshots()))
+	}
+	for i, name := range []string{"groupsnap1", "groupsnap2"} {
+		if snapsRes.GetSnapshots()[i].GetName() != name {
+			t.Errorf(`res.Snapshots[%d].Name != "%s": %s`, i, name, snapsRes.GetSnapshots()[i].GetName())
+		}
+		err = exec.Command("lvs", vg.Name()+"/"+name).Run()
+		if err != nil {
+			t.Error("failed to create logical volume")
+		}
+	}
+	for _, name := range []string{"sourceVol", "sourceVol2"} {
+		out, err := exec.Command("dmsetup", "info", "-c", "--noheadings", "-o", "suspended", vg.Name()+"-"+name).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSpace(string(out)) != "Active" {
+			t.Errorf("%s is not resumed: %s", name, out)
+		}
+	}
+	if err := vg.Update(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sourceVol", "sourceVol2"} {
+		lv, err := vg.FindVolume(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lv.Attr().IsSuspended() {
+			t.Errorf("%s is suspended: %s", name, lv.Attr())
+		}
+	}
+
+	_, err = lvService.CreateLVSnapshots(context.Background(), &proto.CreateLVSnapshotsRequest{
+		DeviceClass: thindev,
+		Snapshots: []*proto.CreateLVSnapshotRequest{
+			{Name: "groupsnap3", SourceVolume: "sourceVol", AccessType: "ro"},
+			{Name: "groupsnap4", SourceVolume: "notfound", AccessType: "ro"},
+		},
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
 }
@@ -16,6 +16,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -27,6 +28,70 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type WatchEvent_Type int32
+
+const (
+	WatchEvent_UNKNOWN                              WatchEvent_Type = 0
+	WatchEvent_LV_CREATED                           WatchEvent_Type = 1 // A logical volume is created.
+	WatchEvent_LV_REMOVED                           WatchEvent_Type = 2 // A logical volume is removed.
+	WatchEvent_LV_RESIZED                           WatchEvent_Type = 3 // A logical volume is resized.
+	WatchEvent_SNAPSHOT_CREATED                     WatchEvent_Type = 4 // A snapshot logical volume is created.
+	WatchEvent_THIN_POOL_DATA_THRESHOLD_CROSSED     WatchEvent_Type = 5 // Data percent of the thin pool crossed a threshold.
+	WatchEvent_THIN_POOL_METADATA_THRESHOLD_CROSSED WatchEvent_Type = 6 // Metadata percent of the thin pool crossed a threshold.
+	WatchEvent_VG_SIZE_CHANGED                      WatchEvent_Type = 7 // Size of the volume group is changed.
+)
+
+// Enum value maps for WatchEvent_Type.
+var (
+	WatchEvent_Type_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "LV_CREATED",
+		2: "LV_REMOVED",
+		3: "LV_RESIZED",
+		4: "SNAPSHOT_CREATED",
+		5: "THIN_POOL_DATA_THRESHOLD_CROSSED",
+		6: "THIN_POOL_METADATA_THRESHOLD_CROSSED",
+		7: "VG_SIZE_CHANGED",
+	}
+	WatchEvent_Type_value = map[string]int32{
+		"UNKNOWN":                              0,
+		"LV_CREATED":                           1,
+		"LV_REMOVED":                           2,
+		"LV_RESIZED":                           3,
+		"SNAPSHOT_CREATED":                     4,
+		"THIN_POOL_DATA_THRESHOLD_CROSSED":     5,
+		"THIN_POOL_METADATA_THRESHOLD_CROSSED": 6,
+		"VG_SIZE_CHANGED":                      7,
+	}
+)
+
+func (x WatchEvent_Type) Enum() *WatchEvent_Type {
+	p := new(WatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x WatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_lvmd_proto_lvmd_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_lvmd_proto_lvmd_proto_enumTypes[0]
+}
+
+func (x WatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEvent_Type.Descriptor instead.
+func (WatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{19, 0}
+}
+
 type Empty struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -71,11 +136,18 @@ type LogicalVolume struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name     string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                          // The logical volume name.
-	SizeGb   uint64   `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"`       // Volume size in GiB.
-	DevMajor uint32   `protobuf:"varint,3,opt,name=dev_major,json=devMajor,proto3" json:"dev_major,omitempty"` // Device major number.
-	DevMinor uint32   `protobuf:"varint,4,opt,name=dev_minor,json=devMinor,proto3" json:"dev_minor,omitempty"` // Device minor number.
-	Tags     []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                          // Tags to add to the volume during creation
+	Name         string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                      // The logical volume name.
+	SizeGb       uint64                 `protobuf:"varint,2,opt,name=size_gb,json=sizeGb,proto3" json:"size_gb,omitempty"`                   // Volume size in GiB.
+	DevMajor     uint32                 `protobuf:"varint,3,opt,name=dev_major,json=devMajor,proto3" json:"dev_major,omitempty"`             // Device major number.
+	DevMinor     uint32                 `protobuf:"varint,4,opt,name=dev_minor,json=devMinor,proto3" json:"dev_minor,omitempty"`             // Device minor number.
+	Tags         []string               `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`                                      // Tags to add to the volume during creation
+	Origin       string                 `protobuf:"bytes,6,opt,name=origin,proto3" json:"origin,omitempty"`                                  // The origin logical volume name if this is a snapshot.
+	Pool         string                 `protobuf:"bytes,7,opt,name=pool,proto3" json:"pool,omitempty"`                                      // The thin pool name if this is a thin volume.
+	Uuid         string                 `protobuf:"bytes,8,opt,name=uuid,proto3" json:"uuid,omitempty"`                                      // The UUID of the logical volume.
+	SizeBytes    uint64                 `protobuf:"varint,9,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`          // Volume size in bytes.
+	Attributes   *LVAttributes          `protobuf:"bytes,10,opt,name=attributes,proto3" json:"attributes,omitempty"`                         // Attributes decoded from lv_attr.
+	DataPercent  float64                `protobuf:"fixed64,11,opt,name=data_percent,json=dataPercent,proto3" json:"data_percent,omitempty"`  // Data percent occupied on the thin volume or the snapshot.
+	CreationTime *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=creation_time,json=creationTime,proto3" json:"creation_time,omitempty"` // The time when the logical volume was created.
 }
 
 func (x *LogicalVolume) Reset() {
@@ -145,6 +217,159 @@ func (x *LogicalVolume) GetTags() []string {
 	return nil
 }
 
+func (x *LogicalVolume) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *LogicalVolume) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *LogicalVolume) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *LogicalVolume) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *LogicalVolume) GetAttributes() *LVAttributes {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *LogicalVolume) GetDataPercent() float64 {
+	if x != nil {
+		return x.DataPercent
+	}
+	return 0
+}
+
+func (x *LogicalVolume) GetCreationTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreationTime
+	}
+	return nil
+}
+
+// Represents attributes of a logical volume decoded from lv_attr.
+type LVAttributes struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Attr     string `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`                          // The raw lv_attr.
+	Active   bool   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`                     // True if the logical volume is active.
+	Open     bool   `protobuf:"varint,3,opt,name=open,proto3" json:"open,omitempty"`                         // True if the device of the logical volume is open.
+	ReadOnly bool   `protobuf:"varint,4,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"` // True if the logical volume is read-only.
+	Snapshot bool   `protobuf:"varint,5,opt,name=snapshot,proto3" json:"snapshot,omitempty"`                 // True if the logical volume is a thick or thin snapshot.
+	Thin     bool   `protobuf:"varint,6,opt,name=thin,proto3" json:"thin,omitempty"`                         // True if the logical volume is a thin volume.
+	Raid     bool   `protobuf:"varint,7,opt,name=raid,proto3" json:"raid,omitempty"`                         // True if the logical volume is a RAID volume.
+	Health   string `protobuf:"bytes,8,opt,name=health,proto3" json:"health,omitempty"`                      // One of ok, partial, refresh-needed, mismatches, writemostly, reshaping, reshape-removed, failed, out-of-data-space, metadata-read-only or unknown.
+}
+
+func (x *LVAttributes) Reset() {
+	*x = LVAttributes{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LVAttributes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LVAttributes) ProtoMessage() {}
+
+func (x *LVAttributes) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LVAttributes.ProtoReflect.Descriptor instead.
+func (*LVAttributes) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LVAttributes) GetAttr() string {
+	if x != nil {
+		return x.Attr
+	}
+	return ""
+}
+
+func (x *LVAttributes) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *LVAttributes) GetOpen() bool {
+	if x != nil {
+		return x.Open
+	}
+	return false
+}
+
+func (x *LVAttributes) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *LVAttributes) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+func (x *LVAttributes) GetThin() bool {
+	if x != nil {
+		return x.Thin
+	}
+	return false
+}
+
+func (x *LVAttributes) GetRaid() bool {
+	if x != nil {
+		return x.Raid
+	}
+	return false
+}
+
+func (x *LVAttributes) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
 // Represents the input for CreateLV.
 type CreateLVRequest struct {
 	state         protoimpl.MessageState
@@ -161,7 +386,7 @@ type CreateLVRequest struct {
 func (x *CreateLVRequest) Reset() {
 	*x = CreateLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[2]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -174,7 +399,7 @@ func (x *CreateLVRequest) String() string {
 func (*CreateLVRequest) ProtoMessage() {}
 
 func (x *CreateLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[2]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -187,7 +412,7 @@ func (x *CreateLVRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateLVRequest.ProtoReflect.Descriptor instead.
 func (*CreateLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{2}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateLVRequest) GetName() string {
@@ -237,7 +462,7 @@ type CreateLVResponse struct {
 func (x *CreateLVResponse) Reset() {
 	*x = CreateLVResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[3]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -250,7 +475,7 @@ func (x *CreateLVResponse) String() string {
 func (*CreateLVResponse) ProtoMessage() {}
 
 func (x *CreateLVResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[3]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -263,7 +488,7 @@ func (x *CreateLVResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateLVResponse.ProtoReflect.Descriptor instead.
 func (*CreateLVResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{3}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateLVResponse) GetVolume() *LogicalVolume {
@@ -286,7 +511,7 @@ type RemoveLVRequest struct {
 func (x *RemoveLVRequest) Reset() {
 	*x = RemoveLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[4]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -299,7 +524,7 @@ func (x *RemoveLVRequest) String() string {
 func (*RemoveLVRequest) ProtoMessage() {}
 
 func (x *RemoveLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[4]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,7 +537,7 @@ func (x *RemoveLVRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveLVRequest.ProtoReflect.Descriptor instead.
 func (*RemoveLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{4}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *RemoveLVRequest) GetName() string {
@@ -345,7 +570,7 @@ type CreateLVSnapshotRequest struct {
 func (x *CreateLVSnapshotRequest) Reset() {
 	*x = CreateLVSnapshotRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -358,7 +583,7 @@ func (x *CreateLVSnapshotRequest) String() string {
 func (*CreateLVSnapshotRequest) ProtoMessage() {}
 
 func (x *CreateLVSnapshotRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[5]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +596,7 @@ func (x *CreateLVSnapshotRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateLVSnapshotRequest.ProtoReflect.Descriptor instead.
 func (*CreateLVSnapshotRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{5}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateLVSnapshotRequest) GetName() string {
@@ -427,7 +652,7 @@ type CreateLVSnapshotResponse struct {
 func (x *CreateLVSnapshotResponse) Reset() {
 	*x = CreateLVSnapshotResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -440,7 +665,7 @@ func (x *CreateLVSnapshotResponse) String() string {
 func (*CreateLVSnapshotResponse) ProtoMessage() {}
 
 func (x *CreateLVSnapshotResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[6]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -453,7 +678,7 @@ func (x *CreateLVSnapshotResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateLVSnapshotResponse.ProtoReflect.Descriptor instead.
 func (*CreateLVSnapshotResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{6}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateLVSnapshotResponse) GetSnapshot() *LogicalVolume {
@@ -463,6 +688,112 @@ func (x *CreateLVSnapshotResponse) GetSnapshot() *LogicalVolume {
 	return nil
 }
 
+// Represents the input for CreateLVSnapshots.
+//
+// All the source volumes must be thin volumes in the device class.
+type CreateLVSnapshotsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceClass string                     `protobuf:"bytes,1,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	Snapshots   []*CreateLVSnapshotRequest `protobuf:"bytes,2,rep,name=snapshots,proto3" json:"snapshots,omitempty"` // Snapshots to be created at the same instant. Their device_class is ignored.
+}
+
+func (x *CreateLVSnapshotsRequest) Reset() {
+	*x = CreateLVSnapshotsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLVSnapshotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLVSnapshotsRequest) ProtoMessage() {}
+
+func (x *CreateLVSnapshotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLVSnapshotsRequest.ProtoReflect.Descriptor instead.
+func (*CreateLVSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateLVSnapshotsRequest) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+func (x *CreateLVSnapshotsRequest) GetSnapshots() []*CreateLVSnapshotRequest {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+// Represents the response of CreateLVSnapshots.
+type CreateLVSnapshotsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshots []*LogicalVolume `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"` // Information of the created snapshot lvs in the order of the request.
+}
+
+func (x *CreateLVSnapshotsResponse) Reset() {
+	*x = CreateLVSnapshotsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateLVSnapshotsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLVSnapshotsResponse) ProtoMessage() {}
+
+func (x *CreateLVSnapshotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLVSnapshotsResponse.ProtoReflect.Descriptor instead.
+func (*CreateLVSnapshotsResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateLVSnapshotsResponse) GetSnapshots() []*LogicalVolume {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
 // Represents the input for ResizeLV.
 //
 // The volume must already exist.
@@ -480,7 +811,7 @@ type ResizeLVRequest struct {
 func (x *ResizeLVRequest) Reset() {
 	*x = ResizeLVRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -493,7 +824,7 @@ func (x *ResizeLVRequest) String() string {
 func (*ResizeLVRequest) ProtoMessage() {}
 
 func (x *ResizeLVRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[7]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -506,7 +837,7 @@ func (x *ResizeLVRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResizeLVRequest.ProtoReflect.Descriptor instead.
 func (*ResizeLVRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{7}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ResizeLVRequest) GetName() string {
@@ -536,13 +867,14 @@ type GetLVListResponse struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Volumes []*LogicalVolume `protobuf:"bytes,1,rep,name=volumes,proto3" json:"volumes,omitempty"` // Information of volumes.
+	Volumes  []*LogicalVolume `protobuf:"bytes,1,rep,name=volumes,proto3" json:"volumes,omitempty"`                   // Information of volumes.
+	ThinPool *ThinPoolItem    `protobuf:"bytes,2,opt,name=thin_pool,json=thinPool,proto3" json:"thin_pool,omitempty"` // Information of the thinpool if the device class is thin. Unset if the thinpool cannot be inspected.
 }
 
 func (x *GetLVListResponse) Reset() {
 	*x = GetLVListResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -555,7 +887,7 @@ func (x *GetLVListResponse) String() string {
 func (*GetLVListResponse) ProtoMessage() {}
 
 func (x *GetLVListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[8]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,7 +900,7 @@ func (x *GetLVListResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLVListResponse.ProtoReflect.Descriptor instead.
 func (*GetLVListResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{8}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetLVListResponse) GetVolumes() []*LogicalVolume {
@@ -578,6 +910,13 @@ func (x *GetLVListResponse) GetVolumes() []*LogicalVolume {
 	return nil
 }
 
+func (x *GetLVListResponse) GetThinPool() *ThinPoolItem {
+	if x != nil {
+		return x.ThinPool
+	}
+	return nil
+}
+
 // Represents the response of GetFreeBytes.
 type GetFreeBytesResponse struct {
 	state         protoimpl.MessageState
@@ -590,7 +929,7 @@ type GetFreeBytesResponse struct {
 func (x *GetFreeBytesResponse) Reset() {
 	*x = GetFreeBytesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -603,7 +942,7 @@ func (x *GetFreeBytesResponse) String() string {
 func (*GetFreeBytesResponse) ProtoMessage() {}
 
 func (x *GetFreeBytesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[9]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -616,7 +955,7 @@ func (x *GetFreeBytesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFreeBytesResponse.ProtoReflect.Descriptor instead.
 func (*GetFreeBytesResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{9}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetFreeBytesResponse) GetFreeBytes() uint64 {
@@ -637,7 +976,7 @@ type GetLVListRequest struct {
 func (x *GetLVListRequest) Reset() {
 	*x = GetLVListRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[10]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -650,7 +989,7 @@ func (x *GetLVListRequest) String() string {
 func (*GetLVListRequest) ProtoMessage() {}
 
 func (x *GetLVListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[10]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -663,7 +1002,7 @@ func (x *GetLVListRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLVListRequest.ProtoReflect.Descriptor instead.
 func (*GetLVListRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{10}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetLVListRequest) GetDeviceClass() string {
@@ -684,7 +1023,7 @@ type GetFreeBytesRequest struct {
 func (x *GetFreeBytesRequest) Reset() {
 	*x = GetFreeBytesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[11]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -697,7 +1036,7 @@ func (x *GetFreeBytesRequest) String() string {
 func (*GetFreeBytesRequest) ProtoMessage() {}
 
 func (x *GetFreeBytesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[11]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -710,7 +1049,7 @@ func (x *GetFreeBytesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetFreeBytesRequest.ProtoReflect.Descriptor instead.
 func (*GetFreeBytesRequest) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{11}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetFreeBytesRequest) GetDeviceClass() string {
@@ -733,7 +1072,7 @@ type WatchResponse struct {
 func (x *WatchResponse) Reset() {
 	*x = WatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[12]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -746,7 +1085,7 @@ func (x *WatchResponse) String() string {
 func (*WatchResponse) ProtoMessage() {}
 
 func (x *WatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[12]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -759,7 +1098,7 @@ func (x *WatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
 func (*WatchResponse) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{12}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *WatchResponse) GetFreeBytes() uint64 {
@@ -782,16 +1121,18 @@ type ThinPoolItem struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DataPercent        float64 `protobuf:"fixed64,1,opt,name=data_percent,json=dataPercent,proto3" json:"data_percent,omitempty"`                     // Data percent occupied on the thinpool, used for monitoring.
-	MetadataPercent    float64 `protobuf:"fixed64,2,opt,name=metadata_percent,json=metadataPercent,proto3" json:"metadata_percent,omitempty"`         // Metadata percent occupied on the thinpool, used for monitoring.
-	OverprovisionBytes uint64  `protobuf:"varint,3,opt,name=overprovision_bytes,json=overprovisionBytes,proto3" json:"overprovision_bytes,omitempty"` // Free space on the thinpool with overprovision, used for annotating node.
-	SizeBytes          uint64  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`                            // Physical data space size of the thinpool.
+	DataPercent            float64 `protobuf:"fixed64,1,opt,name=data_percent,json=dataPercent,proto3" json:"data_percent,omitempty"`                                   // Data percent occupied on the thinpool, used for monitoring.
+	MetadataPercent        float64 `protobuf:"fixed64,2,opt,name=metadata_percent,json=metadataPercent,proto3" json:"metadata_percent,omitempty"`                       // Metadata percent occupied on the thinpool, used for monitoring.
+	OverprovisionBytes     uint64  `protobuf:"varint,3,opt,name=overprovision_bytes,json=overprovisionBytes,proto3" json:"overprovision_bytes,omitempty"`               // Free space on the thinpool with overprovision, used for annotating node.
+	SizeBytes              uint64  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`                                          // Physical data space size of the thinpool.
+	OverprovisionSizeBytes uint64  `protobuf:"varint,5,opt,name=overprovision_size_bytes,json=overprovisionSizeBytes,proto3" json:"overprovision_size_bytes,omitempty"` // Size of the thinpool with overprovision, used for annotating node.
+	Health                 string  `protobuf:"bytes,6,opt,name=health,proto3" json:"health,omitempty"`                                                                  // Health of the thinpool decoded from lv_attr. Only set in GetLVList.
 }
 
 func (x *ThinPoolItem) Reset() {
 	*x = ThinPoolItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[13]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -804,7 +1145,7 @@ func (x *ThinPoolItem) String() string {
 func (*ThinPoolItem) ProtoMessage() {}
 
 func (x *ThinPoolItem) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[13]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -817,7 +1158,7 @@ func (x *ThinPoolItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThinPoolItem.ProtoReflect.Descriptor instead.
 func (*ThinPoolItem) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{13}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ThinPoolItem) GetDataPercent() float64 {
@@ -848,22 +1189,40 @@ func (x *ThinPoolItem) GetSizeBytes() uint64 {
 	return 0
 }
 
+func (x *ThinPoolItem) GetOverprovisionSizeBytes() uint64 {
+	if x != nil {
+		return x.OverprovisionSizeBytes
+	}
+	return 0
+}
+
+func (x *ThinPoolItem) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
 // Represents the response corresponding to device class targets.
 type WatchItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	FreeBytes   uint64        `protobuf:"varint,1,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"` // Free space in the volume group in bytes.
-	DeviceClass string        `protobuf:"bytes,2,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
-	SizeBytes   uint64        `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Size of volume group in bytes.
-	ThinPool    *ThinPoolItem `protobuf:"bytes,4,opt,name=thin_pool,json=thinPool,proto3" json:"thin_pool,omitempty"`
+	FreeBytes    uint64            `protobuf:"varint,1,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"` // Free space in the volume group in bytes.
+	DeviceClass  string            `protobuf:"bytes,2,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	SizeBytes    uint64            `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"` // Size of volume group in bytes.
+	ThinPool     *ThinPoolItem     `protobuf:"bytes,4,opt,name=thin_pool,json=thinPool,proto3" json:"thin_pool,omitempty"`
+	IsDefault    bool              `protobuf:"varint,5,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`                                                                                              // True if the device class is the default one.
+	Labels       map[string]string `protobuf:"bytes,6,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`                              // Labels of the device class.
+	MkfsOptions  map[string]string `protobuf:"bytes,7,rep,name=mkfs_options,json=mkfsOptions,proto3" json:"mkfs_options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Default mkfs options of the device class for each filesystem type.
+	MountOptions []string          `protobuf:"bytes,8,rep,name=mount_options,json=mountOptions,proto3" json:"mount_options,omitempty"`                                                                                      // Default mount options of the device class.
 }
 
 func (x *WatchItem) Reset() {
 	*x = WatchItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -876,7 +1235,7 @@ func (x *WatchItem) String() string {
 func (*WatchItem) ProtoMessage() {}
 
 func (x *WatchItem) ProtoReflect() protoreflect.Message {
-	mi := &file_lvmd_proto_lvmd_proto_msgTypes[14]
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,7 +1248,7 @@ func (x *WatchItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchItem.ProtoReflect.Descriptor instead.
 func (*WatchItem) Descriptor() ([]byte, []int) {
-	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{14}
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *WatchItem) GetFreeBytes() uint64 {
@@ -920,121 +1279,516 @@ func (x *WatchItem) GetThinPool() *ThinPoolItem {
 	return nil
 }
 
+func (x *WatchItem) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *WatchItem) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *WatchItem) GetMkfsOptions() map[string]string {
+	if x != nil {
+		return x.MkfsOptions
+	}
+	return nil
+}
+
+func (x *WatchItem) GetMountOptions() []string {
+	if x != nil {
+		return x.MountOptions
+	}
+	return nil
+}
+
+// Represents the input for WatchEvents.
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DeviceClasses []string `protobuf:"bytes,1,rep,name=device_classes,json=deviceClasses,proto3" json:"device_classes,omitempty"`  // Device classes to subscribe. All device classes are subscribed if empty.
+	StartRevision uint64   `protobuf:"varint,2,opt,name=start_revision,json=startRevision,proto3" json:"start_revision,omitempty"` // Send events whose revision is greater than this. If zero, only events after the subscription are sent.
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchEventsRequest) GetDeviceClasses() []string {
+	if x != nil {
+		return x.DeviceClasses
+	}
+	return nil
+}
+
+func (x *WatchEventsRequest) GetStartRevision() uint64 {
+	if x != nil {
+		return x.StartRevision
+	}
+	return 0
+}
+
+// Represents a change of a device class.
+type WatchEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type         WatchEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=proto.WatchEvent_Type" json:"type,omitempty"`
+	Revision     uint64          `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // Revision of this event. The upper 32 bits identify the lvmd process, and the lower 32 bits are increased one by one.
+	DeviceClass  string          `protobuf:"bytes,3,opt,name=device_class,json=deviceClass,proto3" json:"device_class,omitempty"`
+	Volume       *LogicalVolume  `protobuf:"bytes,4,opt,name=volume,proto3" json:"volume,omitempty"`                                    // The logical volume for LV_CREATED, LV_REMOVED, LV_RESIZED and SNAPSHOT_CREATED.
+	OldSizeBytes uint64          `protobuf:"varint,5,opt,name=old_size_bytes,json=oldSizeBytes,proto3" json:"old_size_bytes,omitempty"` // The size before the change for LV_RESIZED and VG_SIZE_CHANGED.
+	NewSizeBytes uint64          `protobuf:"varint,6,opt,name=new_size_bytes,json=newSizeBytes,proto3" json:"new_size_bytes,omitempty"` // The size after the change for LV_RESIZED and VG_SIZE_CHANGED.
+	Threshold    float64         `protobuf:"fixed64,7,opt,name=threshold,proto3" json:"threshold,omitempty"`                            // The crossed threshold in percent for THIN_POOL_*_THRESHOLD_CROSSED.
+	OldPercent   float64         `protobuf:"fixed64,8,opt,name=old_percent,json=oldPercent,proto3" json:"old_percent,omitempty"`        // The percent before the change for THIN_POOL_*_THRESHOLD_CROSSED.
+	NewPercent   float64         `protobuf:"fixed64,9,opt,name=new_percent,json=newPercent,proto3" json:"new_percent,omitempty"`        // The percent after the change for THIN_POOL_*_THRESHOLD_CROSSED.
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchEvent) GetType() WatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEvent_UNKNOWN
+}
+
+func (x *WatchEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetDeviceClass() string {
+	if x != nil {
+		return x.DeviceClass
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetVolume() *LogicalVolume {
+	if x != nil {
+		return x.Volume
+	}
+	return nil
+}
+
+func (x *WatchEvent) GetOldSizeBytes() uint64 {
+	if x != nil {
+		return x.OldSizeBytes
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetNewSizeBytes() uint64 {
+	if x != nil {
+		return x.NewSizeBytes
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetOldPercent() float64 {
+	if x != nil {
+		return x.OldPercent
+	}
+	return 0
+}
+
+func (x *WatchEvent) GetNewPercent() float64 {
+	if x != nil {
+		return x.NewPercent
+	}
+	return 0
+}
+
+// Represents the stream output from WatchEvents.
+type WatchEventsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Revision  uint64         `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`   // The latest revision. Pass this as start_revision to resume watching.
+	Events    []*WatchEvent  `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`        // Events of the subscribed device classes since the last response.
+	Capacity  *WatchResponse `protobuf:"bytes,3,opt,name=capacity,proto3" json:"capacity,omitempty"`    // Capacity snapshot of the subscribed device classes.
+	Compacted bool           `protobuf:"varint,4,opt,name=compacted,proto3" json:"compacted,omitempty"` // True if some events since start_revision are no longer available. Clients should resync their state.
+}
+
+func (x *WatchEventsResponse) Reset() {
+	*x = WatchEventsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_lvmd_proto_lvmd_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsResponse) ProtoMessage() {}
+
+func (x *WatchEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lvmd_proto_lvmd_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsResponse.ProtoReflect.Descriptor instead.
+func (*WatchEventsResponse) Descriptor() ([]byte, []int) {
+	return file_lvmd_proto_lvmd_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchEventsResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *WatchEventsResponse) GetEvents() []*WatchEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *WatchEventsResponse) GetCapacity() *WatchResponse {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+func (x *WatchEventsResponse) GetCompacted() bool {
+	if x != nil {
+		return x.Compacted
+	}
+	return false
+}
+
 var File_lvmd_proto_lvmd_proto protoreflect.FileDescriptor
 
 var file_lvmd_proto_lvmd_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x6c, 0x76, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6c, 0x76, 0x6d,
-	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x07,
-	0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x4c, 0x6f, 0x67, 0x69,
-	0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a,
-	0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
-	0x73, 0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x5f, 0x6d, 0x61,
-	0x6a, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x76, 0x4d, 0x61,
-	0x6a, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x5f, 0x6d, 0x69, 0x6e, 0x6f, 0x72,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x76, 0x4d, 0x69, 0x6e, 0x6f, 0x72,
-	0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
-	0x74, 0x61, 0x67, 0x73, 0x22, 0xa9, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c,
+	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x82, 0x03, 0x0a, 0x0d, 0x4c, 0x6f, 0x67,
+	0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x06, 0x73, 0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x5f, 0x6d,
+	0x61, 0x6a, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x76, 0x4d,
+	0x61, 0x6a, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x64, 0x65, 0x76, 0x5f, 0x6d, 0x69, 0x6e, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x65, 0x76, 0x4d, 0x69, 0x6e, 0x6f,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x6f, 0x6f,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x75, 0x75, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4c, 0x56, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x52, 0x0a, 0x61,
+	0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x74,
+	0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x64, 0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x3f, 0x0a, 0x0d,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xc7, 0x01,
+	0x0a, 0x0c, 0x4c, 0x56, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x61, 0x74, 0x74, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x74,
+	0x74, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x70,
+	0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6f, 0x70, 0x65, 0x6e, 0x12, 0x1b,
+	0x0a, 0x09, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x68, 0x69, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x74, 0x68, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x72,
+	0x61, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x72, 0x61, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x22, 0xa9, 0x01, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12,
+	0x32, 0x0a, 0x15, 0x6c, 0x76, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13,
+	0x6c, 0x76, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x22, 0x40, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06, 0x76,
+	0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x22, 0x48, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c,
 	0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07,
-	0x73, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73,
-	0x69, 0x7a, 0x65, 0x47, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x32, 0x0a, 0x15,
-	0x6c, 0x76, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x6c, 0x76, 0x63,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x61, 0x73, 0x73,
-	0x22, 0x40, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67,
-	0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06, 0x76, 0x6f, 0x6c, 0x75,
-	0x6d, 0x65, 0x22, 0x48, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76,
-	0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0xc3, 0x01, 0x0a,
-	0x17, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73,
-	0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
-	0x61, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x76, 0x6f,
-	0x6c, 0x75, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72,
-	0x63, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65,
-	0x5f, 0x67, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47,
-	0x62, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54, 0x79,
-	0x70, 0x65, 0x22, 0x4c, 0x0a, 0x18, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e,
-	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30,
-	0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22,
+	0xc3, 0x01, 0x0a, 0x17, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x5f, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73,
+	0x69, 0x7a, 0x65, 0x5f, 0x67, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69,
+	0x7a, 0x65, 0x47, 0x62, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x54, 0x79, 0x70, 0x65, 0x22, 0x4c, 0x0a, 0x18, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c,
+	0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x30, 0x0a, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69,
+	0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x22, 0x7b, 0x0a, 0x18, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61,
+	0x73, 0x73, 0x12, 0x3c, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73,
+	0x22, 0x4f, 0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a,
+	0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c,
-	0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x08, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
-	0x22, 0x61, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65, 0x5f,
-	0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47, 0x62,
+	0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74,
+	0x73, 0x22, 0x61, 0x0a, 0x0f, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x7a, 0x65,
+	0x5f, 0x67, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x73, 0x69, 0x7a, 0x65, 0x47,
+	0x62, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43,
+	0x6c, 0x61, 0x73, 0x73, 0x22, 0x75, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x6f, 0x6c,
+	0x75, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x52, 0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x74, 0x68, 0x69,
+	0x6e, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65,
+	0x6d, 0x52, 0x08, 0x74, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x22, 0x35, 0x0a, 0x14, 0x47,
+	0x65, 0x74, 0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x22, 0x35, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0x38, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x46, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
 	0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
-	0x61, 0x73, 0x73, 0x22, 0x43, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x6f, 0x6c, 0x75,
-	0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52,
-	0x07, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x73, 0x22, 0x35, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x46,
-	0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22,
-	0x35, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c,
-	0x61, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63,
-	0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x22, 0x38, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x46, 0x72, 0x65,
-	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a,
-	0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73,
-	0x22, 0x56, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
-	0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65,
-	0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x0c, 0x54, 0x68, 0x69,
-	0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x61, 0x74,
-	0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
-	0x0b, 0x64, 0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x10,
-	0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x6f, 0x76, 0x65, 0x72, 0x70,
-	0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x76, 0x65, 0x72, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73,
-	0x69, 0x6f, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65,
-	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69,
-	0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x9e, 0x01, 0x0a, 0x09, 0x57, 0x61, 0x74, 0x63,
-	0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79,
-	0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42,
-	0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63,
-	0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f,
-	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a,
-	0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x74, 0x68, 0x69, 0x6e, 0x5f, 0x70,
-	0x6f, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x54, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x08,
-	0x74, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x32, 0x81, 0x02, 0x0a, 0x09, 0x4c, 0x56, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
-	0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x12,
-	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x30, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c,
-	0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65,
-	0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x53, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74,
-	0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70,
-	0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xc3, 0x01, 0x0a,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x22, 0x56, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72, 0x65, 0x65, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x66, 0x72, 0x65, 0x65, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xfe, 0x01, 0x0a, 0x0c,
+	0x54, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x21, 0x0a, 0x0c,
+	0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0b, 0x64, 0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12,
+	0x29, 0x0a, 0x10, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x70, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x13, 0x6f, 0x76,
+	0x65, 0x72, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x76, 0x65, 0x72, 0x70, 0x72, 0x6f,
+	0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x38, 0x0a, 0x18, 0x6f, 0x76,
+	0x65, 0x72, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x16, 0x6f, 0x76,
+	0x65, 0x72, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x7a, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x22, 0xd9, 0x03, 0x0a,
+	0x09, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x72,
+	0x65, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09,
+	0x66, 0x72, 0x65, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x74,
+	0x68, 0x69, 0x6e, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x49,
+	0x74, 0x65, 0x6d, 0x52, 0x08, 0x74, 0x68, 0x69, 0x6e, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x1d, 0x0a,
+	0x0a, 0x69, 0x73, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x34, 0x0a, 0x06,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x12, 0x44, 0x0a, 0x0c, 0x6d, 0x6b, 0x66, 0x73, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x4d, 0x6b, 0x66, 0x73, 0x4f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x6d, 0x6b, 0x66,
+	0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x5f, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0c, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x1a, 0x39, 0x0a,
+	0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x4d, 0x6b, 0x66, 0x73,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x62, 0x0a, 0x12, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25,
+	0x0a, 0x0e, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x72,
+	0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x92, 0x04, 0x0a,
+	0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x2c, 0x0a, 0x06, 0x76, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c,
+	0x6f, 0x67, 0x69, 0x63, 0x61, 0x6c, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x52, 0x06, 0x76, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x6c, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x6f, 0x6c,
+	0x64, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x65,
+	0x77, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0c, 0x6e, 0x65, 0x77, 0x53, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0a, 0x6f, 0x6c, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x22, 0xbe, 0x01, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b,
+	0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x4c, 0x56, 0x5f, 0x43, 0x52, 0x45,
+	0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x4c, 0x56, 0x5f, 0x52, 0x45, 0x4d,
+	0x4f, 0x56, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x4c, 0x56, 0x5f, 0x52, 0x45, 0x53,
+	0x49, 0x5a, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x4e, 0x41, 0x50, 0x53, 0x48,
+	0x4f, 0x54, 0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x24, 0x0a, 0x20,
+	0x54, 0x48, 0x49, 0x4e, 0x5f, 0x50, 0x4f, 0x4f, 0x4c, 0x5f, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x54,
+	0x48, 0x52, 0x45, 0x53, 0x48, 0x4f, 0x4c, 0x44, 0x5f, 0x43, 0x52, 0x4f, 0x53, 0x53, 0x45, 0x44,
+	0x10, 0x05, 0x12, 0x28, 0x0a, 0x24, 0x54, 0x48, 0x49, 0x4e, 0x5f, 0x50, 0x4f, 0x4f, 0x4c, 0x5f,
+	0x4d, 0x45, 0x54, 0x41, 0x44, 0x41, 0x54, 0x41, 0x5f, 0x54, 0x48, 0x52, 0x45, 0x53, 0x48, 0x4f,
+	0x4c, 0x44, 0x5f, 0x43, 0x52, 0x4f, 0x53, 0x53, 0x45, 0x44, 0x10, 0x06, 0x12, 0x13, 0x0a, 0x0f,
+	0x56, 0x47, 0x5f, 0x53, 0x49, 0x5a, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10,
+	0x07, 0x22, 0xac, 0x01, 0x0a, 0x13, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x30, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x65, 0x64,
+	0x32, 0xd9, 0x02, 0x0a, 0x09, 0x4c, 0x56, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b,
+	0x0a, 0x08, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x4c, 0x56, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x30, 0x0a,
+	0x08, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x73, 0x69, 0x7a, 0x65, 0x4c, 0x56, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12,
+	0x53, 0x0a, 0x10, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x12, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56,
+	0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68,
+	0x6f, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4c, 0x56, 0x53, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x8b, 0x02, 0x0a,
 	0x09, 0x56, 0x47, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a, 0x09, 0x47, 0x65,
 	0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
 	0x47, 0x65, 0x74, 0x4c, 0x56, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
@@ -1047,10 +1801,14 @@ var file_lvmd_proto_lvmd_proto_rawDesc = []byte{
 	0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x0c, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x30, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
-	0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d, 0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d,
-	0x2f, 0x6c, 0x76, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x30, 0x01, 0x12, 0x46, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d,
+	0x2f, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x76, 0x6d, 0x2f, 0x6c, 0x76, 0x6d, 0x64, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1065,49 +1823,75 @@ func file_lvmd_proto_lvmd_proto_rawDescGZIP() []byte {
 	return file_lvmd_proto_lvmd_proto_rawDescData
 }
 
-var file_lvmd_proto_lvmd_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_lvmd_proto_lvmd_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_lvmd_proto_lvmd_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_lvmd_proto_lvmd_proto_goTypes = []interface{}{
-	(*Empty)(nil),                    // 0: proto.Empty
-	(*LogicalVolume)(nil),            // 1: proto.LogicalVolume
-	(*CreateLVRequest)(nil),          // 2: proto.CreateLVRequest
-	(*CreateLVResponse)(nil),         // 3: proto.CreateLVResponse
-	(*RemoveLVRequest)(nil),          // 4: proto.RemoveLVRequest
-	(*CreateLVSnapshotRequest)(nil),  // 5: proto.CreateLVSnapshotRequest
-	(*CreateLVSnapshotResponse)(nil), // 6: proto.CreateLVSnapshotResponse
-	(*ResizeLVRequest)(nil),          // 7: proto.ResizeLVRequest
-	(*GetLVListResponse)(nil),        // 8: proto.GetLVListResponse
-	(*GetFreeBytesResponse)(nil),     // 9: proto.GetFreeBytesResponse
-	(*GetLVListRequest)(nil),         // 10: proto.GetLVListRequest
-	(*GetFreeBytesRequest)(nil),      // 11: proto.GetFreeBytesRequest
-	(*WatchResponse)(nil),            // 12: proto.WatchResponse
-	(*ThinPoolItem)(nil),             // 13: proto.ThinPoolItem
-	(*WatchItem)(nil),                // 14: proto.WatchItem
+	(WatchEvent_Type)(0),              // 0: proto.WatchEvent.Type
+	(*Empty)(nil),                     // 1: proto.Empty
+	(*LogicalVolume)(nil),             // 2: proto.LogicalVolume
+	(*LVAttributes)(nil),              // 3: proto.LVAttributes
+	(*CreateLVRequest)(nil),           // 4: proto.CreateLVRequest
+	(*CreateLVResponse)(nil),          // 5: proto.CreateLVResponse
+	(*RemoveLVRequest)(nil),           // 6: proto.RemoveLVRequest
+	(*CreateLVSnapshotRequest)(nil),   // 7: proto.CreateLVSnapshotRequest
+	(*CreateLVSnapshotResponse)(nil),  // 8: proto.CreateLVSnapshotResponse
+	(*CreateLVSnapshotsRequest)(nil),  // 9: proto.CreateLVSnapshotsRequest
+	(*CreateLVSnapshotsResponse)(nil), // 10: proto.CreateLVSnapshotsResponse
+	(*ResizeLVRequest)(nil),           // 11: proto.ResizeLVRequest
+	(*GetLVListResponse)(nil),         // 12: proto.GetLVListResponse
+	(*GetFreeBytesResponse)(nil),      // 13: proto.GetFreeBytesResponse
+	(*GetLVListRequest)(nil),          // 14: proto.GetLVListRequest
+	(*GetFreeBytesRequest)(nil),       // 15: proto.GetFreeBytesRequest
+	(*WatchResponse)(nil),             // 16: proto.WatchResponse
+	(*ThinPoolItem)(nil),              // 17: proto.ThinPoolItem
+	(*WatchItem)(nil),                 // 18: proto.WatchItem
+	(*WatchEventsRequest)(nil),        // 19: proto.WatchEventsRequest
+	(*WatchEvent)(nil),                // 20: proto.WatchEvent
+	(*WatchEventsResponse)(nil),       // 21: proto.WatchEventsResponse
+	nil,                               // 22: proto.WatchItem.LabelsEntry
+	nil,                               // 23: proto.WatchItem.MkfsOptionsEntry
+	(*timestamppb.Timestamp)(nil),     // 24: google.protobuf.Timestamp
 }
 var file_lvmd_proto_lvmd_proto_depIdxs = []int32{
-	1,  // 0: proto.CreateLVResponse.volume:type_name -> proto.LogicalVolume
-	1,  // 1: proto.CreateLVSnapshotResponse.snapshot:type_name -> proto.LogicalVolume
-	1,  // 2: proto.GetLVListResponse.volumes:type_name -> proto.LogicalVolume
-	14, // 3: proto.WatchResponse.items:type_name -> proto.WatchItem
-	13, // 4: proto.WatchItem.thin_pool:type_name -> proto.ThinPoolItem
-	2,  // 5: proto.LVService.CreateLV:input_type -> proto.CreateLVRequest
-	4,  // 6: proto.LVService.RemoveLV:input_type -> proto.RemoveLVRequest
-	7,  // 7: proto.LVService.ResizeLV:input_type -> proto.ResizeLVRequest
-	5,  // 8: proto.LVService.CreateLVSnapshot:input_type -> proto.CreateLVSnapshotRequest
-	10, // 9: proto.VGService.GetLVList:input_type -> proto.GetLVListRequest
-	11, // 10: proto.VGService.GetFreeBytes:input_type -> proto.GetFreeBytesRequest
-	0,  // 11: proto.VGService.Watch:input_type -> proto.Empty
-	3,  // 12: proto.LVService.CreateLV:output_type -> proto.CreateLVResponse
-	0,  // 13: proto.LVService.RemoveLV:output_type -> proto.Empty
-	0,  // 14: proto.LVService.ResizeLV:output_type -> proto.Empty
-	6,  // 15: proto.LVService.CreateLVSnapshot:output_type -> proto.CreateLVSnapshotResponse
-	8,  // 16: proto.VGService.GetLVList:output_type -> proto.GetLVListResponse
-	9,  // 17: proto.VGService.GetFreeBytes:output_type -> proto.GetFreeBytesResponse
-	12, // 18: proto.VGService.Watch:output_type -> proto.WatchResponse
-	12, // [12:19] is the sub-list for method output_type
-	5,  // [5:12] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	3,  // 0: proto.LogicalVolume.attributes:type_name -> proto.LVAttributes
+	24, // 1: proto.LogicalVolume.creation_time:type_name -> google.protobuf.Timestamp
+	2,  // 2: proto.CreateLVResponse.volume:type_name -> proto.LogicalVolume
+	2,  // 3: proto.CreateLVSnapshotResponse.snapshot:type_name -> proto.LogicalVolume
+	7,  // 4: proto.CreateLVSnapshotsRequest.snapshots:type_name -> proto.CreateLVSnapshotRequest
+	2,  // 5: proto.CreateLVSnapshotsResponse.snapshots:type_name -> proto.LogicalVolume
+	2,  // 6: proto.GetLVListResponse.volumes:type_name -> proto.LogicalVolume
+	17, // 7: proto.GetLVListResponse.thin_pool:type_name -> proto.ThinPoolItem
+	18, // 8: proto.WatchResponse.items:type_name -> proto.WatchItem
+	17, // 9: proto.WatchItem.thin_pool:type_name -> proto.ThinPoolItem
+	22, // 10: proto.WatchItem.labels:type_name -> proto.WatchItem.LabelsEntry
+	23, // 11: proto.WatchItem.mkfs_options:type_name -> proto.WatchItem.MkfsOptionsEntry
+	0,  // 12: proto.WatchEvent.type:type_name -> proto.WatchEvent.Type
+	2,  // 13: proto.WatchEvent.volume:type_name -> proto.LogicalVolume
+	20, // 14: proto.WatchEventsResponse.events:type_name -> proto.WatchEvent
+	16, // 15: proto.WatchEventsResponse.capacity:type_name -> proto.WatchResponse
+	4,  // 16: proto.LVService.CreateLV:input_type -> proto.CreateLVRequest
+	6,  // 17: proto.LVService.RemoveLV:input_type -> proto.RemoveLVRequest
+	11, // 18: proto.LVService.ResizeLV:input_type -> proto.ResizeLVRequest
+	7,  // 19: proto.LVService.CreateLVSnapshot:input_type -> proto.CreateLVSnapshotRequest
+	9,  // 20: proto.LVService.CreateLVSnapshots:input_type -> proto.CreateLVSnapshotsRequest
+	14, // 21: proto.VGService.GetLVList:input_type -> proto.GetLVListRequest
+	15, // 22: proto.VGService.GetFreeBytes:input_type -> proto.GetFreeBytesRequest
+	1,  // 23: proto.VGService.Watch:input_type -> proto.Empty
+	19, // 24: proto.VGService.WatchEvents:input_type -> proto.WatchEventsRequest
+	5,  // 25: proto.LVService.CreateLV:output_type -> proto.CreateLVResponse
+	1,  // 26: proto.LVService.RemoveLV:output_type -> proto.Empty
+	1,  // 27: proto.LVService.ResizeLV:output_type -> proto.Empty
+	8,  // 28: proto.LVService.CreateLVSnapshot:output_type -> proto.CreateLVSnapshotResponse
+	10, // 29: proto.LVService.CreateLVSnapshots:output_type -> proto.CreateLVSnapshotsResponse
+	12, // 30: proto.VGService.GetLVList:output_type -> proto.GetLVListResponse
+	13, // 31: proto.VGService.GetFreeBytes:output_type -> proto.GetFreeBytesResponse
+	16, // 32: proto.VGService.Watch:output_type -> proto.WatchResponse
+	21, // 33: proto.VGService.WatchEvents:output_type -> proto.WatchEventsResponse
+	25, // [25:34] is the sub-list for method output_type
+	16, // [16:25] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_lvmd_proto_lvmd_proto_init() }
@@ -1141,7 +1925,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateLVRequest); i {
+			switch v := v.(*LVAttributes); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1153,7 +1937,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateLVResponse); i {
+			switch v := v.(*CreateLVRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1165,7 +1949,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RemoveLVRequest); i {
+			switch v := v.(*CreateLVResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1177,7 +1961,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateLVSnapshotRequest); i {
+			switch v := v.(*RemoveLVRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1189,7 +1973,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateLVSnapshotResponse); i {
+			switch v := v.(*CreateLVSnapshotRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1201,7 +1985,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResizeLVRequest); i {
+			switch v := v.(*CreateLVSnapshotResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1213,7 +1997,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetLVListResponse); i {
+			switch v := v.(*CreateLVSnapshotsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1225,7 +2009,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetFreeBytesResponse); i {
+			switch v := v.(*CreateLVSnapshotsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1237,7 +2021,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetLVListRequest); i {
+			switch v := v.(*ResizeLVRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1249,7 +2033,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetFreeBytesRequest); i {
+			switch v := v.(*GetLVListResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1261,7 +2045,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WatchResponse); i {
+			switch v := v.(*GetFreeBytesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1273,7 +2057,7 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ThinPoolItem); i {
+			switch v := v.(*GetLVListRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1285,6 +2069,42 @@ func file_lvmd_proto_lvmd_proto_init() {
 			}
 		}
 		file_lvmd_proto_lvmd_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFreeBytesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ThinPoolItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*WatchItem); i {
 			case 0:
 				return &v.state
@@ -1296,19 +2116,56 @@ func file_lvmd_proto_lvmd_proto_init() {
 				return nil
 			}
 		}
+		file_lvmd_proto_lvmd_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchEventsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_lvmd_proto_lvmd_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchEventsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_lvmd_proto_lvmd_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   15,
+			NumEnums:      1,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
 		GoTypes:           file_lvmd_proto_lvmd_proto_goTypes,
 		DependencyIndexes: file_lvmd_proto_lvmd_proto_depIdxs,
+		EnumInfos:         file_lvmd_proto_lvmd_proto_enumTypes,
 		MessageInfos:      file_lvmd_proto_lvmd_proto_msgTypes,
 	}.Build()
 	File_lvmd_proto_lvmd_proto = out.File
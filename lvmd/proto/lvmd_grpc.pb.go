@@ -29,6 +29,9 @@ type LVServiceClient interface {
 	// Resize a logical volume.
 	ResizeLV(ctx context.Context, in *ResizeLVRequest, opts ...grpc.CallOption) (*Empty, error)
 	CreateLVSnapshot(ctx context.Context, in *CreateLVSnapshotRequest, opts ...grpc.CallOption) (*CreateLVSnapshotResponse, error)
+	// Create snapshots of multiple logical volumes at the same instant.
+	// The source volumes are suspended until all the snapshots are created.
+	CreateLVSnapshots(ctx context.Context, in *CreateLVSnapshotsRequest, opts ...grpc.CallOption) (*CreateLVSnapshotsResponse, error)
 }
 
 type lVServiceClient struct {
@@ -75,6 +78,15 @@ func (c *lVServiceClient) CreateLVSnapshot(ctx context.Context, in *CreateLVSnap
 	return out, nil
 }
 
+func (c *lVServiceClient) CreateLVSnapshots(ctx context.Context, in *CreateLVSnapshotsRequest, opts ...grpc.CallOption) (*CreateLVSnapshotsResponse, error) {
+	out := new(CreateLVSnapshotsResponse)
+	err := c.cc.Invoke(ctx, "/proto.LVService/CreateLVSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LVServiceServer is the server API for LVService service.
 // All implementations must embed UnimplementedLVServiceServer
 // for forward compatibility
@@ -86,6 +98,9 @@ type LVServiceServer interface {
 	// Resize a logical volume.
 	ResizeLV(context.Context, *ResizeLVRequest) (*Empty, error)
 	CreateLVSnapshot(context.Context, *CreateLVSnapshotRequest) (*CreateLVSnapshotResponse, error)
+	// Create snapshots of multiple logical volumes at the same instant.
+	// The source volumes are suspended until all the snapshots are created.
+	CreateLVSnapshots(context.Context, *CreateLVSnapshotsRequest) (*CreateLVSnapshotsResponse, error)
 	mustEmbedUnimplementedLVServiceServer()
 }
 
@@ -105,6 +120,9 @@ func (UnimplementedLVServiceServer) ResizeLV(context.Context, *ResizeLVRequest)
 func (UnimplementedLVServiceServer) CreateLVSnapshot(context.Context, *CreateLVSnapshotRequest) (*CreateLVSnapshotResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateLVSnapshot not implemented")
 }
+func (UnimplementedLVServiceServer) CreateLVSnapshots(context.Context, *CreateLVSnapshotsRequest) (*CreateLVSnapshotsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateLVSnapshots not implemented")
+}
 func (UnimplementedLVServiceServer) mustEmbedUnimplementedLVServiceServer() {}
 
 // UnsafeLVServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -190,6 +208,24 @@ func _LVService_CreateLVSnapshot_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LVService_CreateLVSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateLVSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LVServiceServer).CreateLVSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LVService/CreateLVSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LVServiceServer).CreateLVSnapshots(ctx, req.(*CreateLVSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LVService_ServiceDesc is the grpc.ServiceDesc for LVService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -213,6 +249,10 @@ var LVService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateLVSnapshot",
 			Handler:    _LVService_CreateLVSnapshot_Handler,
 		},
+		{
+			MethodName: "CreateLVSnapshots",
+			Handler:    _LVService_CreateLVSnapshots_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "lvmd/proto/lvmd.proto",
@@ -228,6 +268,8 @@ type VGServiceClient interface {
 	GetFreeBytes(ctx context.Context, in *GetFreeBytesRequest, opts ...grpc.CallOption) (*GetFreeBytesResponse, error)
 	// Stream the volume group metrics.
 	Watch(ctx context.Context, in *Empty, opts ...grpc.CallOption) (VGService_WatchClient, error)
+	// Stream typed events and capacity snapshots of the subscribed device classes.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (VGService_WatchEventsClient, error)
 }
 
 type vGServiceClient struct {
@@ -288,6 +330,38 @@ func (x *vGServiceWatchClient) Recv() (*WatchResponse, error) {
 	return m, nil
 }
 
+func (c *vGServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (VGService_WatchEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &VGService_ServiceDesc.Streams[1], "/proto.VGService/WatchEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &vGServiceWatchEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type VGService_WatchEventsClient interface {
+	Recv() (*WatchEventsResponse, error)
+	grpc.ClientStream
+}
+
+type vGServiceWatchEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *vGServiceWatchEventsClient) Recv() (*WatchEventsResponse, error) {
+	m := new(WatchEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // VGServiceServer is the server API for VGService service.
 // All implementations must embed UnimplementedVGServiceServer
 // for forward compatibility
@@ -298,6 +372,8 @@ type VGServiceServer interface {
 	GetFreeBytes(context.Context, *GetFreeBytesRequest) (*GetFreeBytesResponse, error)
 	// Stream the volume group metrics.
 	Watch(*Empty, VGService_WatchServer) error
+	// Stream typed events and capacity snapshots of the subscribed device classes.
+	WatchEvents(*WatchEventsRequest, VGService_WatchEventsServer) error
 	mustEmbedUnimplementedVGServiceServer()
 }
 
@@ -314,6 +390,9 @@ func (UnimplementedVGServiceServer) GetFreeBytes(context.Context, *GetFreeBytesR
 func (UnimplementedVGServiceServer) Watch(*Empty, VGService_WatchServer) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
+func (UnimplementedVGServiceServer) WatchEvents(*WatchEventsRequest, VGService_WatchEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
 func (UnimplementedVGServiceServer) mustEmbedUnimplementedVGServiceServer() {}
 
 // UnsafeVGServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -384,6 +463,27 @@ func (x *vGServiceWatchServer) Send(m *WatchResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _VGService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VGServiceServer).WatchEvents(m, &vGServiceWatchEventsServer{stream})
+}
+
+type VGService_WatchEventsServer interface {
+	Send(*WatchEventsResponse) error
+	grpc.ServerStream
+}
+
+type vGServiceWatchEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *vGServiceWatchEventsServer) Send(m *WatchEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // VGService_ServiceDesc is the grpc.ServiceDesc for VGService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -406,6 +506,11 @@ var VGService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _VGService_Watch_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _VGService_WatchEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lvmd/proto/lvmd.proto",
 }
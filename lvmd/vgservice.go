@@ -11,6 +11,7 @@ import (
 	"github.com/topolvm/topolvm/lvmd/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // NewVGService creates a VGServiceServer
@@ -18,7 +19,9 @@ func NewVGService(manager *DeviceClassManager) (proto.VGServiceServer, func()) {
 	svc := &vgService{
 		dcManager: manager,
 		watchers:  make(map[int]chan struct{}),
+		history:   newEventHistory(),
 	}
+	svc.collect = svc.collectStates
 
 	return svc, svc.notifyWatchers
 }
@@ -27,10 +30,23 @@ type vgService struct {
 	proto.UnimplementedVGServiceServer
 	dcManager *DeviceClassManager
 
-	// mu protects watcherCounter and watchers. must take it when use them.
+	// mu protects watcherCounter, watchers and changes. must take it when use them.
 	mu             sync.Mutex
 	watcherCounter int
 	watchers       map[int]chan struct{}
+	// changes counts the notifications of changes made by lvmd.
+	changes uint64
+
+	// recordMu serializes collecting and recording states,
+	// so that the states are recorded in the order they are collected.
+	// It also protects recordedChanges.
+	recordMu sync.Mutex
+	// recordedChanges is the value of changes when the states are recorded last time.
+	recordedChanges uint64
+	// collect collects the states to be recorded. It is replaced in tests.
+	collect func() (map[string]*deviceClassState, error)
+	// history keeps events for WatchEvents.
+	history *eventHistory
 }
 
 func (s *vgService) GetLVList(_ context.Context, req *proto.GetLVListRequest) (*proto.GetLVListResponse, error) {
@@ -43,15 +59,67 @@ func (s *vgService) GetLVList(_ context.Context, req *proto.GetLVListRequest) (*
 		return nil, err
 	}
 
+	lvs, err := listVolumes(vg, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	vols := make([]*proto.LogicalVolume, 0, len(lvs))
+	for _, lv := range lvs {
+		vols = append(vols, toProtoLV(lv))
+	}
+	resp := &proto.GetLVListResponse{Volumes: vols}
+
+	if dc.Type == TypeThin {
+		resp.ThinPool = thinPoolHealth(vg, dc)
+	}
+	return resp, nil
+}
+
+// thinPoolHealth returns the usage and the health of the thin pool of the device-class.
+// The thin pool is only informational for GetLVList, so this returns nil
+// instead of failing the whole list when it cannot be inspected.
+func thinPoolHealth(vg *command.VolumeGroup, dc *DeviceClass) *proto.ThinPoolItem {
+	pool, err := vg.FindPool(dc.ThinPoolConfig.Name)
+	if err != nil {
+		log.Error("failed to get thinpool", map[string]interface{}{
+			log.FnError:    err,
+			"device_class": dc.Name,
+		})
+		return nil
+	}
+	tpu, err := pool.Free()
+	if err != nil {
+		log.Error("failed to get free bytes", map[string]interface{}{
+			log.FnError:    err,
+			"device_class": dc.Name,
+		})
+		return nil
+	}
+	return &proto.ThinPoolItem{
+		DataPercent:     tpu.DataPercent,
+		MetadataPercent: tpu.MetadataPercent,
+		SizeBytes:       tpu.SizeBytes,
+		Health:          string(pool.Attr().Health()),
+	}
+}
+
+// listVolumes returns the logical volumes of the device-class.
+func listVolumes(vg *command.VolumeGroup, dc *DeviceClass) ([]*command.LogicalVolume, error) {
 	var lvs []*command.LogicalVolume
 
 	switch dc.Type {
 	case TypeThick:
 		// thick logicalvolumes
-		lvs = vg.ListVolumes()
+		for _, lv := range vg.ListVolumes() {
+			if lv.IsThin() {
+				// do not send thin lvs if request is on TypeThick
+				continue
+			}
+			lvs = append(lvs, lv)
+		}
 	case TypeThin:
-		var pool *command.ThinPool
-		pool, err = vg.FindPool(dc.ThinPoolConfig.Name)
+		pool, err := vg.FindPool(dc.ThinPoolConfig.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -62,22 +130,37 @@ func (s *vgService) GetLVList(_ context.Context, req *proto.GetLVListRequest) (*
 		// in such cases where deviceclass target is neither thick or thinpool
 		return nil, status.Error(codes.Internal, fmt.Sprintf("unsupported device class target: %s", dc.Type))
 	}
+	return lvs, nil
+}
 
-	vols := make([]*proto.LogicalVolume, 0, len(lvs))
-	for _, lv := range lvs {
-		if dc.Type == TypeThick && lv.IsThin() {
-			// do not send thin lvs if request is on TypeThick
-			continue
-		}
-		vols = append(vols, &proto.LogicalVolume{
-			Name:     lv.Name(),
-			SizeGb:   (lv.Size() + (1 << 30) - 1) >> 30,
-			DevMajor: lv.MajorNumber(),
-			DevMinor: lv.MinorNumber(),
-			Tags:     lv.Tags(),
-		})
+func toProtoLV(lv *command.LogicalVolume) *proto.LogicalVolume {
+	attr := lv.Attr()
+	ret := &proto.LogicalVolume{
+		Name:      lv.Name(),
+		SizeGb:    (lv.Size() + (1 << 30) - 1) >> 30,
+		DevMajor:  lv.MajorNumber(),
+		DevMinor:  lv.MinorNumber(),
+		Tags:      lv.Tags(),
+		Origin:    lv.OriginName(),
+		Pool:      lv.PoolName(),
+		Uuid:      lv.UUID(),
+		SizeBytes: lv.Size(),
+		Attributes: &proto.LVAttributes{
+			Attr:     string(attr),
+			Active:   attr.IsActive(),
+			Open:     attr.IsOpen(),
+			ReadOnly: attr.IsReadOnly(),
+			Snapshot: lv.IsSnapshot(),
+			Thin:     lv.IsThin(),
+			Raid:     attr.IsRAID(),
+			Health:   string(attr.Health()),
+		},
+		DataPercent: lv.DataPercent(),
 	}
-	return &proto.GetLVListResponse{Volumes: vols}, nil
+	if !lv.CreatedAt().IsZero() {
+		ret.CreationTime = timestamppb.New(lv.CreatedAt())
+	}
+	return ret
 }
 
 func (s *vgService) GetFreeBytes(_ context.Context, req *proto.GetFreeBytesRequest) (*proto.GetFreeBytesResponse, error) {
@@ -136,20 +219,28 @@ func (s *vgService) GetFreeBytes(_ context.Context, req *proto.GetFreeBytesReque
 }
 
 func (s *vgService) send(server proto.VGService_WatchServer) error {
-	vgs, err := command.ListVolumeGroups()
+	res, err := s.watchResponse()
 	if err != nil {
 		return err
 	}
+	return server.Send(res)
+}
+
+func (s *vgService) watchResponse() (*proto.WatchResponse, error) {
+	vgs, err := command.ListVolumeGroups()
+	if err != nil {
+		return nil, err
+	}
 	res := &proto.WatchResponse{}
 	for _, vg := range vgs {
 
 		vgFree, err := vg.Free()
 		if err != nil {
-			return status.Error(codes.Internal, err.Error())
+			return nil, status.Error(codes.Internal, err.Error())
 		}
 		vgSize, err := vg.Size()
 		if err != nil {
-			return status.Error(codes.Internal, err.Error())
+			return nil, status.Error(codes.Internal, err.Error())
 		}
 
 		for _, pool := range vg.ListPools() {
@@ -163,11 +254,11 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 			tpi := &proto.ThinPoolItem{}
 			pool, err := vg.FindPool(dc.ThinPoolConfig.Name)
 			if err != nil {
-				return status.Error(codes.Internal, err.Error())
+				return nil, status.Error(codes.Internal, err.Error())
 			}
 			tpu, err := pool.Free()
 			if err != nil {
-				return status.Error(codes.Internal, err.Error())
+				return nil, status.Error(codes.Internal, err.Error())
 			}
 
 			// used for updating prometheus metrics
@@ -175,8 +266,10 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 			tpi.MetadataPercent = tpu.MetadataPercent
 
 			// used for annotating the node for capacity aware scheduling
-			opb := uint64(math.Floor(dc.ThinPoolConfig.OverprovisionRatio*float64(tpu.SizeBytes))) - tpu.VirtualBytes
+			ops := uint64(math.Floor(dc.ThinPoolConfig.OverprovisionRatio * float64(tpu.SizeBytes)))
+			opb := ops - tpu.VirtualBytes
 			tpi.OverprovisionBytes = opb
+			tpi.OverprovisionSizeBytes = ops
 			if dc.Default {
 				res.FreeBytes = opb
 			}
@@ -186,10 +279,14 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 
 			// include thinpoolitem in the response
 			res.Items = append(res.Items, &proto.WatchItem{
-				DeviceClass: dc.Name,
-				FreeBytes:   vgFree,
-				SizeBytes:   vgSize,
-				ThinPool:    tpi,
+				DeviceClass:  dc.Name,
+				FreeBytes:    vgFree,
+				SizeBytes:    vgSize,
+				ThinPool:     tpi,
+				IsDefault:    dc.Default,
+				Labels:       dc.Labels,
+				MkfsOptions:  dc.MkfsOptions,
+				MountOptions: dc.MountOptions,
 			})
 		}
 
@@ -210,12 +307,16 @@ func (s *vgService) send(server proto.VGService_WatchServer) error {
 		}
 
 		res.Items = append(res.Items, &proto.WatchItem{
-			DeviceClass: dc.Name,
-			FreeBytes:   vgFree,
-			SizeBytes:   vgSize,
+			DeviceClass:  dc.Name,
+			FreeBytes:    vgFree,
+			SizeBytes:    vgSize,
+			IsDefault:    dc.Default,
+			Labels:       dc.Labels,
+			MkfsOptions:  dc.MkfsOptions,
+			MountOptions: dc.MountOptions,
 		})
 	}
-	return server.Send(res)
+	return res, nil
 }
 
 func (s *vgService) addWatcher(ch chan struct{}) int {
@@ -236,10 +337,113 @@ func (s *vgService) removeWatcher(num int) {
 	delete(s.watchers, num)
 }
 
+// collectStates returns snapshots of device-classes to generate events.
+// The device-classes whose state cannot be collected are logged and omitted.
+func (s *vgService) collectStates() (map[string]*deviceClassState, error) {
+	vgs, err := command.ListVolumeGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]*deviceClassState)
+	for name, dc := range s.dcManager.deviceClassByName {
+		state, err := collectDeviceClassState(vgs, dc)
+		if err != nil {
+			log.Error("failed to collect state of device-class", map[string]interface{}{
+				log.FnError:    err,
+				"device_class": name,
+			})
+			continue
+		}
+		states[name] = state
+	}
+	return states, nil
+}
+
+func collectDeviceClassState(vgs []*command.VolumeGroup, dc *DeviceClass) (*deviceClassState, error) {
+	vg, err := command.SearchVolumeGroupList(vgs, dc.VolumeGroup)
+	if err != nil {
+		return nil, err
+	}
+	vgSize, err := vg.Size()
+	if err != nil {
+		return nil, err
+	}
+	lvs, err := listVolumes(vg, dc)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &deviceClassState{
+		vgSize:  vgSize,
+		volumes: make(map[string]*volumeState),
+	}
+	for _, lv := range lvs {
+		state.volumes[lv.Name()] = &volumeState{
+			volume:    toProtoLV(lv),
+			sizeBytes: lv.Size(),
+		}
+	}
+	if dc.Type == TypeThin {
+		pool, err := vg.FindPool(dc.ThinPoolConfig.Name)
+		if err != nil {
+			return nil, err
+		}
+		state.pool, err = pool.Free()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return state, nil
+}
+
+func (s *vgService) currentChanges() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changes
+}
+
+// recordEvents records the current states of device-classes.
+func (s *vgService) recordEvents() {
+	s.recordMu.Lock()
+	defer s.recordMu.Unlock()
+
+	s.recordStatesLocked(s.currentChanges())
+}
+
+// recordChanges records the states of device-classes if lvmd has changed them since the last record.
+// Every WatchEvents subscriber calls this when notified, and only the first one collects the states.
+func (s *vgService) recordChanges() {
+	s.recordMu.Lock()
+	defer s.recordMu.Unlock()
+
+	changes := s.currentChanges()
+	if changes == s.recordedChanges {
+		return
+	}
+	s.recordStatesLocked(changes)
+}
+
+func (s *vgService) recordStatesLocked(changes uint64) {
+	states, err := s.collect()
+	if err != nil {
+		log.Error("failed to collect states of device-classes", map[string]interface{}{
+			log.FnError: err,
+		})
+		return
+	}
+	s.history.record(states)
+	s.recordedChanges = changes
+}
+
+// notifyWatchers wakes up the subscribers of Watch and WatchEvents.
+// This is called after every volume operation, so it must not collect states by itself.
+// Changes made while nobody watches events are caught up when the next subscriber comes.
 func (s *vgService) notifyWatchers() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.changes++
 	for _, ch := range s.watchers {
 		select {
 		case ch <- struct{}{}:
@@ -268,3 +472,71 @@ func (s *vgService) Watch(_ *proto.Empty, server proto.VGService_WatchServer) er
 		}
 	}
 }
+
+func (s *vgService) WatchEvents(req *proto.WatchEventsRequest, server proto.VGService_WatchEventsServer) error {
+	deviceClasses := make(map[string]bool)
+	for _, name := range req.DeviceClasses {
+		dc, err := s.dcManager.DeviceClass(name)
+		if err != nil {
+			return status.Errorf(codes.NotFound, "%s: %s", err.Error(), name)
+		}
+		deviceClasses[dc.Name] = true
+	}
+
+	ch := make(chan struct{}, 1)
+	num := s.addWatcher(ch)
+	defer s.removeWatcher(num)
+
+	// Record the current states to take the baseline, or to catch up changes
+	// made outside of lvmd.
+	s.recordEvents()
+
+	rev := req.StartRevision
+	if rev == 0 {
+		_, rev, _ = s.history.since(0, nil)
+	}
+	rev, err := s.sendEvents(server, rev, deviceClasses)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-server.Context().Done():
+			return server.Context().Err()
+		case <-ch:
+			s.recordChanges()
+			rev, err = s.sendEvents(server, rev, deviceClasses)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendEvents sends events after rev and the capacity snapshot of deviceClasses.
+// It returns the latest revision.
+func (s *vgService) sendEvents(server proto.VGService_WatchEventsServer, rev uint64, deviceClasses map[string]bool) (uint64, error) {
+	events, latest, compacted := s.history.since(rev, deviceClasses)
+	capacity, err := s.watchResponse()
+	if err != nil {
+		return 0, err
+	}
+	if len(deviceClasses) > 0 {
+		items := make([]*proto.WatchItem, 0, len(capacity.Items))
+		for _, item := range capacity.Items {
+			if deviceClasses[item.DeviceClass] {
+				items = append(items, item)
+			}
+		}
+		capacity.Items = items
+	}
+
+	err = server.Send(&proto.WatchEventsResponse{
+		Revision:  latest,
+		Events:    events,
+		Capacity:  capacity,
+		Compacted: compacted,
+	})
+	return latest, err
+}
@@ -0,0 +1,207 @@
+package lvmd
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+// maxEventHistory is the number of events kept for resuming WatchEvents.
+const maxEventHistory = 1000
+
+// thinPoolThresholds are the thresholds in percent of thin pool usage.
+// THIN_POOL_*_THRESHOLD_CROSSED events are generated when the usage crosses them.
+var thinPoolThresholds = []float64{80, 90, 95}
+
+// deviceClassState is a snapshot of a device-class to generate events.
+type deviceClassState struct {
+	vgSize  uint64
+	volumes map[string]*volumeState
+	pool    *command.ThinPoolUsage
+}
+
+type volumeState struct {
+	volume    *proto.LogicalVolume
+	sizeBytes uint64
+}
+
+// diffDeviceClassState returns events to convert prev to cur.
+// The revisions of the returned events are not set.
+func diffDeviceClassState(dcName string, prev, cur *deviceClassState) []*proto.WatchEvent {
+	var events []*proto.WatchEvent
+
+	if prev.vgSize != cur.vgSize {
+		events = append(events, &proto.WatchEvent{
+			Type:         proto.WatchEvent_VG_SIZE_CHANGED,
+			DeviceClass:  dcName,
+			OldSizeBytes: prev.vgSize,
+			NewSizeBytes: cur.vgSize,
+		})
+	}
+
+	for _, name := range sortedVolumeNames(cur.volumes) {
+		c := cur.volumes[name]
+		p, ok := prev.volumes[name]
+		switch {
+		case !ok && c.volume.Origin != "":
+			events = append(events, &proto.WatchEvent{
+				Type:        proto.WatchEvent_SNAPSHOT_CREATED,
+				DeviceClass: dcName,
+				Volume:      c.volume,
+			})
+		case !ok:
+			events = append(events, &proto.WatchEvent{
+				Type:        proto.WatchEvent_LV_CREATED,
+				DeviceClass: dcName,
+				Volume:      c.volume,
+			})
+		case p.sizeBytes != c.sizeBytes:
+			events = append(events, &proto.WatchEvent{
+				Type:         proto.WatchEvent_LV_RESIZED,
+				DeviceClass:  dcName,
+				Volume:       c.volume,
+				OldSizeBytes: p.sizeBytes,
+				NewSizeBytes: c.sizeBytes,
+			})
+		}
+	}
+	for _, name := range sortedVolumeNames(prev.volumes) {
+		if _, ok := cur.volumes[name]; !ok {
+			events = append(events, &proto.WatchEvent{
+				Type:        proto.WatchEvent_LV_REMOVED,
+				DeviceClass: dcName,
+				Volume:      prev.volumes[name].volume,
+			})
+		}
+	}
+
+	if prev.pool != nil && cur.pool != nil {
+		for _, t := range crossedThresholds(prev.pool.DataPercent, cur.pool.DataPercent) {
+			events = append(events, &proto.WatchEvent{
+				Type:        proto.WatchEvent_THIN_POOL_DATA_THRESHOLD_CROSSED,
+				DeviceClass: dcName,
+				Threshold:   t,
+				OldPercent:  prev.pool.DataPercent,
+				NewPercent:  cur.pool.DataPercent,
+			})
+		}
+		for _, t := range crossedThresholds(prev.pool.MetadataPercent, cur.pool.MetadataPercent) {
+			events = append(events, &proto.WatchEvent{
+				Type:        proto.WatchEvent_THIN_POOL_METADATA_THRESHOLD_CROSSED,
+				DeviceClass: dcName,
+				Threshold:   t,
+				OldPercent:  prev.pool.MetadataPercent,
+				NewPercent:  cur.pool.MetadataPercent,
+			})
+		}
+	}
+
+	return events
+}
+
+// crossedThresholds returns the thresholds between old and new in both directions.
+func crossedThresholds(old, new float64) []float64 {
+	var ret []float64
+	for _, t := range thinPoolThresholds {
+		if (old < t) != (new < t) {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+func sortedVolumeNames(volumes map[string]*volumeState) []string {
+	names := make([]string, 0, len(volumes))
+	for name := range volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// eventHistory generates events from snapshots of device-classes and
+// keeps the latest events to resume watching.
+//
+// A revision consists of the epoch of the history in the upper 32 bits and
+// the counter of events in the lower 32 bits, so that revisions given by
+// another lvmd process can be detected.
+type eventHistory struct {
+	mu       sync.Mutex
+	epoch    uint32
+	revision uint64
+	events   []*proto.WatchEvent
+	states   map[string]*deviceClassState
+}
+
+func newEventHistory() *eventHistory {
+	epoch := uint32(time.Now().UnixNano()>>10) | 1
+	return &eventHistory{
+		epoch:    epoch,
+		revision: uint64(epoch) << 32,
+	}
+}
+
+// record generates events by comparing states with the previous ones.
+// The first state of each device-class is only recorded as the baseline.
+// The states of the device-classes not in states are kept as they are.
+func (h *eventHistory) record(states map[string]*deviceClassState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.states == nil {
+		h.states = make(map[string]*deviceClassState)
+	}
+
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prev, ok := h.states[name]; ok {
+			for _, ev := range diffDeviceClassState(name, prev, states[name]) {
+				h.revision++
+				ev.Revision = h.revision
+				h.events = append(h.events, ev)
+			}
+		}
+		h.states[name] = states[name]
+	}
+	if len(h.events) > maxEventHistory {
+		h.events = append([]*proto.WatchEvent(nil), h.events[len(h.events)-maxEventHistory:]...)
+	}
+}
+
+// since returns events of the device-classes whose revision is greater than rev,
+// and the latest revision.  If deviceClasses is empty, events of all device-classes are returned.
+// compacted is true if some events after rev are no longer kept.
+func (h *eventHistory) since(rev uint64, deviceClasses map[string]bool) (events []*proto.WatchEvent, latest uint64, compacted bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case rev == 0:
+		rev = uint64(h.epoch) << 32
+	case uint32(rev>>32) != h.epoch || rev > h.revision:
+		// rev was given by another lvmd process.
+		return nil, h.revision, true
+	}
+	if len(h.events) > 0 && h.events[0].Revision > rev+1 {
+		compacted = true
+	}
+
+	for _, ev := range h.events {
+		if ev.Revision <= rev {
+			continue
+		}
+		if len(deviceClasses) > 0 && !deviceClasses[ev.DeviceClass] {
+			continue
+		}
+		events = append(events, ev)
+	}
+	return events, h.revision, compacted
+}
@@ -0,0 +1,252 @@
+package lvmd
+
+import (
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+func testVolumeState(name, origin string, sizeGb uint64) *volumeState {
+	return &volumeState{
+		volume: &proto.LogicalVolume{
+			Name:   name,
+			SizeGb: sizeGb,
+			Origin: origin,
+		},
+		sizeBytes: sizeGb << 30,
+	}
+}
+
+func TestDiffDeviceClassState(t *testing.T) {
+	prev := &deviceClassState{
+		vgSize: 100 << 30,
+		volumes: map[string]*volumeState{
+			"keep":   testVolumeState("keep", "", 1),
+			"resize": testVolumeState("resize", "", 1),
+			"remove": testVolumeState("remove", "", 1),
+		},
+		pool: &command.ThinPoolUsage{DataPercent: 79, MetadataPercent: 96},
+	}
+	cur := &deviceClassState{
+		vgSize: 200 << 30,
+		volumes: map[string]*volumeState{
+			"keep":   testVolumeState("keep", "", 1),
+			"resize": testVolumeState("resize", "", 2),
+			"create": testVolumeState("create", "", 1),
+			"snap":   testVolumeState("snap", "keep", 1),
+		},
+		pool: &command.ThinPoolUsage{DataPercent: 91, MetadataPercent: 94},
+	}
+
+	events := diffDeviceClassState("ssd", prev, cur)
+	expected := []struct {
+		typ       proto.WatchEvent_Type
+		volume    string
+		threshold float64
+	}{
+		{proto.WatchEvent_VG_SIZE_CHANGED, "", 0},
+		{proto.WatchEvent_LV_CREATED, "create", 0},
+		{proto.WatchEvent_LV_RESIZED, "resize", 0},
+		{proto.WatchEvent_SNAPSHOT_CREATED, "snap", 0},
+		{proto.WatchEvent_LV_REMOVED, "remove", 0},
+		{proto.WatchEvent_THIN_POOL_DATA_THRESHOLD_CROSSED, "", 80},
+		{proto.WatchEvent_THIN_POOL_DATA_THRESHOLD_CROSSED, "", 90},
+		{proto.WatchEvent_THIN_POOL_METADATA_THRESHOLD_CROSSED, "", 95},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("unexpected number of events: expected=%d, actual=%v", len(expected), events)
+	}
+	for i, e := range expected {
+		ev := events[i]
+		if ev.Type != e.typ {
+			t.Errorf("%d: unexpected type: expected=%s, actual=%s", i, e.typ, ev.Type)
+		}
+		if ev.DeviceClass != "ssd" {
+			t.Errorf("%d: unexpected device-class: %s", i, ev.DeviceClass)
+		}
+		if e.volume != "" && ev.Volume.GetName() != e.volume {
+			t.Errorf("%d: unexpected volume: expected=%s, actual=%s", i, e.volume, ev.Volume.GetName())
+		}
+		if ev.Threshold != e.threshold {
+			t.Errorf("%d: unexpected threshold: expected=%f, actual=%f", i, e.threshold, ev.Threshold)
+		}
+	}
+
+	resized := events[2]
+	if resized.OldSizeBytes != 1<<30 || resized.NewSizeBytes != 2<<30 {
+		t.Errorf("unexpected sizes of LV_RESIZED: %d -> %d", resized.OldSizeBytes, resized.NewSizeBytes)
+	}
+
+	if events := diffDeviceClassState("ssd", cur, cur); len(events) != 0 {
+		t.Errorf("no events should be generated for the same states: %v", events)
+	}
+}
+
+func TestEventHistory(t *testing.T) {
+	state := func(names ...string) map[string]*deviceClassState {
+		volumes := make(map[string]*volumeState)
+		for _, name := range names {
+			volumes[name] = testVolumeState(name, "", 1)
+		}
+		return map[string]*deviceClassState{
+			"ssd": {volumes: volumes},
+			"hdd": {volumes: map[string]*volumeState{}},
+		}
+	}
+
+	h := &eventHistory{}
+	h.record(state("a"))
+	if events, rev, compacted := h.since(0, nil); len(events) != 0 || rev != 0 || compacted {
+		t.Fatalf("the first record should only take the baseline: events=%v, rev=%d, compacted=%v", events, rev, compacted)
+	}
+
+	h.record(state("a", "b"))
+	h.record(state("b"))
+	events, rev, compacted := h.since(0, nil)
+	if len(events) != 2 || rev != 2 || compacted {
+		t.Fatalf("unexpected result: events=%v, rev=%d, compacted=%v", events, rev, compacted)
+	}
+	if events[0].Revision != 1 || events[0].Type != proto.WatchEvent_LV_CREATED || events[0].Volume.Name != "b" {
+		t.Errorf("unexpected event: %v", events[0])
+	}
+	if events[1].Revision != 2 || events[1].Type != proto.WatchEvent_LV_REMOVED || events[1].Volume.Name != "a" {
+		t.Errorf("unexpected event: %v", events[1])
+	}
+
+	events, _, _ = h.since(1, nil)
+	if len(events) != 1 || events[0].Revision != 2 {
+		t.Errorf("unexpected events after revision 1: %v", events)
+	}
+
+	events, _, _ = h.since(0, map[string]bool{"hdd": true})
+	if len(events) != 0 {
+		t.Errorf("events of other device-classes should be filtered: %v", events)
+	}
+
+	if _, _, compacted := h.since(100, nil); !compacted {
+		t.Error("a revision from another process should be compacted")
+	}
+
+	for i := 0; i < maxEventHistory; i++ {
+		if i%2 == 0 {
+			h.record(state("b", "c"))
+		} else {
+			h.record(state("b"))
+		}
+	}
+	events, rev, compacted = h.since(0, nil)
+	if len(events) != maxEventHistory || rev != maxEventHistory+2 || !compacted {
+		t.Errorf("unexpected result after compaction: len(events)=%d, rev=%d, compacted=%v", len(events), rev, compacted)
+	}
+	if _, _, compacted := h.since(2, nil); compacted {
+		t.Error("events after revision 2 should be kept")
+	}
+}
+
+func TestEventHistoryEpoch(t *testing.T) {
+	state := func(names ...string) map[string]*deviceClassState {
+		volumes := make(map[string]*volumeState)
+		for _, name := range names {
+			volumes[name] = testVolumeState(name, "", 1)
+		}
+		return map[string]*deviceClassState{"ssd": {volumes: volumes}}
+	}
+
+	h := newEventHistory()
+	h.record(state("a"))
+	h.record(state("a", "b"))
+	events, rev, compacted := h.since(0, nil)
+	if len(events) != 1 || compacted {
+		t.Fatalf("unexpected result: events=%v, compacted=%v", events, compacted)
+	}
+	if uint32(rev>>32) != h.epoch || uint32(rev) != 1 || events[0].Revision != rev {
+		t.Errorf("revision should consist of the epoch and the counter: %#x", rev)
+	}
+	if events, _, compacted := h.since(rev, nil); len(events) != 0 || compacted {
+		t.Errorf("no events should be returned after the latest revision: events=%v, compacted=%v", events, compacted)
+	}
+
+	// a restarted lvmd has another epoch.
+	restarted := newEventHistory()
+	restarted.epoch = h.epoch + 2
+	restarted.revision = uint64(restarted.epoch) << 32
+	restarted.record(state("c"))
+	restarted.record(state("c", "d"))
+	restarted.record(state("c", "d", "e"))
+	events, latest, compacted := restarted.since(rev, nil)
+	if len(events) != 0 || !compacted || latest != restarted.revision {
+		t.Errorf("a revision of another epoch should be compacted: events=%v, latest=%#x, compacted=%v", events, latest, compacted)
+	}
+}
+
+func TestEventHistoryPartialRecord(t *testing.T) {
+	h := &eventHistory{}
+	h.record(map[string]*deviceClassState{
+		"ssd": {volumes: map[string]*volumeState{"a": testVolumeState("a", "", 1)}},
+		"hdd": {volumes: map[string]*volumeState{"b": testVolumeState("b", "", 1)}},
+	})
+
+	// the state of hdd could not be collected.
+	h.record(map[string]*deviceClassState{
+		"ssd": {volumes: map[string]*volumeState{"a": testVolumeState("a", "", 2)}},
+	})
+	events, _, _ := h.since(0, nil)
+	if len(events) != 1 || events[0].DeviceClass != "ssd" || events[0].Type != proto.WatchEvent_LV_RESIZED {
+		t.Fatalf("only events of ssd should be generated: %v", events)
+	}
+
+	h.record(map[string]*deviceClassState{
+		"ssd": {volumes: map[string]*volumeState{"a": testVolumeState("a", "", 2)}},
+		"hdd": {volumes: map[string]*volumeState{}},
+	})
+	events, _, _ = h.since(events[0].Revision, nil)
+	if len(events) != 1 || events[0].DeviceClass != "hdd" || events[0].Type != proto.WatchEvent_LV_REMOVED {
+		t.Errorf("the state of hdd should be compared with the last collected one: %v", events)
+	}
+}
+
+func TestRecordChanges(t *testing.T) {
+	server, _ := NewVGService(NewDeviceClassManager(nil))
+	s := server.(*vgService)
+	collected := 0
+	s.collect = func() (map[string]*deviceClassState, error) {
+		collected++
+		return map[string]*deviceClassState{
+			"ssd": {volumes: map[string]*volumeState{}},
+		}, nil
+	}
+	ch := make(chan struct{}, 1)
+	s.addWatcher(ch)
+
+	// volume operations only notify watchers.
+	s.notifyWatchers()
+	s.notifyWatchers()
+	if collected != 0 {
+		t.Fatalf("notifyWatchers should not collect states: %d", collected)
+	}
+	select {
+	case <-ch:
+	default:
+		t.Fatal("watcher is not notified")
+	}
+
+	// the first subscriber collects the states, and the others reuse them.
+	s.recordChanges()
+	s.recordChanges()
+	if collected != 1 {
+		t.Errorf("states should be collected once for notified changes: %d", collected)
+	}
+
+	s.notifyWatchers()
+	s.recordChanges()
+	if collected != 2 {
+		t.Errorf("states should be collected for a new change: %d", collected)
+	}
+
+	// new subscribers always collect states to catch up changes made outside of lvmd.
+	s.recordEvents()
+	if collected != 3 {
+		t.Errorf("recordEvents should always collect states: %d", collected)
+	}
+}
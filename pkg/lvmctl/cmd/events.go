@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newEventsCmd(opts *options) *cobra.Command {
+	var flags struct {
+		deviceClasses []string
+		startRevision uint64
+	}
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Print the WatchEvents stream of lvmd until interrupted",
+		Long: `Print the WatchEvents stream of lvmd until interrupted.
+
+Pass the last printed revision to "--start-revision" to resume watching.
+In JSON output, each response is printed in a single line.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			wc, err := c.vg.WatchEvents(cmd.Context(), &proto.WatchEventsRequest{
+				DeviceClasses: flags.deviceClasses,
+				StartRevision: flags.startRevision,
+			})
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			for {
+				res, err := wc.Recv()
+				switch {
+				case err == io.EOF:
+					return nil
+				case status.Code(err) == codes.Canceled:
+					return nil
+				case err != nil:
+					return err
+				}
+
+				if opts.output == outputJSON {
+					if err := printJSON(w, res, false); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := opts.printEvents(w, res); err != nil {
+					return err
+				}
+			}
+		},
+	}
+	cmd.Flags().StringSliceVar(&flags.deviceClasses, "device-class", nil, "device-classes to subscribe (all if not given)")
+	cmd.Flags().Uint64Var(&flags.startRevision, "start-revision", 0, "print events after this revision")
+	return cmd
+}
+
+func (o *options) printEvents(w io.Writer, res *proto.WatchEventsResponse) error {
+	fmt.Fprintf(w, "# revision=%d compacted=%v\n", res.Revision, res.Compacted)
+	rows := make([][]string, 0, len(res.Events))
+	for _, ev := range res.Events {
+		var detail string
+		switch ev.Type {
+		case proto.WatchEvent_LV_CREATED, proto.WatchEvent_LV_REMOVED, proto.WatchEvent_SNAPSHOT_CREATED:
+			detail = ev.Volume.GetName()
+		case proto.WatchEvent_LV_RESIZED:
+			detail = fmt.Sprintf("%s %s -> %s", ev.Volume.GetName(), formatBytes(ev.OldSizeBytes), formatBytes(ev.NewSizeBytes))
+		case proto.WatchEvent_VG_SIZE_CHANGED:
+			detail = fmt.Sprintf("%s -> %s", formatBytes(ev.OldSizeBytes), formatBytes(ev.NewSizeBytes))
+		case proto.WatchEvent_THIN_POOL_DATA_THRESHOLD_CROSSED, proto.WatchEvent_THIN_POOL_METADATA_THRESHOLD_CROSSED:
+			detail = fmt.Sprintf("%.2f%% -> %.2f%% (threshold %.0f%%)", ev.OldPercent, ev.NewPercent, ev.Threshold)
+		}
+		rows = append(rows, []string{fmt.Sprint(ev.Revision), ev.DeviceClass, ev.Type.String(), detail})
+	}
+	if len(rows) > 0 {
+		if err := printTable(w, []string{"REVISION", "DEVICE_CLASS", "TYPE", "DETAIL"}, rows); err != nil {
+			return err
+		}
+	}
+	return o.printWatchResponse(w, res.Capacity, false)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+func newDeviceClassesCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "device-classes",
+		Aliases: []string{"dc"},
+		Short:   "List device-classes with their free bytes and thin pool usage",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			// lvmd has no API to list device-classes, but the first response of
+			// Watch contains all of them.
+			wc, err := c.vg.Watch(ctx, &proto.Empty{})
+			if err != nil {
+				return err
+			}
+			res, err := wc.Recv()
+			if err != nil {
+				return err
+			}
+			return opts.printWatchResponse(cmd.OutOrStdout(), res, true)
+		},
+	}
+}
+
+func newLVsCmd(opts *options) *cobra.Command {
+	var deviceClass string
+
+	cmd := &cobra.Command{
+		Use:   "lvs",
+		Short: "List logical volumes in a device-class",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			res, err := c.vg.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass})
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if opts.output == outputJSON {
+				return printJSON(w, res, true)
+			}
+			rows := make([][]string, 0, len(res.Volumes))
+			for _, v := range res.Volumes {
+				dataPercent := "-"
+				if v.Pool != "" {
+					dataPercent = fmt.Sprintf("%.2f", v.DataPercent)
+				}
+				rows = append(rows, []string{
+					v.Name,
+					formatBytes(v.SizeBytes),
+					orNone(v.Attributes.GetAttr()),
+					orNone(v.Attributes.GetHealth()),
+					orNone(v.Origin),
+					orNone(v.Pool),
+					dataPercent,
+					fmt.Sprintf("%d:%d", v.DevMajor, v.DevMinor),
+					orNone(strings.Join(v.Tags, ",")),
+				})
+			}
+			return printTable(w, []string{"NAME", "SIZE", "ATTR", "HEALTH", "ORIGIN", "POOL", "DATA%", "DEVICE", "TAGS"}, rows)
+		},
+	}
+	cmd.Flags().StringVar(&deviceClass, "device-class", "", "device-class name (default device-class if empty)")
+	return cmd
+}
+
+func newFreeCmd(opts *options) *cobra.Command {
+	var deviceClass string
+
+	cmd := &cobra.Command{
+		Use:   "free",
+		Short: "Show free bytes of a device-class",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			res, err := c.vg.GetFreeBytes(ctx, &proto.GetFreeBytesRequest{DeviceClass: deviceClass})
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if opts.output == outputJSON {
+				return printJSON(w, res, true)
+			}
+			return printTable(w, []string{"FREE_BYTES", "FREE"}, [][]string{
+				{strconv.FormatUint(res.FreeBytes, 10), formatBytes(res.FreeBytes)},
+			})
+		},
+	}
+	cmd.Flags().StringVar(&deviceClass, "device-class", "", "device-class name (default device-class if empty)")
+	return cmd
+}
+
+func (o *options) printWatchResponse(w io.Writer, res *proto.WatchResponse, multiline bool) error {
+	if o.output == outputJSON {
+		return printJSON(w, res, multiline)
+	}
+	rows := make([][]string, 0, len(res.Items))
+	for _, item := range res.Items {
+		row := []string{item.DeviceClass, "thick", formatBytes(item.FreeBytes), formatBytes(item.SizeBytes), "-", "-", "-", "-"}
+		if tp := item.ThinPool; tp != nil {
+			row[1] = "thin"
+			row[4] = formatBytes(tp.SizeBytes)
+			row[5] = strconv.FormatFloat(tp.DataPercent, 'f', 2, 64)
+			row[6] = strconv.FormatFloat(tp.MetadataPercent, 'f', 2, 64)
+			row[7] = formatBytes(tp.OverprovisionBytes)
+		}
+		rows = append(rows, row)
+	}
+	return printTable(w, []string{"DEVICE_CLASS", "TYPE", "VG_FREE", "VG_SIZE", "POOL_SIZE", "DATA%", "METADATA%", "POOL_FREE"}, rows)
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+var errNotConfirmed = errors.New(`refusing to modify logical volumes without "--yes"`)
+
+// lvFlags holds the flags of a sub-command that modifies logical volumes.
+// Each sub-command has its own instance so that flag values do not leak between sub-commands.
+type lvFlags struct {
+	deviceClass string
+	yes         bool
+}
+
+func (f *lvFlags) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.deviceClass, "device-class", "", "device-class name (default device-class if empty)")
+	cmd.Flags().BoolVar(&f.yes, "yes", false, "actually perform the operation")
+}
+
+// confirm reports the operation and returns errNotConfirmed unless "--yes" is given.
+func (f *lvFlags) confirm(cmd *cobra.Command, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if !f.yes {
+		fmt.Fprintln(cmd.ErrOrStderr(), "would "+msg)
+		return errNotConfirmed
+	}
+	fmt.Fprintln(cmd.ErrOrStderr(), msg)
+	return nil
+}
+
+// findLV returns the named logical volume in the device-class, or nil if it does not exist.
+func findLV(ctx context.Context, c *client, deviceClass, name string) (*proto.LogicalVolume, error) {
+	res, err := c.vg.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: deviceClass})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range res.Volumes {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func newCreateCmd(opts *options) *cobra.Command {
+	var flags struct {
+		lvFlags
+		sizeGb              uint64
+		tags                []string
+		lvcreateOptionClass string
+	}
+
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a logical volume",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			name := args[0]
+			if flags.sizeGb == 0 {
+				return errors.New("--size-gb should be greater than 0")
+			}
+
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			lv, err := findLV(ctx, c, flags.deviceClass, name)
+			if err != nil {
+				return err
+			}
+			if lv != nil {
+				return fmt.Errorf("logical volume already exists: %s", name)
+			}
+
+			if err := flags.confirm(cmd, "create %s (%d GiB) in device-class %q", name, flags.sizeGb, flags.deviceClass); err != nil {
+				return err
+			}
+			res, err := c.lv.CreateLV(ctx, &proto.CreateLVRequest{
+				Name:                name,
+				SizeGb:              flags.sizeGb,
+				Tags:                flags.tags,
+				DeviceClass:         flags.deviceClass,
+				LvcreateOptionClass: flags.lvcreateOptionClass,
+			})
+			if err != nil {
+				return err
+			}
+			return opts.printLV(cmd.OutOrStdout(), res.Volume)
+		},
+	}
+	flags.addFlags(cmd)
+	cmd.Flags().Uint64Var(&flags.sizeGb, "size-gb", 0, "volume size in GiB")
+	cmd.Flags().StringSliceVar(&flags.tags, "tag", nil, "tags to add to the volume")
+	cmd.Flags().StringVar(&flags.lvcreateOptionClass, "lvcreate-option-class", "", "lvcreate-option-class name")
+	return cmd
+}
+
+func newResizeCmd(opts *options) *cobra.Command {
+	var flags struct {
+		lvFlags
+		sizeGb uint64
+	}
+
+	cmd := &cobra.Command{
+		Use:   "resize NAME",
+		Short: "Extend a logical volume",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			name := args[0]
+
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			lv, err := findLV(ctx, c, flags.deviceClass, name)
+			if err != nil {
+				return err
+			}
+			if lv == nil {
+				return fmt.Errorf("logical volume is not found: %s", name)
+			}
+			if flags.sizeGb <= lv.SizeGb {
+				return fmt.Errorf("--size-gb should be greater than the current size: current=%d, requested=%d", lv.SizeGb, flags.sizeGb)
+			}
+
+			if err := flags.confirm(cmd, "resize %s from %d GiB to %d GiB", name, lv.SizeGb, flags.sizeGb); err != nil {
+				return err
+			}
+			_, err = c.lv.ResizeLV(ctx, &proto.ResizeLVRequest{
+				Name:        name,
+				SizeGb:      flags.sizeGb,
+				DeviceClass: flags.deviceClass,
+			})
+			return err
+		},
+	}
+	flags.addFlags(cmd)
+	cmd.Flags().Uint64Var(&flags.sizeGb, "size-gb", 0, "new volume size in GiB")
+	return cmd
+}
+
+func newRemoveCmd(opts *options) *cobra.Command {
+	var flags lvFlags
+
+	cmd := &cobra.Command{
+		Use:   "remove NAME",
+		Short: "Remove a logical volume",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			name := args[0]
+
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			// RemoveLV succeeds even if the volume does not exist,
+			// so check it here to detect typos.
+			lv, err := findLV(ctx, c, flags.deviceClass, name)
+			if err != nil {
+				return err
+			}
+			if lv == nil {
+				return fmt.Errorf("logical volume is not found: %s", name)
+			}
+
+			if err := flags.confirm(cmd, "remove %s (%d GiB)", name, lv.SizeGb); err != nil {
+				return err
+			}
+			_, err = c.lv.RemoveLV(ctx, &proto.RemoveLVRequest{
+				Name:        name,
+				DeviceClass: flags.deviceClass,
+			})
+			return err
+		},
+	}
+	flags.addFlags(cmd)
+	return cmd
+}
+
+func newSnapshotCmd(opts *options) *cobra.Command {
+	var flags struct {
+		lvFlags
+		source     string
+		accessType string
+		tags       []string
+	}
+
+	cmd := &cobra.Command{
+		Use:   "snapshot NAME",
+		Short: "Create a thin snapshot of a logical volume",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			name := args[0]
+			if flags.source == "" {
+				return errors.New("--source is required")
+			}
+			if flags.accessType != "ro" && flags.accessType != "rw" {
+				return fmt.Errorf("--access-type should be ro or rw: %s", flags.accessType)
+			}
+
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			source, err := findLV(ctx, c, flags.deviceClass, flags.source)
+			if err != nil {
+				return err
+			}
+			if source == nil {
+				return fmt.Errorf("source logical volume is not found: %s", flags.source)
+			}
+			if source.Pool == "" {
+				return fmt.Errorf("source logical volume is not a thin volume: %s", flags.source)
+			}
+			lv, err := findLV(ctx, c, flags.deviceClass, name)
+			if err != nil {
+				return err
+			}
+			if lv != nil {
+				return fmt.Errorf("logical volume already exists: %s", name)
+			}
+
+			if err := flags.confirm(cmd, "create %s snapshot %s of %s", flags.accessType, name, flags.source); err != nil {
+				return err
+			}
+			res, err := c.lv.CreateLVSnapshot(ctx, &proto.CreateLVSnapshotRequest{
+				Name:         name,
+				Tags:         flags.tags,
+				DeviceClass:  flags.deviceClass,
+				SourceVolume: flags.source,
+				AccessType:   flags.accessType,
+			})
+			if err != nil {
+				return err
+			}
+			return opts.printLV(cmd.OutOrStdout(), res.Snapshot)
+		},
+	}
+	flags.addFlags(cmd)
+	cmd.Flags().StringVar(&flags.source, "source", "", "source logical volume name")
+	cmd.Flags().StringVar(&flags.accessType, "access-type", "ro", "access type of the snapshot: ro or rw")
+	cmd.Flags().StringSliceVar(&flags.tags, "tag", nil, "tags to add to the snapshot")
+	return cmd
+}
+
+func (o *options) printLV(w io.Writer, lv *proto.LogicalVolume) error {
+	if o.output == outputJSON {
+		return printJSON(w, lv, true)
+	}
+	return printTable(w, []string{"NAME", "SIZE_GB", "DEVICE"}, [][]string{
+		{lv.Name, fmt.Sprint(lv.SizeGb), fmt.Sprintf("%d:%d", lv.DevMajor, lv.DevMinor)},
+	})
+}
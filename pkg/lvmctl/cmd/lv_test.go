@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/proto"
+)
+
+func testVolumes() []*proto.LogicalVolume {
+	return []*proto.LogicalVolume{
+		{Name: "thick", SizeGb: 2},
+		{Name: "thin", SizeGb: 1, Pool: "pool"},
+	}
+}
+
+func TestLVArgumentValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "invalid output",
+			args: []string{"lvs", "-o", "yaml"},
+			err:  "output should be",
+		},
+		{
+			name: "create without name",
+			args: []string{"create", "--size-gb", "1", "--yes"},
+			err:  "accepts 1 arg(s)",
+		},
+		{
+			name: "create without size",
+			args: []string{"create", "new", "--yes"},
+			err:  "--size-gb should be greater than 0",
+		},
+		{
+			name: "create existing volume",
+			args: []string{"create", "thick", "--size-gb", "1", "--yes"},
+			err:  "logical volume already exists",
+		},
+		{
+			name: "resize missing volume",
+			args: []string{"resize", "missing", "--size-gb", "3", "--yes"},
+			err:  "logical volume is not found",
+		},
+		{
+			name: "resize to the same size",
+			args: []string{"resize", "thick", "--size-gb", "2", "--yes"},
+			err:  "--size-gb should be greater than the current size",
+		},
+		{
+			name: "remove missing volume",
+			args: []string{"remove", "missing", "--yes"},
+			err:  "logical volume is not found",
+		},
+		{
+			name: "snapshot without source",
+			args: []string{"snapshot", "snap", "--yes"},
+			err:  "--source is required",
+		},
+		{
+			name: "snapshot with invalid access type",
+			args: []string{"snapshot", "snap", "--source", "thin", "--access-type", "wo", "--yes"},
+			err:  "--access-type should be ro or rw",
+		},
+		{
+			name: "snapshot of thick volume",
+			args: []string{"snapshot", "snap", "--source", "thick", "--yes"},
+			err:  "source logical volume is not a thin volume",
+		},
+		{
+			name: "snapshot with existing name",
+			args: []string{"snapshot", "thick", "--source", "thin", "--yes"},
+			err:  "logical volume already exists",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := startFakeLVMd(t, testVolumes()...)
+			_, _, err := f.run(tc.args...)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, actual %v", tc.err, err)
+			}
+			if n := f.mutations(); n != 0 {
+				t.Errorf("expected no mutating calls, actual %d", n)
+			}
+		})
+	}
+}
+
+func TestLVConfirmation(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		msg  string
+	}{
+		{
+			name: "create",
+			args: []string{"create", "new", "--size-gb", "1"},
+			msg:  `create new (1 GiB) in device-class ""`,
+		},
+		{
+			name: "resize",
+			args: []string{"resize", "thick", "--size-gb", "3"},
+			msg:  "resize thick from 2 GiB to 3 GiB",
+		},
+		{
+			name: "remove",
+			args: []string{"remove", "thick"},
+			msg:  "remove thick (2 GiB)",
+		},
+		{
+			name: "snapshot",
+			args: []string{"snapshot", "snap", "--source", "thin"},
+			msg:  "create ro snapshot snap of thin",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := startFakeLVMd(t, testVolumes()...)
+			_, stderr, err := f.run(tc.args...)
+			if !errors.Is(err, errNotConfirmed) {
+				t.Fatalf("expected errNotConfirmed, actual %v", err)
+			}
+			if !strings.Contains(stderr, "would "+tc.msg) {
+				t.Errorf("expected %q in stderr, actual %q", "would "+tc.msg, stderr)
+			}
+			if n := f.mutations(); n != 0 {
+				t.Fatalf("expected no mutating calls without --yes, actual %d", n)
+			}
+
+			_, stderr, err = f.run(append(tc.args, "--yes")...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Contains(stderr, "would ") || !strings.Contains(stderr, tc.msg) {
+				t.Errorf("expected %q in stderr, actual %q", tc.msg, stderr)
+			}
+			if n := f.mutations(); n != 1 {
+				t.Errorf("expected 1 mutating call with --yes, actual %d", n)
+			}
+		})
+	}
+}
+
+func TestLVFlagsPerCommand(t *testing.T) {
+	f := startFakeLVMd(t, testVolumes()...)
+	root := newRootCmd()
+
+	_, _, err := f.runWith(root, "create", "new", "--size-gb", "5", "--tag", "a", "--device-class", "ssd", "--yes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = f.runWith(root, "snapshot", "snap", "--source", "thin", "--yes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// resize must not see --size-gb given to create.
+	_, _, err = f.runWith(root, "resize", "thick", "--yes")
+	if err == nil || !strings.Contains(err.Error(), "--size-gb should be greater than the current size") {
+		t.Errorf("expected error about --size-gb, actual %v", err)
+	}
+
+	if len(f.snapshots) != 1 {
+		t.Fatalf("expected 1 snapshot, actual %d", len(f.snapshots))
+	}
+	snap := f.snapshots[0]
+	if len(snap.Tags) != 0 || snap.DeviceClass != "" {
+		t.Errorf("flags of create leaked to snapshot: tags=%v device-class=%q", snap.Tags, snap.DeviceClass)
+	}
+	if len(f.resized) != 0 {
+		t.Errorf("expected no resize, actual %v", f.resized)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestFormatBytes(t *testing.T) {
+	testCases := []struct {
+		bytes    uint64
+		expected string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1.0KiB"},
+		{1536, "1.5KiB"},
+		{5 << 30, "5.0GiB"},
+		{3 << 60, "3.0EiB"},
+	}
+
+	for _, tc := range testCases {
+		if actual := formatBytes(tc.bytes); actual != tc.expected {
+			t.Errorf("formatBytes(%d): expected %q, actual %q", tc.bytes, tc.expected, actual)
+		}
+	}
+}
+
+func TestOutput(t *testing.T) {
+	volumes := []*proto.LogicalVolume{
+		{
+			Name:       "thick",
+			SizeBytes:  2 << 30,
+			DevMajor:   253,
+			DevMinor:   1,
+			Attributes: &proto.LVAttributes{Attr: "-wi-a-----", Health: "ok"},
+		},
+		{
+			Name:        "snap",
+			SizeBytes:   1 << 30,
+			DevMajor:    253,
+			DevMinor:    2,
+			Origin:      "thin",
+			Pool:        "pool",
+			DataPercent: 12.5,
+			Tags:        []string{"a", "b"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "lvs",
+			args: []string{"lvs"},
+			expected: "" +
+				"NAME   SIZE    ATTR        HEALTH  ORIGIN  POOL  DATA%  DEVICE  TAGS\n" +
+				"thick  2.0GiB  -wi-a-----  ok      -       -     -      253:1   -\n" +
+				"snap   1.0GiB  -           -       thin    pool  12.50  253:2   a,b\n",
+		},
+		{
+			name: "free",
+			args: []string{"free"},
+			expected: "" +
+				"FREE_BYTES  FREE\n" +
+				"5368709120  5.0GiB\n",
+		},
+		{
+			name: "device-classes",
+			args: []string{"device-classes"},
+			expected: "" +
+				"DEVICE_CLASS  TYPE   VG_FREE  VG_SIZE  POOL_SIZE  DATA%  METADATA%  POOL_FREE\n" +
+				"hdd           thick  1.0GiB   4.0GiB   -          -      -          -\n" +
+				"ssd           thin   0B       2.0GiB   2.0GiB     50.00  10.00      3.0GiB\n",
+		},
+		{
+			name: "create",
+			args: []string{"create", "new", "--size-gb", "3", "--yes"},
+			expected: "" +
+				"NAME  SIZE_GB  DEVICE\n" +
+				"new   3        0:0\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := startFakeLVMd(t, volumes...)
+			f.free = 5 << 30
+			f.watch = &proto.WatchResponse{Items: []*proto.WatchItem{
+				{DeviceClass: "hdd", FreeBytes: 1 << 30, SizeBytes: 4 << 30},
+				{DeviceClass: "ssd", SizeBytes: 2 << 30, ThinPool: &proto.ThinPoolItem{
+					SizeBytes:          2 << 30,
+					DataPercent:        50,
+					MetadataPercent:    10,
+					OverprovisionBytes: 3 << 30,
+				}},
+			}}
+
+			stdout, _, err := f.run(tc.args...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stdout != tc.expected {
+				t.Errorf("expected:\n%s\nactual:\n%s", tc.expected, stdout)
+			}
+		})
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	f := startFakeLVMd(t, testVolumes()...)
+	f.free = 5 << 30
+
+	stdout, _, err := f.run("lvs", "-o", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var list proto.GetLVListResponse
+	if err := protojson.Unmarshal([]byte(stdout), &list); err != nil {
+		t.Fatalf("failed to parse %q: %v", stdout, err)
+	}
+	if len(list.Volumes) != 2 || list.Volumes[0].Name != "thick" || list.Volumes[1].Pool != "pool" {
+		t.Errorf("unexpected volumes: %v", list.Volumes)
+	}
+
+	stdout, _, err = f.run("free", "--output", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var free proto.GetFreeBytesResponse
+	if err := protojson.Unmarshal([]byte(stdout), &free); err != nil {
+		t.Fatalf("failed to parse %q: %v", stdout, err)
+	}
+	if free.FreeBytes != 5<<30 {
+		t.Errorf("expected %d free bytes, actual %d", uint64(5<<30), free.FreeBytes)
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// options holds the global flags shared by all sub-commands.
+type options struct {
+	socketName string
+	output     string
+	timeout    time.Duration
+}
+
+// newRootCmd returns the base command when called without any subcommands.
+// Every call returns a new command tree so that flag values are not shared.
+func newRootCmd() *cobra.Command {
+	opts := &options{}
+	cmd := &cobra.Command{
+		Use:     "lvmctl",
+		Version: topolvm.Version,
+		Short:   "a client to operate lvmd",
+		Long: `A client to operate lvmd.
+
+lvmctl connects to the UNIX domain socket of lvmd and calls its gRPC API.
+It is intended to be used for debugging and maintenance on a node.
+
+Sub-commands that modify logical volumes refuse to run unless "--yes" is
+given, because lvmd does not know whether the volume is used by Kubernetes.
+`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			switch opts.output {
+			case outputTable, outputJSON:
+				return nil
+			}
+			return fmt.Errorf("output should be %q or %q: %s", outputTable, outputJSON, opts.output)
+		},
+	}
+
+	fs := cmd.PersistentFlags()
+	fs.StringVar(&opts.socketName, "socket", topolvm.DefaultLVMdSocket, "UNIX domain socket of lvmd service")
+	fs.StringVarP(&opts.output, "output", "o", outputTable, "output format: table or json")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout for each gRPC call")
+
+	cmd.AddCommand(
+		newDeviceClassesCmd(opts),
+		newLVsCmd(opts),
+		newFreeCmd(opts),
+		newCreateCmd(opts),
+		newResizeCmd(opts),
+		newRemoveCmd(opts),
+		newSnapshotCmd(opts),
+		newWatchCmd(opts),
+		newEventsCmd(opts),
+	)
+	return cmd
+}
+
+// client holds gRPC clients for lvmd services.
+type client struct {
+	conn *grpc.ClientConn
+	vg   proto.VGServiceClient
+	lv   proto.LVServiceClient
+}
+
+func (c *client) Close() error {
+	return c.conn.Close()
+}
+
+func (o *options) dial() (*client, error) {
+	dialer := &net.Dialer{}
+	dialFunc := func(ctx context.Context, a string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", a)
+	}
+	conn, err := grpc.Dial(o.socketName, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(dialFunc))
+	if err != nil {
+		return nil, err
+	}
+	return &client{
+		conn: conn,
+		vg:   proto.NewVGServiceClient(conn),
+		lv:   proto.NewLVServiceClient(conn),
+	}, nil
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main().
+func Execute() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc"
+)
+
+// fakeLVMd serves VGService and LVService on a UNIX domain socket and records mutating requests.
+type fakeLVMd struct {
+	proto.UnimplementedVGServiceServer
+	proto.UnimplementedLVServiceServer
+
+	socket  string
+	volumes []*proto.LogicalVolume
+	free    uint64
+	watch   *proto.WatchResponse
+
+	mu        sync.Mutex
+	created   []*proto.CreateLVRequest
+	resized   []*proto.ResizeLVRequest
+	removed   []*proto.RemoveLVRequest
+	snapshots []*proto.CreateLVSnapshotRequest
+}
+
+func startFakeLVMd(t *testing.T, volumes ...*proto.LogicalVolume) *fakeLVMd {
+	t.Helper()
+	f := &fakeLVMd{
+		socket:  filepath.Join(t.TempDir(), "lvmd.sock"),
+		volumes: volumes,
+	}
+	lis, err := net.Listen("unix", f.socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	proto.RegisterVGServiceServer(server, f)
+	proto.RegisterLVServiceServer(server, f)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return f
+}
+
+func (f *fakeLVMd) GetLVList(context.Context, *proto.GetLVListRequest) (*proto.GetLVListResponse, error) {
+	return &proto.GetLVListResponse{Volumes: f.volumes}, nil
+}
+
+func (f *fakeLVMd) GetFreeBytes(context.Context, *proto.GetFreeBytesRequest) (*proto.GetFreeBytesResponse, error) {
+	return &proto.GetFreeBytesResponse{FreeBytes: f.free}, nil
+}
+
+func (f *fakeLVMd) Watch(_ *proto.Empty, server proto.VGService_WatchServer) error {
+	if err := server.Send(f.watch); err != nil {
+		return err
+	}
+	<-server.Context().Done()
+	return nil
+}
+
+func (f *fakeLVMd) CreateLV(_ context.Context, req *proto.CreateLVRequest) (*proto.CreateLVResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created = append(f.created, req)
+	return &proto.CreateLVResponse{Volume: &proto.LogicalVolume{Name: req.Name, SizeGb: req.SizeGb}}, nil
+}
+
+func (f *fakeLVMd) ResizeLV(_ context.Context, req *proto.ResizeLVRequest) (*proto.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resized = append(f.resized, req)
+	return &proto.Empty{}, nil
+}
+
+func (f *fakeLVMd) RemoveLV(_ context.Context, req *proto.RemoveLVRequest) (*proto.Empty, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, req)
+	return &proto.Empty{}, nil
+}
+
+func (f *fakeLVMd) CreateLVSnapshot(_ context.Context, req *proto.CreateLVSnapshotRequest) (*proto.CreateLVSnapshotResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.snapshots = append(f.snapshots, req)
+	return &proto.CreateLVSnapshotResponse{Snapshot: &proto.LogicalVolume{Name: req.Name}}, nil
+}
+
+func (f *fakeLVMd) mutations() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.created) + len(f.resized) + len(f.removed) + len(f.snapshots)
+}
+
+// run executes lvmctl with args against the fake lvmd and returns stdout and stderr.
+func (f *fakeLVMd) run(args ...string) (string, string, error) {
+	return f.runWith(newRootCmd(), args...)
+}
+
+// runWith is like run but executes the given command tree so that it can be executed repeatedly.
+func (f *fakeLVMd) runWith(root *cobra.Command, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	root.SetArgs(append([]string{"--socket", f.socket}, args...))
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	err := root.ExecuteContext(context.Background())
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newWatchCmd(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "watch",
+		Short: "Print the Watch stream of lvmd until interrupted",
+		Long: `Print the Watch stream of lvmd until interrupted.
+
+In JSON output, each response is printed in a single line.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			c, err := opts.dial()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			wc, err := c.vg.Watch(cmd.Context(), &proto.Empty{})
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			for {
+				res, err := wc.Recv()
+				switch {
+				case err == io.EOF:
+					return nil
+				case status.Code(err) == codes.Canceled:
+					return nil
+				case err != nil:
+					return err
+				}
+
+				if opts.output == outputTable {
+					fmt.Fprintf(w, "# %s\n", time.Now().Format(time.RFC3339))
+				}
+				if err := opts.printWatchResponse(w, res, false); err != nil {
+					return err
+				}
+			}
+		},
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
@@ -55,6 +56,14 @@ func subMain() error {
 	if err != nil {
 		return err
 	}
+	// Invalid lvcreate-option-classes were accepted by older versions, so they are reported
+	// only as a warning here.  Run "lvmd validate" to check the configuration strictly.
+	err = lvmd.ValidateLvcreateOptionClasses(config.LvcreateOptionClasses)
+	if err != nil {
+		log.Warn("invalid lvcreate-option-classes", map[string]interface{}{
+			log.FnError: err,
+		})
+	}
 
 	vgs, err := command.ListVolumeGroups()
 	if err != nil {
@@ -82,6 +91,9 @@ func subMain() error {
 		}
 	}
 
+	// The volumes are left suspended if lvmd was killed while creating snapshots.
+	lvmd.ResumeSuspendedVolumes(config.DeviceClasses)
+
 	// UNIX domain socket file should be removed before listening.
 	err = os.Remove(config.SocketName)
 	if err != nil && !os.IsNotExist(err) {
@@ -130,6 +142,10 @@ func subMain() error {
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/topolvm/topolvm/lvmd"
+	"github.com/topolvm/topolvm/lvmd/command"
+	"sigs.k8s.io/yaml"
+)
+
+// Exit codes of "lvmd validate".
+// Other errors, e.g. the config file cannot be read, exit with 1.
+const (
+	exitCodeInvalidConfig   = 2
+	exitCodePreflightFailed = 3
+)
+
+// exitError is returned from a sub-command to exit with the specific code.
+// The error message is not printed because the sub-command reports it by itself.
+type exitError struct {
+	code int
+	msg  string
+}
+
+func (e *exitError) Error() string {
+	return e.msg
+}
+
+var validateFlags struct {
+	preflight bool
+	output    string
+}
+
+type validateReport struct {
+	Config        string               `json:"config"`
+	Valid         bool                 `json:"valid"`
+	Errors        []string             `json:"errors"`
+	Warnings      []string             `json:"warnings"`
+	DeviceClasses []*deviceClassReport `json:"device-classes,omitempty"`
+}
+
+type deviceClassReport struct {
+	Name        string `json:"name"`
+	VolumeGroup string `json:"volume-group"`
+	ThinPool    string `json:"thin-pool,omitempty"`
+	Found       bool   `json:"found"`
+	PVCount     uint64 `json:"pv-count"`
+	Stripe      uint   `json:"stripe,omitempty"`
+	FreeBytes   uint64 `json:"free-bytes"`
+	SpareBytes  uint64 `json:"spare-bytes"`
+}
+
+func (r *validateReport) errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *validateReport) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the config file without starting lvmd",
+	Long: `Validate the config file without starting lvmd.
+
+With "--preflight", this also checks that the volume groups and thin pools
+exist on this node, reports their free space, and checks stripe settings
+against the number of physical volumes.
+
+Exit codes:
+  0  the config file is valid (and preflight checks passed)
+  1  the config file cannot be read or parsed
+  2  the config file is invalid
+  3  preflight checks failed`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		switch validateFlags.output {
+		case "text", "json":
+		default:
+			return fmt.Errorf("--output should be text or json: %s", validateFlags.output)
+		}
+		cmd.SilenceErrors = true
+		return validateSubMain(cmd.OutOrStdout())
+	},
+}
+
+func validateSubMain(w io.Writer) error {
+	report := &validateReport{
+		Config:   cfgFilePath,
+		Errors:   []string{},
+		Warnings: []string{},
+	}
+
+	b, err := os.ReadFile(cfgFilePath)
+	if err != nil {
+		return err
+	}
+	cfg := &Config{}
+	err = yaml.Unmarshal(b, cfg)
+	if err != nil {
+		return err
+	}
+	err = yaml.UnmarshalStrict(b, &Config{})
+	if err != nil {
+		report.warnf("%v", err)
+	}
+
+	code := 0
+	if err := lvmd.ValidateDeviceClasses(cfg.DeviceClasses); err != nil {
+		report.errorf("%v", err)
+	}
+	if err := lvmd.ValidateLvcreateOptionClasses(cfg.LvcreateOptionClasses); err != nil {
+		report.errorf("%v", err)
+	}
+	for _, dc := range cfg.DeviceClasses {
+		stripe, ok := stripesInOptions(dc.LVCreateOptions)
+		if ok && dc.Stripe != nil {
+			report.warnf("device-class %s: both stripe and --stripes in lvcreate-options are set, which leads to duplicate arguments to lvcreate", dc.Name)
+		}
+		if ok && stripe == 0 {
+			report.errorf("device-class %s: invalid stripes in lvcreate-options: %v", dc.Name, dc.LVCreateOptions)
+		}
+	}
+	if len(report.Errors) > 0 {
+		code = exitCodeInvalidConfig
+	}
+
+	if code == 0 && validateFlags.preflight {
+		preflight(report, cfg.DeviceClasses)
+		if len(report.Errors) > 0 {
+			code = exitCodePreflightFailed
+		}
+	}
+	report.Valid = code == 0
+
+	if err := printReport(w, report); err != nil {
+		return err
+	}
+	if code != 0 {
+		return &exitError{code: code, msg: strings.Join(report.Errors, "\n")}
+	}
+	return nil
+}
+
+// volumeGroupInfo is the part of a volume group inspected by the preflight checks.
+type volumeGroupInfo interface {
+	PVCount() uint64
+	Free() (uint64, error)
+	ThinPoolUsage(name string) (*command.ThinPoolUsage, error)
+}
+
+type lvmVolumeGroup struct {
+	*command.VolumeGroup
+}
+
+// ThinPoolUsage returns command.ErrNotFound if the thin pool does not exist.
+func (vg lvmVolumeGroup) ThinPoolUsage(name string) (*command.ThinPoolUsage, error) {
+	pool, err := vg.FindPool(name)
+	if err != nil {
+		return nil, err
+	}
+	return pool.Free()
+}
+
+// listVolumeGroups returns the volume groups on this node by name.
+// Tests replace this to run the preflight checks without LVM.
+var listVolumeGroups = func() (map[string]volumeGroupInfo, error) {
+	vgs, err := command.ListVolumeGroups()
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]volumeGroupInfo, len(vgs))
+	for _, vg := range vgs {
+		ret[vg.Name()] = lvmVolumeGroup{vg}
+	}
+	return ret, nil
+}
+
+func preflight(report *validateReport, deviceClasses []*lvmd.DeviceClass) {
+	vgs, err := listVolumeGroups()
+	if err != nil {
+		report.errorf("failed to list volume groups: %v", err)
+		return
+	}
+
+	for _, dc := range deviceClasses {
+		r := &deviceClassReport{
+			Name:        dc.Name,
+			VolumeGroup: dc.VolumeGroup,
+			SpareBytes:  dc.GetSpare(),
+		}
+		report.DeviceClasses = append(report.DeviceClasses, r)
+
+		vg, ok := vgs[dc.VolumeGroup]
+		if !ok {
+			report.errorf("device-class %s: volume group not found: %s", dc.Name, dc.VolumeGroup)
+			continue
+		}
+		r.PVCount = vg.PVCount()
+
+		if dc.Type == lvmd.TypeThin {
+			r.ThinPool = dc.ThinPoolConfig.Name
+			tpu, err := vg.ThinPoolUsage(dc.ThinPoolConfig.Name)
+			if errors.Is(err, command.ErrNotFound) {
+				report.errorf("device-class %s: thin pool not found: %s", dc.Name, dc.ThinPoolConfig.Name)
+				continue
+			}
+			if err != nil {
+				report.errorf("device-class %s: failed to get free bytes: %v", dc.Name, err)
+				continue
+			}
+			opb := uint64(math.Floor(dc.ThinPoolConfig.OverprovisionRatio * float64(tpu.SizeBytes)))
+			if opb > tpu.VirtualBytes {
+				r.FreeBytes = opb - tpu.VirtualBytes
+			}
+		} else {
+			r.FreeBytes, err = vg.Free()
+			if err != nil {
+				report.errorf("device-class %s: failed to get free bytes: %v", dc.Name, err)
+				continue
+			}
+		}
+		r.Found = true
+
+		if r.FreeBytes <= r.SpareBytes {
+			report.warnf("device-class %s: free space %d bytes is not larger than spare-gb (%d bytes), so no volume can be created", dc.Name, r.FreeBytes, r.SpareBytes)
+		}
+
+		if dc.Stripe != nil {
+			r.Stripe = *dc.Stripe
+		} else if stripe, ok := stripesInOptions(dc.LVCreateOptions); ok {
+			r.Stripe = stripe
+		}
+		if uint64(r.Stripe) > r.PVCount {
+			report.errorf("device-class %s: stripe %d is larger than the number of physical volumes %d in %s", dc.Name, r.Stripe, r.PVCount, dc.VolumeGroup)
+		}
+	}
+}
+
+// stripesInOptions returns the number of stripes given by "--stripes" or "-i" in lvcreate options.
+// The second return value is false if no such option is given.
+// If the value cannot be parsed, it returns 0 and true.
+func stripesInOptions(options []string) (uint, bool) {
+	for i, opt := range options {
+		var value string
+		switch {
+		case opt == "--stripes" || opt == "-i":
+			if i+1 < len(options) {
+				value = options[i+1]
+			}
+		case strings.HasPrefix(opt, "--stripes="):
+			value = strings.TrimPrefix(opt, "--stripes=")
+		case strings.HasPrefix(opt, "-i"):
+			value = strings.TrimPrefix(opt, "-i")
+		default:
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return 0, true
+		}
+		return uint(n), true
+	}
+	return 0, false
+}
+
+func printReport(w io.Writer, report *validateReport) error {
+	if validateFlags.output == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	for _, r := range report.DeviceClasses {
+		if !r.Found {
+			continue
+		}
+		target := r.VolumeGroup
+		if r.ThinPool != "" {
+			target += "/" + r.ThinPool
+		}
+		fmt.Fprintf(w, "device-class %s: %s pvs=%d stripe=%d free=%d spare=%d\n",
+			r.Name, target, r.PVCount, r.Stripe, r.FreeBytes, r.SpareBytes)
+	}
+	for _, msg := range report.Warnings {
+		fmt.Fprintln(w, "warning:", msg)
+	}
+	for _, msg := range report.Errors {
+		fmt.Fprintln(w, "error:", msg)
+	}
+	if report.Valid {
+		fmt.Fprintf(w, "%s: OK\n", report.Config)
+	} else {
+		fmt.Fprintf(w, "%s: NG\n", report.Config)
+	}
+	return nil
+}
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateFlags.preflight, "preflight", false, "check volume groups and thin pools on this node")
+	validateCmd.Flags().StringVarP(&validateFlags.output, "output", "o", "text", "output format: text or json")
+	rootCmd.AddCommand(validateCmd)
+}
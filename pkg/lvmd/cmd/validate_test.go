@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/topolvm/topolvm/lvmd/command"
+)
+
+func TestStripesInOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  []string
+		expected uint
+		found    bool
+	}{
+		{name: "no options", options: nil},
+		{name: "other options", options: []string{"--type=raid1", "-m", "1"}},
+		{name: "--stripes", options: []string{"--stripes", "2"}, expected: 2, found: true},
+		{name: "--stripes=", options: []string{"--type=striped", "--stripes=3"}, expected: 3, found: true},
+		{name: "-i", options: []string{"-i", "4", "-I", "64"}, expected: 4, found: true},
+		{name: "-i with value", options: []string{"-i2"}, expected: 2, found: true},
+		{name: "missing value", options: []string{"--stripes"}, expected: 0, found: true},
+		{name: "invalid value", options: []string{"--stripes=two"}, expected: 0, found: true},
+	}
+
+	for _, tc := range testCases {
+		stripes, found := stripesInOptions(tc.options)
+		if stripes != tc.expected || found != tc.found {
+			t.Errorf("%s: expected=(%d, %t) actual=(%d, %t)", tc.name, tc.expected, tc.found, stripes, found)
+		}
+	}
+}
+
+type fakeVolumeGroup struct {
+	pvCount uint64
+	free    uint64
+	pools   map[string]*command.ThinPoolUsage
+}
+
+func (vg *fakeVolumeGroup) PVCount() uint64 {
+	return vg.pvCount
+}
+
+func (vg *fakeVolumeGroup) Free() (uint64, error) {
+	return vg.free, nil
+}
+
+func (vg *fakeVolumeGroup) ThinPoolUsage(name string) (*command.ThinPoolUsage, error) {
+	tpu, ok := vg.pools[name]
+	if !ok {
+		return nil, command.ErrNotFound
+	}
+	return tpu, nil
+}
+
+const validConfig = `device-classes:
+  - name: ssd
+    volume-group: vg1
+    default: true
+    spare-gb: 1
+    stripe: 2
+  - name: thin
+    volume-group: vg2
+    type: thin
+    spare-gb: 0
+    thin-pool:
+      name: pool
+      overprovision-ratio: 2.0
+`
+
+func testVolumeGroups() map[string]volumeGroupInfo {
+	return map[string]volumeGroupInfo{
+		"vg1": &fakeVolumeGroup{pvCount: 2, free: 5 << 30},
+		"vg2": &fakeVolumeGroup{pvCount: 1, pools: map[string]*command.ThinPoolUsage{
+			"pool": {SizeBytes: 4 << 30, VirtualBytes: 3 << 30},
+		}},
+	}
+}
+
+func TestValidateSubMain(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    string
+		preflight bool
+		vgs       map[string]volumeGroupInfo
+		code      int
+		report    []string
+	}{
+		{
+			name:   "valid",
+			config: validConfig,
+		},
+		{
+			name:      "valid with preflight",
+			config:    validConfig,
+			preflight: true,
+			vgs:       testVolumeGroups(),
+			report: []string{
+				"device-class ssd: vg1 pvs=2 stripe=2 free=5368709120 spare=1073741824\n",
+				"device-class thin: vg2/pool pvs=1 stripe=0 free=5368709120 spare=0\n",
+			},
+		},
+		{
+			name:   "unknown field",
+			config: validConfig + "unknown: true\n",
+			report: []string{"warning: ", "unknown"},
+		},
+		{
+			name:   "no device-classes",
+			config: "socket-name: /tmp/lvmd.sock\n",
+			code:   exitCodeInvalidConfig,
+			report: []string{"error: should have at least one device-class"},
+		},
+		{
+			name:      "invalid config skips preflight",
+			config:    strings.Replace(validConfig, "name: thin", "name: ssd", 1),
+			preflight: true,
+			code:      exitCodeInvalidConfig,
+			report:    []string{"error: duplicate device-class name: ssd"},
+		},
+		{
+			name: "invalid stripes in lvcreate-options",
+			config: `device-classes:
+  - name: ssd
+    volume-group: vg1
+    default: true
+    lvcreate-options: ["--stripes=two"]
+`,
+			code:   exitCodeInvalidConfig,
+			report: []string{"error: device-class ssd: invalid stripes in lvcreate-options"},
+		},
+		{
+			name:      "volume group not found",
+			config:    validConfig,
+			preflight: true,
+			vgs:       map[string]volumeGroupInfo{"vg2": testVolumeGroups()["vg2"]},
+			code:      exitCodePreflightFailed,
+			report:    []string{"error: device-class ssd: volume group not found: vg1"},
+		},
+		{
+			name:      "thin pool not found",
+			config:    strings.Replace(validConfig, "name: pool", "name: pool2", 1),
+			preflight: true,
+			vgs:       testVolumeGroups(),
+			code:      exitCodePreflightFailed,
+			report:    []string{"error: device-class thin: thin pool not found: pool2"},
+		},
+		{
+			name:      "stripe larger than PVs",
+			config:    strings.Replace(validConfig, "stripe: 2", "stripe: 3", 1),
+			preflight: true,
+			vgs:       testVolumeGroups(),
+			code:      exitCodePreflightFailed,
+			report:    []string{"error: device-class ssd: stripe 3 is larger than the number of physical volumes 2 in vg1"},
+		},
+		{
+			name:      "no free space",
+			config:    strings.Replace(validConfig, "spare-gb: 1", "spare-gb: 5", 1),
+			preflight: true,
+			vgs:       testVolumeGroups(),
+			report:    []string{"warning: device-class ssd: free space 5368709120 bytes is not larger than spare-gb"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfgFile := setupValidate(t, tc.config, tc.preflight, "text")
+			listVolumeGroups = func() (map[string]volumeGroupInfo, error) {
+				if tc.vgs == nil {
+					t.Fatal("unexpected preflight")
+				}
+				return tc.vgs, nil
+			}
+
+			var out bytes.Buffer
+			err := validateSubMain(&out)
+			if code := exitCodeOf(err); code != tc.code {
+				t.Errorf("expected exit code %d, actual %d: %v", tc.code, code, err)
+			}
+			report := out.String()
+			for _, s := range tc.report {
+				if !strings.Contains(report, s) {
+					t.Errorf("expected %q in report:\n%s", s, report)
+				}
+			}
+			result := cfgFile + ": OK\n"
+			if tc.code != 0 {
+				result = cfgFile + ": NG\n"
+			}
+			if !strings.HasSuffix(report, result) {
+				t.Errorf("expected %q at the end of report:\n%s", result, report)
+			}
+		})
+	}
+}
+
+func TestValidateSubMainJSON(t *testing.T) {
+	setupValidate(t, validConfig, true, "json")
+	listVolumeGroups = func() (map[string]volumeGroupInfo, error) {
+		return nil, errors.New("lvm is not available")
+	}
+
+	var out bytes.Buffer
+	err := validateSubMain(&out)
+	if code := exitCodeOf(err); code != exitCodePreflightFailed {
+		t.Errorf("expected exit code %d, actual %d: %v", exitCodePreflightFailed, code, err)
+	}
+	var report validateReport
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("failed to parse %q: %v", out.String(), err)
+	}
+	if report.Valid {
+		t.Error("expected invalid report")
+	}
+	if len(report.Errors) != 1 || !strings.Contains(report.Errors[0], "lvm is not available") {
+		t.Errorf("unexpected errors: %v", report.Errors)
+	}
+}
+
+func TestValidateSubMainUnreadable(t *testing.T) {
+	setupValidate(t, validConfig, false, "text")
+	cfgFilePath = filepath.Join(t.TempDir(), "missing.yaml")
+
+	err := validateSubMain(&bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if code := exitCodeOf(err); code != 1 {
+		t.Errorf("expected exit code 1, actual %d", code)
+	}
+}
+
+// setupValidate writes the config file and sets the flags of "lvmd validate".
+// It returns the path to the config file.
+func setupValidate(t *testing.T, config string, preflight bool, output string) string {
+	t.Helper()
+	cfgFile := filepath.Join(t.TempDir(), "lvmd.yaml")
+	if err := os.WriteFile(cfgFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origPath, origFlags, origList := cfgFilePath, validateFlags, listVolumeGroups
+	t.Cleanup(func() {
+		cfgFilePath, validateFlags, listVolumeGroups = origPath, origFlags, origList
+	})
+	cfgFilePath = cfgFile
+	validateFlags.preflight = preflight
+	validateFlags.output = output
+	return cfgFile
+}
+
+// exitCodeOf returns the exit code of "lvmd validate" for the error returned from validateSubMain.
+func exitCodeOf(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
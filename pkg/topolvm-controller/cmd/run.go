@@ -42,6 +42,7 @@ func init() {
 }
 
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;watch
 
 // Run builds and starts the manager with leader election.
 func subMain() error {
@@ -82,6 +83,7 @@ func subMain() error {
 	wh := mgr.GetWebhookServer()
 	wh.Register("/pod/mutate", hook.PodMutator(client, apiReader, dec))
 	wh.Register("/pvc/mutate", hook.PVCMutator(client, apiReader, dec))
+	wh.Register("/pvc/validate", hook.PVCValidator(client, apiReader, dec))
 
 	// register controllers
 	nodecontroller := controllers.NewNodeReconciler(client, config.skipNodeFinalize)
@@ -90,12 +92,35 @@ func subMain() error {
 		return err
 	}
 
+	reservationcontroller := controllers.NewReservationReconciler(client)
+	if err := reservationcontroller.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Reservation")
+		return err
+	}
+
+	if config.capacityNS != "" {
+		capacitycontroller := controllers.NewStorageCapacityReconciler(client, config.capacityNS)
+		if err := capacitycontroller.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "StorageCapacity")
+			return err
+		}
+	}
+
 	pvccontroller := controllers.NewPersistentVolumeClaimReconciler(client, apiReader)
 	if err := pvccontroller.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PersistentVolumeClaim")
 		return err
 	}
 
+	// TopoLVMQuota is not supported with the legacy API group.
+	if !topolvm.UseLegacy() {
+		quotacontroller := controllers.NewTopoLVMQuotaReconciler(client)
+		if err := quotacontroller.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "TopoLVMQuota")
+			return err
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	// Add health checker to manager
@@ -120,6 +145,7 @@ func subMain() error {
 		return err
 	}
 	csi.RegisterControllerServer(grpcServer, controllerSever)
+	csi.RegisterGroupControllerServer(grpcServer, controllerSever)
 
 	// gRPC service itself should run even when the manager is *not* a leader
 	// because CSI sidecar containers choose a leader.
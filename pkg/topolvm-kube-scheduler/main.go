@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os"
+
+	"github.com/topolvm/topolvm/scheduler/plugin"
+	"k8s.io/component-base/cli"
+	"k8s.io/kubernetes/cmd/kube-scheduler/app"
+)
+
+// topolvm-kube-scheduler is kube-scheduler with the TopoLVM plugin.
+func main() {
+	command := app.NewSchedulerCommand(
+		app.WithPlugin(plugin.Name, plugin.New),
+	)
+	os.Exit(cli.Run(command))
+}
@@ -119,6 +119,8 @@ func subMain() error {
 }
 
 //+kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func checkFunc(conn *grpc.ClientConn, r client.Reader) func() error {
 	vgs := proto.NewVGServiceClient(conn)
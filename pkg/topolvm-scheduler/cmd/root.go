@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
 
+	"github.com/cybozu-go/log"
 	"github.com/cybozu-go/well"
 	"github.com/spf13/cobra"
 	"github.com/topolvm/topolvm"
 	"github.com/topolvm/topolvm/scheduler"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
 
@@ -25,6 +29,12 @@ type Config struct {
 	Divisors map[string]float64 `json:"divisors"`
 	// DefaultDivisor is the default divisor value.
 	DefaultDivisor float64 `json:"default-divisor"`
+	// Strategies is a mapping between device-class names and their scoring strategies.
+	Strategies map[string]scheduler.Strategy `json:"strategies"`
+	// DefaultStrategy is the default scoring strategy.
+	DefaultStrategy scheduler.Strategy `json:"default-strategy"`
+	// ThinPoolThresholds is the limits of the physical usage of thin pools.
+	ThinPoolThresholds scheduler.ThinPoolThresholds `json:"thin-pool-thresholds"`
 }
 
 var config = &Config{
@@ -51,6 +61,9 @@ It scores nodes with this formula:
     min(10, max(0, log2(capacity >> 30 / divisor)))
 
 The default divisor is 1.  It can be changed with a command-line option.
+The scoring strategy can be changed per device-class in the config file.
+
+The decisions for a pod are explained at "/explain" via HTTP.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
@@ -75,7 +88,20 @@ func subMain() error {
 		}
 	}
 
-	h, err := scheduler.NewHandler(config.DefaultDivisor, config.Divisors)
+	// The API client is optional.  It is used only to look up pods by /explain.
+	var reader client.Reader
+	if cfg, err := ctrl.GetConfig(); err != nil {
+		log.Warn("pod lookup by /explain is disabled", map[string]interface{}{
+			log.FnError: err,
+		})
+	} else {
+		reader, err = client.New(cfg, client.Options{})
+		if err != nil {
+			return err
+		}
+	}
+
+	h, err := scheduler.NewHandler(config.DefaultDivisor, config.Divisors, config.DefaultStrategy, config.Strategies, config.ThinPoolThresholds, reader)
 	if err != nil {
 		return err
 	}
@@ -111,4 +137,9 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFilePath, "config", "", "config file")
+
+	// flags for logging defined by well.
+	for _, name := range []string{"logfile", "loglevel", "logformat"} {
+		rootCmd.PersistentFlags().AddGoFlag(flag.CommandLine.Lookup(name))
+	}
 }
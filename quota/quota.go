@@ -0,0 +1,213 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrExceeded represents that a TopoLVMQuota is exceeded.
+var ErrExceeded = errors.New("TopoLVMQuota exceeded")
+
+// Usage represents the usage of a device-class in a namespace.
+type Usage struct {
+	Bytes   int64
+	Volumes int64
+}
+
+// DeviceClasses returns the candidate device-classes of the StorageClass.
+// For StorageClasses that select device-classes by labels, the device-classes of
+// all the nodes that match the selector are returned.
+// The second return value is false if the StorageClass is not for TopoLVM.
+func DeviceClasses(ctx context.Context, r client.Reader, sc *storagev1.StorageClass) ([]string, bool, error) {
+	if sc.Provisioner != topolvm.GetPluginName() {
+		return nil, false, nil
+	}
+	param, ok := sc.Parameters[topolvm.GetDeviceClassSelectorKey()]
+	if !ok {
+		return topolvm.DeviceClasses(sc.Parameters[topolvm.GetDeviceClassKey()]), true, nil
+	}
+
+	// CreateVolume rejects invalid selectors, so no device-class can be used.
+	selector, err := labels.Parse(param)
+	if err != nil {
+		return nil, true, nil
+	}
+	var nodeList corev1.NodeList
+	if err := r.List(ctx, &nodeList); err != nil {
+		return nil, false, err
+	}
+	var dcs []string
+	for i := range nodeList.Items {
+		for _, dc := range topolvm.SelectDeviceClasses(&nodeList.Items[i], selector) {
+			if dc == topolvm.DefaultDeviceClassAnnotationName {
+				dc = topolvm.DefaultDeviceClassName
+			}
+			if !containsString(dcs, dc) {
+				dcs = append(dcs, dc)
+			}
+		}
+	}
+	sort.Strings(dcs)
+	return dcs, true, nil
+}
+
+// ResolveDeviceClasses returns the device-class of the LogicalVolume for the PersistentVolume named volumeName.
+// The candidates are returned if the volume is not created yet.
+func ResolveDeviceClasses(ctx context.Context, r client.Reader, volumeName string, candidates []string) ([]string, error) {
+	if volumeName == "" || len(candidates) == 0 {
+		return candidates, nil
+	}
+	var lv topolvmv1.LogicalVolume
+	err := r.Get(ctx, client.ObjectKey{Name: volumeName}, &lv)
+	switch {
+	case err == nil:
+		return []string{lv.Spec.DeviceClass}, nil
+	case apierrors.IsNotFound(err):
+		return candidates, nil
+	default:
+		return nil, err
+	}
+}
+
+// NamespaceUsage returns the usage of device-classes in the namespace.
+// The usage is the total requested bytes and the number of PVCs of TopoLVM StorageClasses.
+// PVCs are counted in the device-class of their LogicalVolumes, or in all the candidate
+// device-classes of their StorageClasses if the volumes are not created yet.
+// The PVC named exclude is not counted.
+func NamespaceUsage(ctx context.Context, r client.Reader, namespace, exclude string) (map[string]*Usage, error) {
+	var scList storagev1.StorageClassList
+	if err := r.List(ctx, &scList); err != nil {
+		return nil, err
+	}
+	deviceClasses := make(map[string][]string)
+	for i := range scList.Items {
+		dcs, ok, err := DeviceClasses(ctx, r, &scList.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			deviceClasses[scList.Items[i].Name] = dcs
+		}
+	}
+
+	var pvcList corev1.PersistentVolumeClaimList
+	if err := r.List(ctx, &pvcList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var lvList topolvmv1.LogicalVolumeList
+	if err := r.List(ctx, &lvList); err != nil {
+		return nil, err
+	}
+	resolved := make(map[string]string)
+	for _, lv := range lvList.Items {
+		resolved[lv.Spec.Name] = lv.Spec.DeviceClass
+	}
+
+	usage := make(map[string]*Usage)
+	for _, pvc := range pvcList.Items {
+		if pvc.Name == exclude || pvc.Spec.StorageClassName == nil {
+			continue
+		}
+		dcs, ok := deviceClasses[*pvc.Spec.StorageClassName]
+		if !ok {
+			continue
+		}
+		if dc, ok := resolved[pvc.Spec.VolumeName]; ok && pvc.Spec.VolumeName != "" {
+			dcs = []string{dc}
+		}
+		for _, dc := range dcs {
+			u, ok := usage[dc]
+			if !ok {
+				u = &Usage{}
+				usage[dc] = u
+			}
+			u.Bytes += pvc.Spec.Resources.Requests.Storage().Value()
+			u.Volumes++
+		}
+	}
+	return usage, nil
+}
+
+// Check checks TopoLVMQuotas in the namespace for the PVC named pvcName
+// that requests requestBytes of one of the device-classes.
+// The request must be within the quotas of all the device-classes because any of them may be used.
+// The current usage of the PVC itself is not counted, so this can be used
+// for both of creating and expanding the PVC.
+// An error wrapping ErrExceeded is returned if any quota is exceeded.
+func Check(ctx context.Context, r client.Reader, namespace, pvcName string, deviceClasses []string, requestBytes int64) error {
+	// TopoLVMQuota is not supported with the legacy API group.
+	if topolvm.UseLegacy() {
+		return nil
+	}
+
+	var quotaList topolvmv1.TopoLVMQuotaList
+	if err := r.List(ctx, &quotaList, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+
+	// The device-classes are unknown if no node matches the selector of the StorageClass.
+	// Such requests are denied because they could be provisioned later without being counted.
+	if len(deviceClasses) == 0 {
+		if len(quotaList.Items) != 0 {
+			return fmt.Errorf("%w: %s: no device-class is found for the request", ErrExceeded, namespace)
+		}
+		return nil
+	}
+
+	var limits []*topolvmv1.DeviceClassQuota
+	var names []string
+	for i := range quotaList.Items {
+		q := &quotaList.Items[i]
+		for j := range q.Spec.Limits {
+			if containsString(deviceClasses, q.Spec.Limits[j].DeviceClass) {
+				limits = append(limits, &q.Spec.Limits[j])
+				names = append(names, q.Name)
+			}
+		}
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	usage, err := NamespaceUsage(ctx, r, namespace, pvcName)
+	if err != nil {
+		return err
+	}
+
+	for i, l := range limits {
+		deviceClass := l.DeviceClass
+		used := usage[deviceClass]
+		if used == nil {
+			used = &Usage{}
+		}
+		if l.Storage != nil && used.Bytes+requestBytes > l.Storage.Value() {
+			return fmt.Errorf("%w: %s/%s: storage of device-class %q: requested=%d, used=%d, limited=%d",
+				ErrExceeded, namespace, names[i], deviceClass, requestBytes, used.Bytes, l.Storage.Value())
+		}
+		if l.Volumes != nil && used.Volumes+1 > *l.Volumes {
+			return fmt.Errorf("%w: %s/%s: volumes of device-class %q: used=%d, limited=%d",
+				ErrExceeded, namespace, names[i], deviceClass, used.Volumes, *l.Volumes)
+		}
+	}
+	return nil
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "test"
+
+func testStorageClass(name, provisioner, dc string) *storagev1.StorageClass {
+	sc := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: name},
+		Provisioner: provisioner,
+	}
+	if dc != "" {
+		sc.Parameters = map[string]string{topolvm.GetDeviceClassKey(): dc}
+	}
+	return sc
+}
+
+func testPVC(name, sc string, size int64) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &sc,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI),
+				},
+			},
+		},
+	}
+}
+
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	objs = append(objs,
+		testStorageClass("ssd1", topolvm.GetPluginName(), "ssd"),
+		testStorageClass("ssd2", topolvm.GetPluginName(), "ssd"),
+		testStorageClass("default", topolvm.GetPluginName(), ""),
+		testStorageClass("other", "other.csi.example.com", "ssd"),
+		testPVC("a", "ssd1", 10<<30),
+		testPVC("b", "ssd2", 5<<30),
+		testPVC("c", "default", 1<<30),
+		testPVC("d", "other", 100<<30),
+	)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestNamespaceUsage(t *testing.T) {
+	c := newTestClient(t)
+
+	usage, err := NamespaceUsage(context.Background(), c, testNamespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 {
+		t.Errorf("unexpected device-classes: %v", usage)
+	}
+	if u := usage["ssd"]; u == nil || u.Bytes != 15<<30 || u.Volumes != 2 {
+		t.Errorf("unexpected usage of ssd: %v", u)
+	}
+	if u := usage[""]; u == nil || u.Bytes != 1<<30 || u.Volumes != 1 {
+		t.Errorf("unexpected usage of the default device-class: %v", u)
+	}
+
+	usage, err = NamespaceUsage(context.Background(), c, testNamespace, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u := usage["ssd"]; u == nil || u.Bytes != 5<<30 || u.Volumes != 1 {
+		t.Errorf("excluded PVC should not be counted: %v", u)
+	}
+}
+
+func TestNamespaceUsageFallback(t *testing.T) {
+	bound := testPVC("f1", "fallback", 2<<30)
+	bound.Spec.VolumeName = "pv-f1"
+	pending := testPVC("f2", "fallback", 3<<30)
+	lv := &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-f1"},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:        "pv-f1",
+			DeviceClass: "ssd",
+		},
+	}
+	c := newTestClient(t, testStorageClass("fallback", topolvm.GetPluginName(), "nvme, ssd"), bound, pending, lv)
+
+	usage, err := NamespaceUsage(context.Background(), c, testNamespace, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the bound PVC is counted in the device-class of the LogicalVolume,
+	// and the pending PVC is counted in all the candidates.
+	if u := usage["ssd"]; u == nil || u.Bytes != 20<<30 || u.Volumes != 4 {
+		t.Errorf("unexpected usage of ssd: %v", u)
+	}
+	if u := usage["nvme"]; u == nil || u.Bytes != 3<<30 || u.Volumes != 1 {
+		t.Errorf("unexpected usage of nvme: %v", u)
+	}
+
+	dcs, err := ResolveDeviceClasses(context.Background(), c, "pv-f1", []string{"nvme", "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dcs) != 1 || dcs[0] != "ssd" {
+		t.Errorf("the device-class of the LogicalVolume should be returned: %v", dcs)
+	}
+	dcs, err = ResolveDeviceClasses(context.Background(), c, "", []string{"nvme", "ssd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dcs) != 2 {
+		t.Errorf("the candidates should be returned for pending PVCs: %v", dcs)
+	}
+}
+
+func TestDeviceClassesBySelector(t *testing.T) {
+	node1 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node1",
+			Annotations: map[string]string{
+				topolvm.GetDeviceClassLabelsKeyPrefix() + "ssd":                                    "type=fast",
+				topolvm.GetDeviceClassLabelsKeyPrefix() + topolvm.DefaultDeviceClassAnnotationName: "type=fast",
+				topolvm.GetDeviceClassLabelsKeyPrefix() + "hdd":                                    "type=slow",
+			},
+		},
+	}
+	node2 := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node2",
+			Annotations: map[string]string{
+				topolvm.GetDeviceClassLabelsKeyPrefix() + "nvme": "type=fast",
+			},
+		},
+	}
+	c := newTestClient(t, node1, node2)
+
+	testCases := []struct {
+		selector string
+		expected []string
+	}{
+		{"type=fast", []string{"", "nvme", "ssd"}},
+		{"type=slow", []string{"hdd"}},
+		{"type=none", nil},
+		{"type in (", nil},
+	}
+	for _, tc := range testCases {
+		sc := &storagev1.StorageClass{
+			Provisioner: topolvm.GetPluginName(),
+			Parameters:  map[string]string{topolvm.GetDeviceClassSelectorKey(): tc.selector},
+		}
+		dcs, ok, err := DeviceClasses(context.Background(), c, sc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Errorf("%s: StorageClass should be for TopoLVM", tc.selector)
+		}
+		if !reflect.DeepEqual(dcs, tc.expected) {
+			t.Errorf("%s: expected=%v actual=%v", tc.selector, tc.expected, dcs)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	storage := resource.MustParse("20Gi")
+	volumes := int64(3)
+	quota := &topolvmv1.TopoLVMQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "quota"},
+		Spec: topolvmv1.TopoLVMQuotaSpec{
+			Limits: []topolvmv1.DeviceClassQuota{
+				{DeviceClass: "ssd", Storage: &storage, Volumes: &volumes},
+			},
+		},
+	}
+	c := newTestClient(t, quota)
+
+	testCases := []struct {
+		name          string
+		pvcName       string
+		deviceClasses []string
+		request       int64
+		exceeded      bool
+	}{
+		{"within limits", "new", []string{"ssd"}, 5 << 30, false},
+		{"storage exceeded", "new", []string{"ssd"}, 6 << 30, true},
+		{"expand within limits", "a", []string{"ssd"}, 15 << 30, false},
+		{"expand exceeded", "a", []string{"ssd"}, 16 << 30, true},
+		{"no quota for device-class", "new", []string{""}, 100 << 30, false},
+		{"fallback within limits", "new", []string{"nvme", "ssd"}, 5 << 30, false},
+		{"fallback exceeded", "new", []string{"nvme", "ssd"}, 6 << 30, true},
+		{"no device-class with quota", "new", nil, 1, true},
+	}
+	for _, tc := range testCases {
+		err := Check(context.Background(), c, testNamespace, tc.pvcName, tc.deviceClasses, tc.request)
+		if tc.exceeded {
+			if !errors.Is(err, ErrExceeded) {
+				t.Errorf("%s: ErrExceeded should be returned: %v", tc.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+
+	if err := c.Create(context.Background(), testPVC("e", "ssd1", 1<<30)); err != nil {
+		t.Fatal(err)
+	}
+	err := Check(context.Background(), c, testNamespace, "new", []string{"ssd"}, 1<<30)
+	if !errors.Is(err, ErrExceeded) {
+		t.Errorf("volumes should be exceeded: %v", err)
+	}
+}
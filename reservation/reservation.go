@@ -0,0 +1,89 @@
+package reservation
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Ledger represents bytes reserved for in-flight LogicalVolumes.
+// The keys are node names and device-class names.
+type Ledger map[string]map[string]int64
+
+// Reserved returns the bytes reserved on the node for the device-class.
+func (l Ledger) Reserved(node, deviceClass string) int64 {
+	return l[node][deviceClass]
+}
+
+// IsInFlight returns true if the LogicalVolume is not created by lvmd yet.
+// A reservation expires when the LV appears (status.volumeID is set),
+// or when the creation fails (status.code is set or the LogicalVolume is deleted).
+func IsInFlight(lv *topolvmv1.LogicalVolume) bool {
+	if lv.DeletionTimestamp != nil {
+		return false
+	}
+	// Thin snapshots do not consume the VG free space on creation.
+	if lv.Spec.Source != "" {
+		return false
+	}
+	return lv.Status.VolumeID == "" && lv.Status.Code == codes.OK
+}
+
+// Build returns the ledger of in-flight LogicalVolumes.
+// The LogicalVolume named exclude is not counted.
+func Build(ctx context.Context, r client.Reader, exclude string) (Ledger, error) {
+	var lvList topolvmv1.LogicalVolumeList
+	if err := r.List(ctx, &lvList); err != nil {
+		return nil, err
+	}
+
+	ledger := make(Ledger)
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		if lv.Name == exclude || !IsInFlight(lv) {
+			continue
+		}
+		dcs, ok := ledger[lv.Spec.NodeName]
+		if !ok {
+			dcs = make(map[string]int64)
+			ledger[lv.Spec.NodeName] = dcs
+		}
+		dcs[lv.Spec.DeviceClass] += lv.Spec.Size.Value()
+	}
+	return ledger, nil
+}
+
+// Annotations returns the Node annotations that represent the reserved bytes for each device-class.
+func Annotations(reserved map[string]int64) map[string]string {
+	annotations := make(map[string]string)
+	for dc, size := range reserved {
+		if size <= 0 {
+			continue
+		}
+		if dc == topolvm.DefaultDeviceClassName {
+			dc = topolvm.DefaultDeviceClassAnnotationName
+		}
+		annotations[topolvm.GetReservedKeyPrefix()+dc] = strconv.FormatInt(size, 10)
+	}
+	return annotations
+}
+
+// FromAnnotation returns the reserved bytes for the device-class from the Node annotation.
+// dc must be the name used in the annotation key, i.e. "00default" for the default device-class.
+// It returns 0 if the annotation is missing or malformed.
+func FromAnnotation(node *corev1.Node, dc string) int64 {
+	v, ok := node.Annotations[topolvm.GetReservedKeyPrefix()+dc]
+	if !ok {
+		return 0
+	}
+	reserved, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || reserved < 0 {
+		return 0
+	}
+	return reserved
+}
@@ -0,0 +1,110 @@
+package reservation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"google.golang.org/grpc/codes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func testLV(name, node, dc string, size int64) *topolvmv1.LogicalVolume {
+	return &topolvmv1.LogicalVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: topolvmv1.LogicalVolumeSpec{
+			Name:        name,
+			NodeName:    node,
+			DeviceClass: dc,
+			Size:        *resource.NewQuantity(size, resource.BinarySI),
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := topolvmv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	created := testLV("created", "node1", "ssd", 100<<30)
+	created.Status.VolumeID = "created"
+	failed := testLV("failed", "node1", "ssd", 100<<30)
+	failed.Status.Code = codes.ResourceExhausted
+	snapshot := testLV("snapshot", "node1", "ssd", 100<<30)
+	snapshot.Spec.Source = "created"
+
+	objs := []client.Object{
+		testLV("a", "node1", "ssd", 1<<30),
+		testLV("b", "node1", "ssd", 2<<30),
+		testLV("c", "node1", "", 4<<30),
+		testLV("d", "node2", "ssd", 8<<30),
+		created,
+		failed,
+		snapshot,
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	ledger, err := Build(context.Background(), c, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		node     string
+		dc       string
+		expected int64
+	}{
+		{"node1", "ssd", 3 << 30},
+		{"node1", "", 4 << 30},
+		{"node1", "hdd", 0},
+		{"node2", "ssd", 8 << 30},
+		{"node3", "ssd", 0},
+	}
+	for _, tc := range testCases {
+		if r := ledger.Reserved(tc.node, tc.dc); r != tc.expected {
+			t.Errorf("unexpected reservation for %s/%s: expected=%d actual=%d", tc.node, tc.dc, tc.expected, r)
+		}
+	}
+
+	ledger, err = Build(context.Background(), c, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := ledger.Reserved("node1", "ssd"); r != 2<<30 {
+		t.Errorf("excluded LogicalVolume should not be counted: %d", r)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	annotations := Annotations(map[string]int64{
+		"ssd": 1 << 30,
+		"":    2 << 30,
+		"hdd": 0,
+	})
+	if len(annotations) != 2 {
+		t.Errorf("unexpected annotations: %v", annotations)
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+	}
+	node.Annotations[topolvm.GetReservedKeyPrefix()+"bad"] = "foo"
+	if r := FromAnnotation(node, "ssd"); r != 1<<30 {
+		t.Errorf("unexpected reservation for ssd: %d", r)
+	}
+	if r := FromAnnotation(node, topolvm.DefaultDeviceClassAnnotationName); r != 2<<30 {
+		t.Errorf("unexpected reservation for the default device-class: %d", r)
+	}
+	if r := FromAnnotation(node, "hdd"); r != 0 {
+		t.Errorf("unexpected reservation for hdd: %d", r)
+	}
+	if r := FromAnnotation(node, "bad"); r != 0 {
+		t.Errorf("malformed annotation should be ignored: %d", r)
+	}
+}
@@ -2,8 +2,10 @@ package runners
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/topolvm/topolvm"
@@ -12,6 +14,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -120,10 +123,15 @@ func NewMetricsExporter(conn *grpc.ClientConn, client client.Client, nodeName st
 	}, []string{"device_class"})
 	metrics.Registry.MustRegister(opAvailableBytes)
 
+	vgService := proto.NewVGServiceClient(conn)
+
+	// metrics available under volume subsystem
+	metrics.Registry.MustRegister(newVolumeMetricsCollector(client, nodeName, vgService))
+
 	return &metricsExporter{
 		client:         client,
 		nodeName:       nodeName,
-		vgService:      proto.NewVGServiceClient(conn),
+		vgService:      vgService,
 		availableBytes: availableBytes,
 		sizeBytes:      sizeBytes,
 		thinPool: &thinPoolMetricsExporter{
@@ -223,13 +231,38 @@ func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_Wat
 
 		node2.Annotations[topolvm.GetCapacityKeyPrefix()+topolvm.DefaultDeviceClassAnnotationName] = strconv.FormatUint(res.FreeBytes, 10)
 		for _, item := range res.Items {
-			var freeSize uint64
+			var freeSize, size uint64
 			if item.ThinPool != nil {
 				freeSize = item.ThinPool.OverprovisionBytes
+				size = item.ThinPool.OverprovisionSizeBytes
 			} else {
 				freeSize = item.FreeBytes
+				size = item.SizeBytes
 			}
 			node2.Annotations[topolvm.GetCapacityKeyPrefix()+item.DeviceClass] = strconv.FormatUint(freeSize, 10)
+			node2.Annotations[topolvm.GetDeviceClassLabelsKeyPrefix()+item.DeviceClass] = labels.Set(item.Labels).String()
+			mkfsOptions := ""
+			if len(item.MkfsOptions) != 0 {
+				data, err := json.Marshal(item.MkfsOptions)
+				if err != nil {
+					return err
+				}
+				mkfsOptions = string(data)
+			}
+
+			dcs := []string{item.DeviceClass}
+			if item.IsDefault {
+				dcs = append(dcs, topolvm.DefaultDeviceClassAnnotationName)
+			}
+			for _, dc := range dcs {
+				node2.Annotations[topolvm.GetSizeKeyPrefix()+dc] = strconv.FormatUint(size, 10)
+				setOrDeleteAnnotation(node2, topolvm.GetMkfsOptionsKeyPrefix()+dc, mkfsOptions)
+				setOrDeleteAnnotation(node2, topolvm.GetMountOptionsKeyPrefix()+dc, strings.Join(item.MountOptions, ","))
+				if item.ThinPool != nil {
+					node2.Annotations[topolvm.GetThinDataPercentKeyPrefix()+dc] = strconv.FormatFloat(item.ThinPool.DataPercent, 'f', -1, 64)
+					node2.Annotations[topolvm.GetThinMetadataPercentKeyPrefix()+dc] = strconv.FormatFloat(item.ThinPool.MetadataPercent, 'f', -1, 64)
+				}
+			}
 		}
 		if err := m.client.Patch(ctx, node2, client.MergeFrom(&node)); err != nil {
 			return err
@@ -238,3 +271,12 @@ func (m *metricsExporter) updateNode(ctx context.Context, wc proto.VGService_Wat
 
 	return nil
 }
+
+// setOrDeleteAnnotation sets the annotation, or deletes it if the value is empty.
+func setOrDeleteAnnotation(node *corev1.Node, key, value string) {
+	if value == "" {
+		delete(node.Annotations, key)
+		return
+	}
+	node.Annotations[key] = value
+}
@@ -0,0 +1,242 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	"github.com/topolvm/topolvm/lvmd/proto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// sysDevBlockPath is the directory that has block devices named "<major>:<minor>".
+// Each of them is a symbolic link to the device directory, e.g. /sys/block/dm-N.
+const sysDevBlockPath = "/sys/dev/block"
+
+// sectorSize is the unit of sectors in the block device stat file.
+// https://www.kernel.org/doc/Documentation/block/stat.txt
+const sectorSize = 512
+
+const volumeMetricsTimeout = 10 * time.Second
+
+var volumeLabels = []string{"device_class", "logicalvolume", "namespace", "persistentvolumeclaim"}
+
+// blockStat holds the fields of the block device stat file used for metrics.
+type blockStat struct {
+	readIOs      uint64
+	readSectors  uint64
+	readTicks    uint64
+	writeIOs     uint64
+	writeSectors uint64
+	writeTicks   uint64
+}
+
+// parseBlockStat parses the content of /sys/block/<dev>/stat.
+func parseBlockStat(data string) (*blockStat, error) {
+	fields := strings.Fields(data)
+	if len(fields) < 8 {
+		return nil, fmt.Errorf("too few fields in block device stat: %d", len(fields))
+	}
+	values := make([]uint64, 8)
+	for i := range values {
+		v, err := strconv.ParseUint(fields[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field in block device stat: %w", err)
+		}
+		values[i] = v
+	}
+	return &blockStat{
+		readIOs:      values[0],
+		readSectors:  values[2],
+		readTicks:    values[3],
+		writeIOs:     values[4],
+		writeSectors: values[6],
+		writeTicks:   values[7],
+	}, nil
+}
+
+func readBlockStat(major, minor uint32) (*blockStat, error) {
+	data, err := os.ReadFile(filepath.Join(sysDevBlockPath, fmt.Sprintf("%d:%d", major, minor), "stat"))
+	if err != nil {
+		return nil, err
+	}
+	return parseBlockStat(string(data))
+}
+
+// volumeMetricsCollector is a prometheus.Collector to export metrics of
+// logical volumes on the node.  The metrics are collected on each scrape
+// because the I/O statistics change continuously.
+type volumeMetricsCollector struct {
+	client    client.Client
+	nodeName  string
+	vgService proto.VGServiceClient
+
+	sizeBytes      *prometheus.Desc
+	allocatedBytes *prometheus.Desc
+	readIOs        *prometheus.Desc
+	readBytes      *prometheus.Desc
+	readSeconds    *prometheus.Desc
+	writeIOs       *prometheus.Desc
+	writeBytes     *prometheus.Desc
+	writeSeconds   *prometheus.Desc
+
+	// pvcs caches the PVC bound to each LogicalVolume by its UID
+	// to avoid getting PersistentVolumes on every scrape.
+	// The binding does not change once the PersistentVolume is bound.
+	mu   sync.Mutex
+	pvcs map[types.UID]types.NamespacedName
+}
+
+var _ prometheus.Collector = &volumeMetricsCollector{}
+
+func newVolumeMetricsCollector(client client.Client, nodeName string, vgService proto.VGServiceClient) *volumeMetricsCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "volume", name),
+			help,
+			volumeLabels,
+			prometheus.Labels{"node": nodeName},
+		)
+	}
+	return &volumeMetricsCollector{
+		client:         client,
+		nodeName:       nodeName,
+		vgService:      vgService,
+		sizeBytes:      desc("size_bytes", "LVM LV size bytes"),
+		allocatedBytes: desc("allocated_bytes", "LVM LV bytes allocated in the VG or the thin pool"),
+		readIOs:        desc("read_completed_total", "The number of read I/Os completed on the LV"),
+		readBytes:      desc("read_bytes_total", "The number of bytes read from the LV"),
+		readSeconds:    desc("read_time_seconds_total", "The total time spent by read I/Os on the LV"),
+		writeIOs:       desc("write_completed_total", "The number of write I/Os completed on the LV"),
+		writeBytes:     desc("write_bytes_total", "The number of bytes written to the LV"),
+		writeSeconds:   desc("write_time_seconds_total", "The total time spent by write I/Os on the LV"),
+		pvcs:           make(map[types.UID]types.NamespacedName),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *volumeMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sizeBytes
+	ch <- c.allocatedBytes
+	ch <- c.readIOs
+	ch <- c.readBytes
+	ch <- c.readSeconds
+	ch <- c.writeIOs
+	ch <- c.writeBytes
+	ch <- c.writeSeconds
+}
+
+// Collect implements prometheus.Collector.
+func (c *volumeMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), volumeMetricsTimeout)
+	defer cancel()
+
+	var lvList topolvmv1.LogicalVolumeList
+	if err := c.client.List(ctx, &lvList); err != nil {
+		meLogger.Error(err, "failed to list LogicalVolumes")
+		return
+	}
+
+	// lvmd volumes by device-class and name
+	volumes := make(map[string]map[string]*proto.LogicalVolume)
+	existing := make(map[types.UID]bool)
+	defer c.prunePVCs(existing)
+	for i := range lvList.Items {
+		lv := &lvList.Items[i]
+		if lv.Spec.NodeName != c.nodeName || lv.Status.VolumeID == "" {
+			continue
+		}
+		existing[lv.UID] = true
+
+		dc := lv.Spec.DeviceClass
+		if _, ok := volumes[dc]; !ok {
+			res, err := c.vgService.GetLVList(ctx, &proto.GetLVListRequest{DeviceClass: dc})
+			if err != nil {
+				meLogger.Error(err, "failed to get list of LVs", "device_class", dc)
+				volumes[dc] = nil
+				continue
+			}
+			volumes[dc] = make(map[string]*proto.LogicalVolume)
+			for _, v := range res.Volumes {
+				volumes[dc][v.Name] = v
+			}
+		}
+		v, ok := volumes[dc][lv.Status.VolumeID]
+		if !ok {
+			continue
+		}
+
+		namespace, pvc := c.getPVC(ctx, lv)
+		labels := []string{dc, lv.Name, namespace, pvc}
+		c.collectVolume(ch, v, labels)
+	}
+}
+
+// getPVC returns the namespace and the name of the PVC bound to the LogicalVolume.
+// The PV of the LogicalVolume has the same name as the LogicalVolume.
+func (c *volumeMetricsCollector) getPVC(ctx context.Context, lv *topolvmv1.LogicalVolume) (string, string) {
+	c.mu.Lock()
+	pvc, ok := c.pvcs[lv.UID]
+	c.mu.Unlock()
+	if ok {
+		return pvc.Namespace, pvc.Name
+	}
+
+	var pv corev1.PersistentVolume
+	if err := c.client.Get(ctx, types.NamespacedName{Name: lv.Spec.Name}, &pv); err != nil {
+		meLogger.Error(err, "failed to get PersistentVolume", "name", lv.Spec.Name)
+		return "", ""
+	}
+	if pv.Spec.ClaimRef == nil {
+		return "", ""
+	}
+
+	c.mu.Lock()
+	c.pvcs[lv.UID] = types.NamespacedName{Namespace: pv.Spec.ClaimRef.Namespace, Name: pv.Spec.ClaimRef.Name}
+	c.mu.Unlock()
+	return pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name
+}
+
+// prunePVCs removes the cache of LogicalVolumes that no longer exist.
+func (c *volumeMetricsCollector) prunePVCs(existing map[types.UID]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uid := range c.pvcs {
+		if !existing[uid] {
+			delete(c.pvcs, uid)
+		}
+	}
+}
+
+func (c *volumeMetricsCollector) collectVolume(ch chan<- prometheus.Metric, v *proto.LogicalVolume, labels []string) {
+	allocated := float64(v.SizeBytes)
+	if v.Pool != "" {
+		allocated = float64(v.SizeBytes) * v.DataPercent / 100
+	}
+	ch <- prometheus.MustNewConstMetric(c.sizeBytes, prometheus.GaugeValue, float64(v.SizeBytes), labels...)
+	ch <- prometheus.MustNewConstMetric(c.allocatedBytes, prometheus.GaugeValue, allocated, labels...)
+
+	if !v.Attributes.GetActive() {
+		return
+	}
+	stat, err := readBlockStat(v.DevMajor, v.DevMinor)
+	if err != nil {
+		meLogger.Error(err, "failed to read block device stat", "name", v.Name, "major", v.DevMajor, "minor", v.DevMinor)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.readIOs, prometheus.CounterValue, float64(stat.readIOs), labels...)
+	ch <- prometheus.MustNewConstMetric(c.readBytes, prometheus.CounterValue, float64(stat.readSectors*sectorSize), labels...)
+	ch <- prometheus.MustNewConstMetric(c.readSeconds, prometheus.CounterValue, float64(stat.readTicks)/1000, labels...)
+	ch <- prometheus.MustNewConstMetric(c.writeIOs, prometheus.CounterValue, float64(stat.writeIOs), labels...)
+	ch <- prometheus.MustNewConstMetric(c.writeBytes, prometheus.CounterValue, float64(stat.writeSectors*sectorSize), labels...)
+	ch <- prometheus.MustNewConstMetric(c.writeSeconds, prometheus.CounterValue, float64(stat.writeTicks)/1000, labels...)
+}
@@ -0,0 +1,152 @@
+package runners
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	topolvmv1 "github.com/topolvm/topolvm/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseBlockStat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected *blockStat
+		hasErr   bool
+	}{
+		{
+			name: "full stat",
+			data: "    1234       10    56789      321     4321       20    98765      654        0      800      975        0        0        0        0        0        0\n",
+			expected: &blockStat{
+				readIOs:      1234,
+				readSectors:  56789,
+				readTicks:    321,
+				writeIOs:     4321,
+				writeSectors: 98765,
+				writeTicks:   654,
+			},
+		},
+		{
+			name: "old kernel with 11 fields",
+			data: "1 2 3 4 5 6 7 8 9 10 11",
+			expected: &blockStat{
+				readIOs:      1,
+				readSectors:  3,
+				readTicks:    4,
+				writeIOs:     5,
+				writeSectors: 7,
+				writeTicks:   8,
+			},
+		},
+		{
+			name:   "empty",
+			data:   "",
+			hasErr: true,
+		},
+		{
+			name:   "too few fields",
+			data:   "1 2 3 4 5 6 7",
+			hasErr: true,
+		},
+		{
+			name:   "not a number",
+			data:   "1 2 3 4 five 6 7 8 9 10 11",
+			hasErr: true,
+		},
+		{
+			name:   "negative number",
+			data:   "1 2 3 -4 5 6 7 8 9 10 11",
+			hasErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		stat, err := parseBlockStat(tc.data)
+		if tc.hasErr {
+			if err == nil {
+				t.Errorf("%s: error is expected", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(stat, tc.expected) {
+			t.Errorf("%s: expected=%+v actual=%+v", tc.name, tc.expected, stat)
+		}
+	}
+}
+
+// countingClient counts Get calls.
+type countingClient struct {
+	client.Client
+	gets int
+}
+
+func (c *countingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.gets++
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func TestVolumeMetricsCollectorGetPVC(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	bound := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-bound"},
+		Spec: corev1.PersistentVolumeSpec{
+			ClaimRef: &corev1.ObjectReference{Namespace: "ns", Name: "pvc"},
+		},
+	}
+	unbound := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-unbound"},
+	}
+	c := &countingClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(bound, unbound).Build()}
+	collector := newVolumeMetricsCollector(c, "node1", nil)
+
+	lv := func(uid, pvName string) *topolvmv1.LogicalVolume {
+		return &topolvmv1.LogicalVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: pvName, UID: types.UID(uid)},
+			Spec:       topolvmv1.LogicalVolumeSpec{Name: pvName},
+		}
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		namespace, name := collector.getPVC(ctx, lv("uid-bound", "pv-bound"))
+		if namespace != "ns" || name != "pvc" {
+			t.Fatalf("unexpected PVC: %s/%s", namespace, name)
+		}
+	}
+	if c.gets != 1 {
+		t.Errorf("the bound PVC should be cached: gets=%d", c.gets)
+	}
+
+	for i := 0; i < 2; i++ {
+		if namespace, name := collector.getPVC(ctx, lv("uid-unbound", "pv-unbound")); namespace != "" || name != "" {
+			t.Fatalf("unexpected PVC: %s/%s", namespace, name)
+		}
+	}
+	if c.gets != 3 {
+		t.Errorf("unbound PVs should not be cached: gets=%d", c.gets)
+	}
+	if namespace, name := collector.getPVC(ctx, lv("uid-missing", "pv-missing")); namespace != "" || name != "" {
+		t.Fatalf("unexpected PVC: %s/%s", namespace, name)
+	}
+
+	collector.prunePVCs(map[types.UID]bool{})
+	collector.getPVC(ctx, lv("uid-bound", "pv-bound"))
+	if c.gets != 5 {
+		t.Errorf("the cache of removed LogicalVolumes should be pruned: gets=%d", c.gets)
+	}
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExplainResult is the response of /explain.
+type ExplainResult struct {
+	// Pod is the namespace and the name of the pod.
+	Pod string `json:"pod"`
+	// Requested is the requested bytes of each device-class or fallback group.
+	Requested map[string]int64 `json:"requested"`
+	// Fallbacks is the candidate device-classes of each fallback group.
+	Fallbacks map[string]Fallback `json:"fallbacks,omitempty"`
+	// Nodes is the decisions for each node.
+	Nodes []NodeExplanation `json:"nodes"`
+}
+
+// NodeExplanation represents the decision for a node.
+type NodeExplanation struct {
+	// Node is the name of the node.
+	Node string `json:"node"`
+	// DeviceClasses is the details of each requested device-class.
+	// Fallback groups are resolved into device-classes for the node.
+	DeviceClasses []DeviceClassExplanation `json:"deviceClasses"`
+	// Score is the score of the node given by prioritize.
+	Score int `json:"score"`
+	// Reason is the reason why the node is filtered out by predicate.
+	// It is empty if the node is not filtered out.
+	Reason string `json:"reason,omitempty"`
+}
+
+// DeviceClassExplanation represents the details of a device-class on a node.
+type DeviceClassExplanation struct {
+	// DeviceClass is the name of the device-class.
+	DeviceClass string `json:"deviceClass"`
+	// Requested is the requested bytes.
+	Requested int64 `json:"requested"`
+	// Capacity is the free bytes seen by the scheduler before the reserved bytes are subtracted.
+	// It is nil if the node has no capacity annotation.
+	Capacity *uint64 `json:"capacity,omitempty"`
+	// Reserved is the bytes reserved for in-flight LogicalVolumes.
+	Reserved int64 `json:"reserved"`
+	// Divisor is the divisor used for scoring.
+	Divisor float64 `json:"divisor"`
+	// Strategy is the scoring strategy.
+	Strategy StrategyType `json:"strategy"`
+	// Score is the score of the device-class.
+	Score int `json:"score"`
+}
+
+// explain returns the decisions of predicate and prioritize for the pod.
+//
+// The pod and the candidate nodes can be given by POST with the same body as predicate.
+// Alternatively, GET with "namespace" and "name" query parameters looks up the pod
+// and all nodes from the API server.
+func (s scheduler) explain(w http.ResponseWriter, r *http.Request) {
+	var pod *corev1.Pod
+	var nodes []corev1.Node
+
+	switch r.Method {
+	case http.MethodPost:
+		var input ExtenderArgs
+		reader := http.MaxBytesReader(w, r.Body, 10<<20)
+		err := json.NewDecoder(reader).Decode(&input)
+		if err != nil || input.Nodes == nil || input.Pod == nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		pod = input.Pod
+		nodes = input.Nodes.Items
+	case http.MethodGet:
+		if s.reader == nil {
+			http.Error(w, "pod lookup is not available", http.StatusNotImplemented)
+			return
+		}
+		namespace := r.URL.Query().Get("namespace")
+		name := r.URL.Query().Get("name")
+		if namespace == "" || name == "" {
+			http.Error(w, "namespace and name are required", http.StatusBadRequest)
+			return
+		}
+		pod = new(corev1.Pod)
+		err := s.reader.Get(r.Context(), client.ObjectKey{Namespace: namespace, Name: name}, pod)
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "pod not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var nl corev1.NodeList
+		if err := s.reader.List(r.Context(), &nl); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		nodes = nl.Items
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result := explainPod(pod, nodes, s.scorer)
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func explainPod(pod *corev1.Pod, nodes []corev1.Node, s Scorer) ExplainResult {
+	requested := ExtractRequestedSize(pod)
+	fallbacks := ExtractFallbacks(pod)
+
+	result := ExplainResult{
+		Pod:       podName(pod),
+		Requested: requested,
+		Fallbacks: fallbacks,
+		Nodes:     make([]NodeExplanation, 0, len(nodes)),
+	}
+	for i := range nodes {
+		node := &nodes[i]
+		resolved := ResolveFallbacks(node, requested, fallbacks)
+		dcs := sortedDeviceClasses(resolved)
+		ne := NodeExplanation{
+			Node:          node.Name,
+			DeviceClasses: make([]DeviceClassExplanation, 0, len(dcs)),
+			Reason:        filterNode(*node, resolved, s.thresholds).message,
+		}
+		scores := scoreDeviceClasses(*node, dcs, resolved, s)
+		ne.Score = scoreNode(*node, dcs, resolved, s)
+
+		for _, dc := range dcs {
+			de := DeviceClassExplanation{
+				DeviceClass: dc,
+				Requested:   resolved[dc],
+				Reserved:    reservation.FromAnnotation(node, dc),
+				Divisor:     s.divisor(dc),
+				Strategy:    s.strategy(dc).Type,
+				Score:       scores[dc],
+			}
+			if de.Strategy == "" {
+				de.Strategy = StrategyCapacity
+			}
+			if val, ok := node.Annotations[topolvm.GetCapacityKeyPrefix()+dc]; ok {
+				if capacity, err := strconv.ParseUint(val, 10, 64); err == nil {
+					de.Capacity = &capacity
+				}
+			}
+			ne.DeviceClasses = append(ne.DeviceClasses, de)
+		}
+		result.Nodes = append(result.Nodes, ne)
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Fallback represents the candidate device-classes of a fallback group.
+// The candidates are either listed in the order of preference, or selected
+// on each node by a label selector and sorted by name.
+type Fallback struct {
+	// DeviceClasses is the device-classes in the order of preference.
+	DeviceClasses []string `json:"deviceClasses,omitempty"`
+	// Selector is the label selector of device-classes.
+	Selector string `json:"selector,omitempty"`
+
+	selector labels.Selector
+}
+
+// candidates returns the candidate device-classes on the node.
+func (f Fallback) candidates(node *corev1.Node) []string {
+	if f.selector != nil {
+		return topolvm.SelectDeviceClasses(node, f.selector)
+	}
+	return f.DeviceClasses
+}
+
+// ExtractFallbacks returns the fallback groups from the annotations of the pod.
+// The requested capacity of a fallback group is given in the same way as a device-class.
+func ExtractFallbacks(pod *corev1.Pod) map[string]Fallback {
+	result := make(map[string]Fallback)
+	for k, v := range pod.Annotations {
+		switch {
+		case strings.HasPrefix(k, topolvm.GetDeviceClassesKeyPrefix()):
+			group := k[len(topolvm.GetDeviceClassesKeyPrefix()):]
+			result[group] = Fallback{DeviceClasses: strings.Split(v, ",")}
+		case strings.HasPrefix(k, topolvm.GetDeviceClassSelectorKeyPrefix()):
+			group := k[len(topolvm.GetDeviceClassSelectorKeyPrefix()):]
+			selector, err := labels.Parse(v)
+			if err != nil {
+				// the group is left unresolved, so no node fits it.
+				continue
+			}
+			result[group] = Fallback{Selector: v, selector: selector}
+		}
+	}
+	return result
+}
+
+// ResolveFallbacks returns the requested capacity of each device-class on the node.
+// Each fallback group is replaced with the first candidate device-class that has enough free space
+// for the group and the other requests.  If no candidate has enough space, the group is
+// replaced with the first candidate so that the node is filtered out.  If the node has
+// no candidate, the group is left as it is, which is not found in the node annotations.
+func ResolveFallbacks(node *corev1.Node, requested map[string]int64, fallbacks map[string]Fallback) map[string]int64 {
+	if len(fallbacks) == 0 {
+		return requested
+	}
+
+	resolved := make(map[string]int64)
+	var groups []string
+	for dc, size := range requested {
+		if _, ok := fallbacks[dc]; ok {
+			groups = append(groups, dc)
+			continue
+		}
+		resolved[dc] += size
+	}
+	// resolve groups in a fixed order to make results deterministic.
+	sort.Strings(groups)
+
+	for _, group := range groups {
+		dcs := fallbacks[group].candidates(node)
+		size := requested[group]
+		if len(dcs) == 0 {
+			resolved[group] += size
+			continue
+		}
+		selected := dcs[0]
+		for _, dc := range dcs {
+			if freeSpace(node, dc) >= uint64(resolved[dc]+size) {
+				selected = dc
+				break
+			}
+		}
+		resolved[selected] += size
+	}
+	return resolved
+}
+
+// freeSpace returns the free space of the device-class on the node minus the reserved space.
+func freeSpace(node *corev1.Node, dc string) uint64 {
+	val, ok := node.Annotations[topolvm.GetCapacityKeyPrefix()+dc]
+	if !ok {
+		return 0
+	}
+	capacity, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return subtractReserved(capacity, reservation.FromAnnotation(node, dc))
+}
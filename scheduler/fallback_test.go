@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestExtractFallbacks(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				topolvm.GetCapacityKeyPrefix() + "group":          "1073741824",
+				topolvm.GetDeviceClassesKeyPrefix() + "group":     "ssd,hdd1",
+				topolvm.GetDeviceClassSelectorKeyPrefix() + "sel": "media=hdd",
+				topolvm.GetDeviceClassSelectorKeyPrefix() + "bad": "media==(",
+			},
+		},
+	}
+	actual := ExtractFallbacks(pod)
+	if len(actual) != 2 {
+		t.Fatalf("unexpected fallbacks: %v", actual)
+	}
+	if !reflect.DeepEqual(actual["group"].DeviceClasses, []string{"ssd", "hdd1"}) {
+		t.Errorf("unexpected fallback: %v", actual["group"])
+	}
+	if actual["sel"].Selector != "media=hdd" || actual["sel"].selector == nil {
+		t.Errorf("unexpected fallback: %v", actual["sel"])
+	}
+}
+
+func testLabeledNode(name string, cap1Gb, cap2Gb, cap3Gb int64) corev1.Node {
+	node := testNode(name, cap1Gb, cap2Gb, cap3Gb)
+	node.Annotations[topolvm.GetDeviceClassLabelsKeyPrefix()+"ssd"] = "media=ssd"
+	node.Annotations[topolvm.GetDeviceClassLabelsKeyPrefix()+"hdd1"] = "media=hdd"
+	node.Annotations[topolvm.GetDeviceClassLabelsKeyPrefix()+"hdd2"] = "media=hdd"
+	return node
+}
+
+func TestResolveFallbacks(t *testing.T) {
+	fallbacks := map[string]Fallback{
+		"group": {DeviceClasses: []string{"ssd", "hdd1"}},
+		"sel":   {Selector: "media=hdd", selector: labels.SelectorFromSet(labels.Set{"media": "hdd"})},
+	}
+
+	testCases := []struct {
+		name      string
+		node      corev1.Node
+		requested map[string]int64
+		expected  map[string]int64
+	}{
+		{
+			name:      "first device-class",
+			node:      testNode("10.1.1.1", 10, 10, 10),
+			requested: map[string]int64{"group": 5 << 30},
+			expected:  map[string]int64{"ssd": 5 << 30},
+		},
+		{
+			name:      "fall back to the next device-class",
+			node:      testNode("10.1.1.1", 2, 10, 10),
+			requested: map[string]int64{"group": 5 << 30},
+			expected:  map[string]int64{"hdd1": 5 << 30},
+		},
+		{
+			name:      "fall back considering other requests",
+			node:      testNode("10.1.1.1", 10, 10, 10),
+			requested: map[string]int64{"group": 5 << 30, "ssd": 6 << 30},
+			expected:  map[string]int64{"ssd": 6 << 30, "hdd1": 5 << 30},
+		},
+		{
+			name:      "fall back considering reservations",
+			node:      testReservedNode("10.1.1.1", 10, 10, 10, 8),
+			requested: map[string]int64{"group": 5 << 30},
+			expected:  map[string]int64{"hdd1": 5 << 30},
+		},
+		{
+			name:      "no device-class has enough space",
+			node:      testNode("10.1.1.1", 2, 2, 10),
+			requested: map[string]int64{"group": 5 << 30},
+			expected:  map[string]int64{"ssd": 5 << 30},
+		},
+		{
+			name:      "selected device-classes in name order",
+			node:      testLabeledNode("10.1.1.1", 10, 10, 10),
+			requested: map[string]int64{"sel": 5 << 30},
+			expected:  map[string]int64{"hdd1": 5 << 30},
+		},
+		{
+			name:      "fall back to the next selected device-class",
+			node:      testLabeledNode("10.1.1.1", 10, 2, 10),
+			requested: map[string]int64{"sel": 5 << 30},
+			expected:  map[string]int64{"hdd2": 5 << 30},
+		},
+		{
+			name:      "no device-class is selected",
+			node:      testNode("10.1.1.1", 10, 10, 10),
+			requested: map[string]int64{"sel": 5 << 30},
+			expected:  map[string]int64{"sel": 5 << 30},
+		},
+	}
+
+	for _, tc := range testCases {
+		actual := ResolveFallbacks(&tc.node, tc.requested, fallbacks)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%s: expected=%v actual=%v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestFilterNodesWithFallbacks(t *testing.T) {
+	nodes := corev1.NodeList{
+		Items: []corev1.Node{
+			testNode("10.1.1.1", 2, 10, 10),
+			testNode("10.1.1.2", 2, 2, 10),
+		},
+	}
+	requested := map[string]int64{"group": 5 << 30}
+	fallbacks := map[string]Fallback{"group": {DeviceClasses: []string{"ssd", "hdd1"}}}
+
+	result := filterNodes(nil, nodes, requested, fallbacks, ThinPoolThresholds{})
+	if len(result.Nodes.Items) != 1 || result.Nodes.Items[0].Name != "10.1.1.1" {
+		t.Errorf("wrong result.Nodes: %#v", result.Nodes)
+	}
+	if result.FailedNodes["10.1.1.2"] != "out of VG free space" {
+		t.Errorf("wrong result.FailedNodes: %#v", result.FailedNodes)
+	}
+}
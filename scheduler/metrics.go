@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const metricsNamespace = "topolvm"
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "scheduler",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the scheduler extender requests",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+	}, []string{"verb"})
+
+	rejectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "scheduler",
+		Name:      "rejections_total",
+		Help:      "The number of nodes filtered out by the scheduler extender",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(requestDuration, rejectionsTotal)
+}
+
+func observeDuration(verb string, start time.Time) {
+	requestDuration.WithLabelValues(verb).Observe(time.Since(start).Seconds())
+}
+
+func podName(pod *corev1.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	return pod.Namespace + "/" + pod.Name
+}
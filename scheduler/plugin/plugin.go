@@ -0,0 +1,327 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
+	"github.com/topolvm/topolvm/scheduler"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+)
+
+// Name is the name of the plugin used in the plugin registry and configurations.
+const Name = "TopoLVM"
+
+const stateKey framework.StateKey = Name
+
+// boundReservationTTL is how long the capacity is held after the pod is bound
+// if the node annotations are not updated for the LogicalVolume.
+const boundReservationTTL = 5 * time.Minute
+
+// Args holds the arguments of the plugin.
+// The fields are the same as the config file of topolvm-scheduler.
+type Args struct {
+	// Divisors is a mapping between device-class names and their divisors.
+	Divisors map[string]float64 `json:"divisors"`
+	// DefaultDivisor is the default divisor value.
+	DefaultDivisor float64 `json:"default-divisor"`
+	// Strategies is a mapping between device-class names and their scoring strategies.
+	Strategies map[string]scheduler.Strategy `json:"strategies"`
+	// DefaultStrategy is the default scoring strategy.
+	DefaultStrategy scheduler.Strategy `json:"default-strategy"`
+	// ThinPoolThresholds is the limits of the physical usage of thin pools.
+	ThinPoolThresholds scheduler.ThinPoolThresholds `json:"thin-pool-thresholds"`
+}
+
+// Plugin is a kube-scheduler framework plugin for TopoLVM.
+// It filters and scores nodes in the same way as topolvm-scheduler,
+// and holds the requested capacity on the selected node until the LogicalVolumes are
+// reflected in the node annotations.
+type Plugin struct {
+	handle     framework.Handle
+	scorer     scheduler.Scorer
+	thresholds scheduler.ThinPoolThresholds
+	now        func() time.Time
+
+	mu sync.Mutex
+	// reservations holds the capacity reserved for pods whose LogicalVolumes may not exist yet.
+	reservations map[types.UID]*podReservation
+}
+
+type podReservation struct {
+	nodeName  string
+	requested map[string]int64
+	// annotations are the capacity and reservation annotations of the node for the
+	// requested device-classes at the time of Reserve.
+	annotations map[string]string
+	// expiry is set when the pod is bound.
+	expiry time.Time
+}
+
+var _ framework.PreFilterPlugin = &Plugin{}
+var _ framework.FilterPlugin = &Plugin{}
+var _ framework.ScorePlugin = &Plugin{}
+var _ framework.ReservePlugin = &Plugin{}
+var _ framework.PostBindPlugin = &Plugin{}
+
+// New creates the plugin.
+func New(obj runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	args := Args{
+		DefaultDivisor: 1,
+	}
+	if err := frameworkruntime.DecodeInto(obj, &args); err != nil {
+		return nil, err
+	}
+	scorer, err := scheduler.NewScorer(args.DefaultDivisor, args.Divisors, args.DefaultStrategy, args.Strategies, args.ThinPoolThresholds)
+	if err != nil {
+		return nil, err
+	}
+	return &Plugin{
+		handle:       h,
+		scorer:       scorer,
+		thresholds:   args.ThinPoolThresholds,
+		now:          time.Now,
+		reservations: make(map[types.UID]*podReservation),
+	}, nil
+}
+
+// Name implements framework.Plugin interface.
+func (p *Plugin) Name() string {
+	return Name
+}
+
+// stateData holds the requested capacity of each device-class and the fallback groups.
+type stateData struct {
+	requested map[string]int64
+	fallbacks map[string]scheduler.Fallback
+}
+
+// Clone implements framework.StateData interface.
+// The requested capacity is never modified after PreFilter.
+func (d *stateData) Clone() framework.StateData {
+	return d
+}
+
+func getStateData(state *framework.CycleState) (*stateData, error) {
+	c, err := state.Read(stateKey)
+	if err != nil {
+		return nil, err
+	}
+	d, ok := c.(*stateData)
+	if !ok {
+		return nil, fmt.Errorf("invalid state data: %T", c)
+	}
+	return d, nil
+}
+
+// PreFilter implements framework.PreFilterPlugin interface.
+func (p *Plugin) PreFilter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod) (*framework.PreFilterResult, *framework.Status) {
+	state.Write(stateKey, &stateData{
+		requested: scheduler.ExtractRequestedSize(pod),
+		fallbacks: scheduler.ExtractFallbacks(pod),
+	})
+	return nil, nil
+}
+
+// PreFilterExtensions implements framework.PreFilterPlugin interface.
+func (p *Plugin) PreFilterExtensions() framework.PreFilterExtensions {
+	return nil
+}
+
+// Filter implements framework.FilterPlugin interface.
+func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	d, err := getStateData(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if len(d.requested) == 0 {
+		return nil
+	}
+
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	node = p.withReservations(node, pod.UID)
+	requested := scheduler.ResolveFallbacks(node, d.requested, d.fallbacks)
+	if reason := scheduler.FilterNode(*node, requested, p.thresholds); reason != "" {
+		return framework.NewStatus(framework.Unschedulable, reason)
+	}
+	return nil
+}
+
+// Score implements framework.ScorePlugin interface.
+func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) (int64, *framework.Status) {
+	d, err := getStateData(state)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	if len(d.requested) == 0 {
+		return 0, nil
+	}
+
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.AsStatus(err)
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return 0, framework.NewStatus(framework.Error, "node not found")
+	}
+
+	// topolvm-scheduler scores nodes between 0 and 10.
+	node = p.withReservations(node, pod.UID)
+	score := p.scorer.ScoreNode(*node, scheduler.ResolveFallbacks(node, d.requested, d.fallbacks))
+	return int64(score) * framework.MaxNodeScore / 10, nil
+}
+
+// ScoreExtensions implements framework.ScorePlugin interface.
+func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// Reserve implements framework.ReservePlugin interface.
+func (p *Plugin) Reserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	d, err := getStateData(state)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	if len(d.requested) == 0 {
+		return nil
+	}
+
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return framework.AsStatus(err)
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+
+	// fallback groups are held as the device-classes selected for the node.
+	requested := d.requested
+	if len(d.fallbacks) != 0 {
+		requested = scheduler.ResolveFallbacks(p.withReservations(node, pod.UID), d.requested, d.fallbacks)
+	}
+
+	annotations := make(map[string]string)
+	for dc := range requested {
+		for _, key := range reservationWatchKeys(dc) {
+			annotations[key] = node.Annotations[key]
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reservations[pod.UID] = &podReservation{
+		nodeName:    nodeName,
+		requested:   requested,
+		annotations: annotations,
+	}
+	return nil
+}
+
+// reservationWatchKeys returns the node annotation keys updated when a LogicalVolume of the device-class
+// is created: the reservation by topolvm-controller and the capacity by topolvm-node.
+func reservationWatchKeys(dc string) []string {
+	return []string{
+		topolvm.GetReservedKeyPrefix() + dc,
+		topolvm.GetCapacityKeyPrefix() + dc,
+	}
+}
+
+// Unreserve implements framework.ReservePlugin interface.
+func (p *Plugin) Unreserve(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	p.release(pod.UID)
+}
+
+// PostBind implements framework.PostBindPlugin interface.
+// The LogicalVolumes may not be created by topolvm-controller yet when the pod is bound,
+// so the capacity is held until the node annotations of the device-class are updated,
+// or until boundReservationTTL expires.
+func (p *Plugin) PostBind(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r, ok := p.reservations[pod.UID]; ok {
+		r.expiry = p.now().Add(boundReservationTTL)
+	}
+}
+
+func (p *Plugin) release(uid types.UID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.reservations, uid)
+}
+
+// withReservations returns a copy of the node whose reservation annotations include
+// the capacity reserved for other pods by this plugin.
+func (p *Plugin) withReservations(node *corev1.Node, exclude types.UID) *corev1.Node {
+	p.mu.Lock()
+	p.expireReservations(node)
+	reserved := make(map[string]int64)
+	for uid, r := range p.reservations {
+		if uid == exclude || r.nodeName != node.Name {
+			continue
+		}
+		for dc, size := range r.requested {
+			reserved[dc] += size
+		}
+	}
+	p.mu.Unlock()
+
+	if len(reserved) == 0 {
+		return node
+	}
+
+	node = node.DeepCopy()
+	if node.Annotations == nil {
+		node.Annotations = make(map[string]string)
+	}
+	for dc, size := range reserved {
+		size += reservation.FromAnnotation(node, dc)
+		node.Annotations[topolvm.GetReservedKeyPrefix()+dc] = strconv.FormatInt(size, 10)
+	}
+	return node
+}
+
+// expireReservations releases the capacity held for bound pods on the node
+// once the LogicalVolumes are reflected in the node annotations.
+// p.mu must be held by the caller.
+func (p *Plugin) expireReservations(node *corev1.Node) {
+	now := p.now()
+	for uid, r := range p.reservations {
+		if r.expiry.IsZero() || r.nodeName != node.Name {
+			continue
+		}
+		if now.After(r.expiry) {
+			delete(p.reservations, uid)
+			continue
+		}
+
+		requested := make(map[string]int64)
+		for dc, size := range r.requested {
+			for _, key := range reservationWatchKeys(dc) {
+				if node.Annotations[key] != r.annotations[key] {
+					size = 0
+				}
+			}
+			if size > 0 {
+				requested[dc] = size
+			}
+		}
+		if len(requested) == 0 {
+			delete(p.reservations, uid)
+			continue
+		}
+		r.requested = requested
+	}
+}
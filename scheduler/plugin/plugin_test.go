@@ -0,0 +1,228 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+type fakeNodeInfoLister struct {
+	framework.NodeInfoLister
+	nodes map[string]*framework.NodeInfo
+}
+
+func (l fakeNodeInfoLister) Get(nodeName string) (*framework.NodeInfo, error) {
+	n, ok := l.nodes[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("node %s not found", nodeName)
+	}
+	return n, nil
+}
+
+type fakeSharedLister struct {
+	framework.SharedLister
+	nodeInfos fakeNodeInfoLister
+}
+
+func (l fakeSharedLister) NodeInfos() framework.NodeInfoLister {
+	return l.nodeInfos
+}
+
+type fakeHandle struct {
+	framework.Handle
+	lister fakeSharedLister
+}
+
+func (h fakeHandle) SnapshotSharedLister() framework.SharedLister {
+	return h.lister
+}
+
+func testNodeInfo(name string, capacityGb int64) *framework.NodeInfo {
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Annotations: map[string]string{
+				topolvm.GetCapacityKeyPrefix() + "ssd": fmt.Sprintf("%d", capacityGb<<30),
+			},
+		},
+	})
+	return nodeInfo
+}
+
+func testPod(uid string, requestedGb int64) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: types.UID(uid),
+		},
+	}
+	if requestedGb > 0 {
+		pod.Annotations = map[string]string{
+			topolvm.GetCapacityKeyPrefix() + "ssd": fmt.Sprintf("%d", requestedGb<<30),
+		}
+	}
+	return pod
+}
+
+func newTestPlugin(t *testing.T, nodes ...*framework.NodeInfo) *Plugin {
+	lister := fakeNodeInfoLister{nodes: make(map[string]*framework.NodeInfo)}
+	for _, n := range nodes {
+		lister.nodes[n.Node().Name] = n
+	}
+	p, err := New(nil, fakeHandle{lister: fakeSharedLister{nodeInfos: lister}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*Plugin)
+}
+
+func preFilter(t *testing.T, p *Plugin, pod *corev1.Pod) *framework.CycleState {
+	state := framework.NewCycleState()
+	_, status := p.PreFilter(context.Background(), state, pod)
+	if !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+	return state
+}
+
+func TestFilterWithReservation(t *testing.T) {
+	ctx := context.Background()
+	node := testNodeInfo("node1", 5)
+	p := newTestPlugin(t, node)
+
+	pod1 := testPod("pod1", 3)
+	state1 := preFilter(t, p, pod1)
+	if status := p.Filter(ctx, state1, pod1, node); !status.IsSuccess() {
+		t.Fatal("pod1 should fit:", status.AsError())
+	}
+	if status := p.Reserve(ctx, state1, pod1, "node1"); !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+
+	pod2 := testPod("pod2", 3)
+	state2 := preFilter(t, p, pod2)
+	status := p.Filter(ctx, state2, pod2, node)
+	if status.Code() != framework.Unschedulable {
+		t.Error("pod2 should not fit while pod1 is reserved:", status)
+	}
+
+	// the reservation of the pod itself must not be counted.
+	if status := p.Filter(ctx, state1, pod1, node); !status.IsSuccess() {
+		t.Error("pod1 should still fit:", status.AsError())
+	}
+
+	p.Unreserve(ctx, state1, pod1, "node1")
+	if status := p.Filter(ctx, state2, pod2, node); !status.IsSuccess() {
+		t.Error("pod2 should fit after pod1 is unreserved:", status.AsError())
+	}
+
+	if status := p.Reserve(ctx, state2, pod2, "node1"); !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+	p.PostBind(ctx, state2, pod2, "node1")
+	if len(p.reservations) != 1 {
+		t.Error("reservations should be kept after binding:", p.reservations)
+	}
+
+	pod3 := testPod("pod3", 0)
+	state3 := preFilter(t, p, pod3)
+	if status := p.Filter(ctx, state3, pod3, framework.NewNodeInfo()); !status.IsSuccess() {
+		t.Error("pod without TopoLVM volumes should not be filtered:", status.AsError())
+	}
+}
+
+func TestScoreWithReservation(t *testing.T) {
+	ctx := context.Background()
+	p := newTestPlugin(t, testNodeInfo("node1", 128), testNodeInfo("node2", 128))
+
+	pod1 := testPod("pod1", 64)
+	state1 := preFilter(t, p, pod1)
+	score, status := p.Score(ctx, state1, pod1, "node1")
+	if !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+	// log2(128) => 7
+	if score != 70 {
+		t.Error("unexpected score:", score)
+	}
+	if status := p.Reserve(ctx, state1, pod1, "node1"); !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+
+	pod2 := testPod("pod2", 1)
+	state2 := preFilter(t, p, pod2)
+	score, status = p.Score(ctx, state2, pod2, "node1")
+	if !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+	// log2(128 - 64) => 6
+	if score != 60 {
+		t.Error("reserved capacity should be subtracted:", score)
+	}
+	score, status = p.Score(ctx, state2, pod2, "node2")
+	if !status.IsSuccess() {
+		t.Fatal(status.AsError())
+	}
+	if score != 70 {
+		t.Error("reservation on another node should not be subtracted:", score)
+	}
+
+	pod3 := testPod("pod3", 0)
+	state3 := preFilter(t, p, pod3)
+	score, status = p.Score(ctx, state3, pod3, "node1")
+	if !status.IsSuccess() || score != 0 {
+		t.Error("pod without TopoLVM volumes should be scored 0:", score, status)
+	}
+}
+
+func TestReservationAfterBinding(t *testing.T) {
+	ctx := context.Background()
+	node := testNodeInfo("node1", 5)
+	p := newTestPlugin(t, node)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	reserve := func(uid string) *framework.CycleState {
+		pod := testPod(uid, 3)
+		state := preFilter(t, p, pod)
+		if status := p.Reserve(ctx, state, pod, "node1"); !status.IsSuccess() {
+			t.Fatal(status.AsError())
+		}
+		p.PostBind(ctx, state, pod, "node1")
+		return state
+	}
+	filter := func(uid string) *framework.Status {
+		pod := testPod(uid, 3)
+		return p.Filter(ctx, preFilter(t, p, pod), pod, node)
+	}
+
+	// the capacity is held until the LogicalVolume is reserved by topolvm-controller.
+	reserve("pod1")
+	if status := filter("pod2"); status.Code() != framework.Unschedulable {
+		t.Error("pod2 should not fit while pod1 is bound but LogicalVolume is not created:", status)
+	}
+	node.Node().Annotations[topolvm.GetReservedKeyPrefix()+"ssd"] = fmt.Sprintf("%d", 3<<30)
+	if status := filter("pod2"); status.Code() != framework.Unschedulable {
+		t.Error("pod2 should not fit while LogicalVolume of pod1 is reserved:", status)
+	}
+	if len(p.reservations) != 0 {
+		t.Error("reservation should be released after the node annotation is updated:", p.reservations)
+	}
+
+	// the capacity is released after the TTL.
+	node = testNodeInfo("node1", 5)
+	p = newTestPlugin(t, node)
+	p.now = func() time.Time { return now }
+	reserve("pod1")
+	now = now.Add(boundReservationTTL + time.Second)
+	if status := filter("pod2"); !status.IsSuccess() {
+		t.Error("pod2 should fit after the reservation of pod1 expires:", status.AsError())
+	}
+}
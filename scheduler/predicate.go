@@ -6,26 +6,29 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/cybozu-go/log"
 	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func filterNodes(nodes corev1.NodeList, requested map[string]int64) ExtenderFilterResult {
+func filterNodes(pod *corev1.Pod, nodes corev1.NodeList, requested map[string]int64, fallbacks map[string]Fallback, thresholds ThinPoolThresholds) ExtenderFilterResult {
 	if len(requested) == 0 {
 		return ExtenderFilterResult{
 			Nodes: &nodes,
 		}
 	}
 
-	failedNodes := make([]string, len(nodes.Items))
+	rejections := make([]rejection, len(nodes.Items))
 	wg := &sync.WaitGroup{}
 	wg.Add(len(nodes.Items))
 	for i := range nodes.Items {
-		reason := &failedNodes[i]
+		r := &rejections[i]
 		node := nodes.Items[i]
 		go func() {
-			*reason = filterNode(node, requested)
+			*r = filterNode(node, ResolveFallbacks(&node, requested, fallbacks), thresholds)
 			wg.Done()
 		}()
 	}
@@ -34,34 +37,77 @@ func filterNodes(nodes corev1.NodeList, requested map[string]int64) ExtenderFilt
 		Nodes:       &corev1.NodeList{},
 		FailedNodes: FailedNodesMap{},
 	}
-	for i, reason := range failedNodes {
-		if len(reason) == 0 {
+	for i, r := range rejections {
+		if len(r.message) == 0 {
 			result.Nodes.Items = append(result.Nodes.Items, nodes.Items[i])
 		} else {
-			result.FailedNodes[nodes.Items[i].Name] = reason
+			result.FailedNodes[nodes.Items[i].Name] = r.message
+			rejectionsTotal.WithLabelValues(r.reason).Inc()
 		}
+		log.Debug("topolvm-scheduler: filtered node", map[string]interface{}{
+			"pod":       podName(pod),
+			"node":      nodes.Items[i].Name,
+			"requested": requested,
+			"rejected":  r.message,
+		})
 	}
 	return result
 }
 
-func filterNode(node corev1.Node, requested map[string]int64) string {
+// Reasons of rejections used as the label of the metrics.
+const (
+	reasonNoCapacityAnnotation  = "no-capacity-annotation"
+	reasonBadCapacityAnnotation = "bad-capacity-annotation"
+	reasonOutOfFreeSpace        = "out-of-free-space"
+	reasonThinPoolData          = "thin-pool-data"
+	reasonThinPoolMetadata      = "thin-pool-metadata"
+)
+
+// rejection represents why a node is filtered out.
+// It is empty if the node is not filtered out.
+type rejection struct {
+	reason  string
+	message string
+}
+
+// FilterNode returns the reason why the node cannot satisfy the requested capacity of each device-class.
+// It returns an empty string if the node can satisfy it.
+// Nodes whose thin pools exceed the thresholds are filtered out as well.
+func FilterNode(node corev1.Node, requested map[string]int64, thresholds ThinPoolThresholds) string {
+	return filterNode(node, requested, thresholds).message
+}
+
+func filterNode(node corev1.Node, requested map[string]int64, thresholds ThinPoolThresholds) rejection {
 	for dc, required := range requested {
 		val, ok := node.Annotations[topolvm.GetCapacityKeyPrefix()+dc]
 		if !ok {
-			return "no capacity annotation"
+			return rejection{reasonNoCapacityAnnotation, "no capacity annotation"}
 		}
 		capacity, err := strconv.ParseUint(val, 10, 64)
 		if err != nil {
-			return "bad capacity annotation: " + val
+			return rejection{reasonBadCapacityAnnotation, "bad capacity annotation: " + val}
 		}
+		capacity = subtractReserved(capacity, reservation.FromAnnotation(&node, dc))
 		if capacity < uint64(required) {
-			return "out of VG free space"
+			return rejection{reasonOutOfFreeSpace, "out of VG free space"}
+		}
+		if r := thresholds.check(getThinPoolPercents(&node, dc)); r.message != "" {
+			return r
 		}
 	}
-	return ""
+	return rejection{}
 }
 
-func extractRequestedSize(pod *corev1.Pod) map[string]int64 {
+// subtractReserved returns the capacity minus the bytes reserved for in-flight LogicalVolumes.
+func subtractReserved(capacity uint64, reserved int64) uint64 {
+	if uint64(reserved) >= capacity {
+		return 0
+	}
+	return capacity - uint64(reserved)
+}
+
+// ExtractRequestedSize returns the requested capacity of each device-class from the annotations of the pod.
+func ExtractRequestedSize(pod *corev1.Pod) map[string]int64 {
 	result := make(map[string]int64)
 	for k, v := range pod.Annotations {
 		if !strings.HasPrefix(k, topolvm.GetCapacityKeyPrefix()) {
@@ -79,6 +125,8 @@ func extractRequestedSize(pod *corev1.Pod) map[string]int64 {
 }
 
 func (s scheduler) predicate(w http.ResponseWriter, r *http.Request) {
+	defer observeDuration("predicate", time.Now())
+
 	var input ExtenderArgs
 
 	reader := http.MaxBytesReader(w, r.Body, 10<<20)
@@ -88,8 +136,8 @@ func (s scheduler) predicate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	requested := extractRequestedSize(input.Pod)
-	result := filterNodes(*input.Nodes, requested)
+	requested := ExtractRequestedSize(input.Pod)
+	result := filterNodes(input.Pod, *input.Nodes, requested, ExtractFallbacks(input.Pod), s.scorer.thresholds)
 	w.Header().Set("content-type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
@@ -25,6 +25,12 @@ func testNode(name string, cap1Gb, cap2Gb, cap3Gb int64) corev1.Node {
 	}
 }
 
+func testReservedNode(name string, cap1Gb, cap2Gb, cap3Gb, reserved1Gb int64) corev1.Node {
+	node := testNode(name, cap1Gb, cap2Gb, cap3Gb)
+	node.Annotations[topolvm.GetReservedKeyPrefix()+"ssd"] = fmt.Sprintf("%d", reserved1Gb<<30)
+	return node
+}
+
 func TestFilterNodes(t *testing.T) {
 	testCases := []struct {
 		nodes     corev1.NodeList
@@ -92,6 +98,29 @@ func TestFilterNodes(t *testing.T) {
 				},
 			},
 		},
+		{
+			nodes: corev1.NodeList{
+				Items: []corev1.Node{
+					testReservedNode("10.1.1.1", 5, 10, 10, 2),
+					testReservedNode("10.1.1.2", 5, 10, 10, 4),
+					testReservedNode("10.1.1.3", 5, 10, 10, 10),
+				},
+			},
+			requested: map[string]int64{
+				"ssd": 3 << 30,
+			},
+			expect: ExtenderFilterResult{
+				Nodes: &corev1.NodeList{
+					Items: []corev1.Node{
+						testReservedNode("10.1.1.1", 5, 10, 10, 2),
+					},
+				},
+				FailedNodes: FailedNodesMap{
+					"10.1.1.2": "out of VG free space",
+					"10.1.1.3": "out of VG free space",
+				},
+			},
+		},
 		{
 			nodes: corev1.NodeList{
 				Items: []corev1.Node{
@@ -113,7 +142,7 @@ func TestFilterNodes(t *testing.T) {
 	}
 
 	for _, tt := range testCases {
-		result := filterNodes(tt.nodes, tt.requested)
+		result := filterNodes(nil, tt.nodes, tt.requested, nil, ThinPoolThresholds{})
 		if len(result.Nodes.Items) != len(tt.expect.Nodes.Items) {
 			t.Fatalf("not match length of filtered NodeList: expect=%d actual=%d", len(tt.expect.Nodes.Items), len(result.Nodes.Items))
 		}
@@ -197,11 +226,11 @@ func TestExtractRequestedSize(t *testing.T) {
 	}
 
 	for _, tt := range testCases {
-		result := extractRequestedSize(tt.input)
+		result := ExtractRequestedSize(tt.input)
 		for vg, cap := range tt.expected {
 			if v, ok := result[vg]; ok {
 				if v != cap {
-					t.Errorf("expected extractRequestedSize() to be %d, but actual %d", cap, v)
+					t.Errorf("expected ExtractRequestedSize() to be %d, but actual %d", cap, v)
 				}
 			} else {
 				t.Errorf("clould not find target: %v", result)
@@ -4,11 +4,11 @@ import (
 	"encoding/json"
 	"math"
 	"net/http"
-	"strconv"
-	"strings"
+	"sort"
 	"sync"
+	"time"
 
-	"github.com/topolvm/topolvm"
+	"github.com/cybozu-go/log"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -31,16 +31,12 @@ func capacityToScore(capacity uint64, divisor float64) int {
 	}
 }
 
-func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, defaultDivisor float64, divisors map[string]float64) []HostPriority {
-	var dcs []string
-	for k := range pod.Annotations {
-		if strings.HasPrefix(k, topolvm.GetCapacityKeyPrefix()) {
-			dcs = append(dcs, k[len(topolvm.GetCapacityKeyPrefix()):])
-		}
-	}
-	if len(dcs) == 0 {
+func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, s Scorer) []HostPriority {
+	requested := ExtractRequestedSize(pod)
+	if len(requested) == 0 {
 		return nil
 	}
+	fallbacks := ExtractFallbacks(pod)
 
 	result := make([]HostPriority, len(nodes))
 	wg := &sync.WaitGroup{}
@@ -49,31 +45,49 @@ func scoreNodes(pod *corev1.Pod, nodes []corev1.Node, defaultDivisor float64, di
 		r := &result[i]
 		item := nodes[i]
 		go func() {
-			score := scoreNode(item, dcs, defaultDivisor, divisors)
+			resolved := ResolveFallbacks(&item, requested, fallbacks)
+			score := scoreNode(item, sortedDeviceClasses(resolved), resolved, s)
 			*r = HostPriority{Host: item.Name, Score: score}
 			wg.Done()
 		}()
 	}
 	wg.Wait()
 
+	for _, r := range result {
+		log.Debug("topolvm-scheduler: scored node", map[string]interface{}{
+			"pod":       podName(pod),
+			"node":      r.Host,
+			"requested": requested,
+			"score":     r.Score,
+		})
+	}
+
 	return result
 }
 
-func scoreNode(item corev1.Node, deviceClasses []string, defaultDivisor float64, divisors map[string]float64) int {
+// sortedDeviceClasses returns the sorted device-classes to make scores deterministic.
+func sortedDeviceClasses(requested map[string]int64) []string {
+	dcs := make([]string, 0, len(requested))
+	for dc := range requested {
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+	return dcs
+}
+
+// ScoreNode returns the score of the node between 0 and 10 for the requested capacity of each device-class.
+func (s Scorer) ScoreNode(item corev1.Node, requested map[string]int64) int {
+	if len(requested) == 0 {
+		return 0
+	}
+	return scoreNode(item, sortedDeviceClasses(requested), requested, s)
+}
+
+func scoreNode(item corev1.Node, deviceClasses []string, requested map[string]int64, s Scorer) int {
 	minScore := math.MaxInt32
-	for _, dc := range deviceClasses {
-		if val, ok := item.Annotations[topolvm.GetCapacityKeyPrefix()+dc]; ok {
-			capacity, _ := strconv.ParseUint(val, 10, 64)
-			var divisor float64
-			if v, ok := divisors[dc]; ok {
-				divisor = v
-			} else {
-				divisor = defaultDivisor
-			}
-			score := capacityToScore(capacity, divisor)
-			if score < minScore {
-				minScore = score
-			}
+	for _, score := range scoreDeviceClasses(item, deviceClasses, requested, s) {
+		if score < minScore {
+			minScore = score
 		}
 	}
 	if minScore == math.MaxInt32 {
@@ -82,7 +96,39 @@ func scoreNode(item corev1.Node, deviceClasses []string, defaultDivisor float64,
 	return minScore
 }
 
+// scoreDeviceClasses returns the scores of the device-classes on the node.
+// Device-classes without capacity annotations are omitted.
+func scoreDeviceClasses(item corev1.Node, deviceClasses []string, requested map[string]int64, s Scorer) map[string]int {
+	stats := make(map[string]deviceClassStats)
+	var sumAllocated float64
+	var numAllocated int
+	for _, dc := range deviceClasses {
+		st, ok := getDeviceClassStats(&item, dc, requested[dc])
+		if !ok {
+			continue
+		}
+		stats[dc] = st
+		if st.size != 0 {
+			sumAllocated += st.allocated
+			numAllocated++
+		}
+	}
+	var meanAllocated float64
+	if numAllocated != 0 {
+		meanAllocated = sumAllocated / float64(numAllocated)
+	}
+
+	scores := make(map[string]int)
+	for dc, st := range stats {
+		score := scoreDeviceClass(st, s.strategy(dc), s.divisor(dc), meanAllocated)
+		scores[dc] = s.thresholds.penalize(score, st.dataPercent, st.metadataPercent)
+	}
+	return scores
+}
+
 func (s scheduler) prioritize(w http.ResponseWriter, r *http.Request) {
+	defer observeDuration("prioritize", time.Now())
+
 	var input ExtenderArgs
 
 	reader := http.MaxBytesReader(w, r.Body, 10<<20)
@@ -92,7 +138,7 @@ func (s scheduler) prioritize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := scoreNodes(input.Pod, input.Nodes.Items, s.defaultDivisor, s.divisors)
+	result := scoreNodes(input.Pod, input.Nodes.Items, s.scorer)
 
 	w.Header().Set("content-type", "application/json")
 	json.NewEncoder(w).Encode(result)
@@ -60,6 +60,7 @@ func TestScoreNodes(t *testing.T) {
 				},
 			},
 		},
+		testReservedNode("10.1.1.4", 128, 128, 128, 120),
 	}
 	expected := []HostPriority{
 		{
@@ -74,6 +75,10 @@ func TestScoreNodes(t *testing.T) {
 			Host:  "10.1.1.3",
 			Score: 0,
 		},
+		{
+			Host:  "10.1.1.4",
+			Score: 1,
+		},
 	}
 
 	defaultDivisor := 2.0
@@ -81,7 +86,7 @@ func TestScoreNodes(t *testing.T) {
 		"ssd":  4,
 		"hdd1": 10,
 	}
-	result := scoreNodes(pod, input, defaultDivisor, divisors)
+	result := scoreNodes(pod, input, Scorer{defaultDivisor: defaultDivisor, divisors: divisors})
 	if !reflect.DeepEqual(result, expected) {
 		t.Errorf("expected scoreNodes() to be %#v, but actual %#v", expected, result)
 	}
@@ -1,13 +1,16 @@
 package scheduler
 
 import (
-	"fmt"
 	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type scheduler struct {
-	defaultDivisor float64
-	divisors       map[string]float64
+	scorer Scorer
+	// reader is used to look up pods and nodes for /explain.  It may be nil.
+	reader client.Reader
 }
 
 func (s scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -16,6 +19,10 @@ func (s scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.predicate(w, r)
 	case "/prioritize":
 		s.prioritize(w, r)
+	case "/explain":
+		s.explain(w, r)
+	case "/metrics":
+		promhttp.Handler().ServeHTTP(w, r)
 	case "/status":
 		status(w, r)
 	default:
@@ -24,13 +31,13 @@ func (s scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // NewHandler return new http.Handler of the scheduler extender
-func NewHandler(defaultDiv float64, divisors map[string]float64) (http.Handler, error) {
-	for _, divisor := range divisors {
-		if divisor <= 0 {
-			return nil, fmt.Errorf("invalid divisor: %f", divisor)
-		}
+// The reader is used to look up pods by /explain.  If it is nil, pods should be given in the request body.
+func NewHandler(defaultDiv float64, divisors map[string]float64, defaultStrategy Strategy, strategies map[string]Strategy, thresholds ThinPoolThresholds, reader client.Reader) (http.Handler, error) {
+	scorer, err := NewScorer(defaultDiv, divisors, defaultStrategy, strategies, thresholds)
+	if err != nil {
+		return nil, err
 	}
-	return scheduler{defaultDiv, divisors}, nil
+	return scheduler{scorer: scorer, reader: reader}, nil
 }
 
 func status(w http.ResponseWriter, r *http.Request) {
@@ -13,6 +13,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var extenderArgs = ExtenderArgs{
@@ -47,7 +49,7 @@ func testPredicate(t *testing.T) {
 
 	handler, err := NewHandler(1, map[string]float64{
 		"ssd": 1,
-	})
+	}, Strategy{}, nil, ThinPoolThresholds{}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -94,7 +96,7 @@ func testPrioritize(t *testing.T) {
 
 	handler, err := NewHandler(1, map[string]float64{
 		"ssd": 1,
-	})
+	}, Strategy{}, nil, ThinPoolThresholds{}, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -143,7 +145,80 @@ func testPrioritize(t *testing.T) {
 	}
 }
 
+func testExplain(t *testing.T) {
+	t.Parallel()
+
+	pod := extenderArgs.Pod.DeepCopy()
+	pod.Namespace = "default"
+	pod.Name = "explain"
+	objs := []client.Object{pod}
+	for i := range extenderArgs.Nodes.Items {
+		objs = append(objs, extenderArgs.Nodes.Items[i].DeepCopy())
+	}
+	reader := fake.NewClientBuilder().WithObjects(objs...).Build()
+
+	handler, err := NewHandler(1, map[string]float64{
+		"ssd": 1,
+	}, Strategy{}, nil, ThinPoolThresholds{}, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := json.Marshal(extenderArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []*http.Request{
+		httptest.NewRequest("POST", "/explain", bytes.NewReader(input)),
+		httptest.NewRequest("GET", "/explain?namespace=default&name=explain", nil),
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		resp := w.Result()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal("resp.StatusCode != http.StatusOK:", resp.StatusCode)
+		}
+
+		result := ExplainResult{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Requested["ssd"] != 3<<30 {
+			t.Errorf("wrong requested: %v", result.Requested)
+		}
+		if len(result.Nodes) != 2 {
+			t.Fatalf("wrong nodes: %#v", result.Nodes)
+		}
+		n1, n2 := result.Nodes[0], result.Nodes[1]
+		if n1.Node != "10.1.1.1" || n1.Reason != "out of VG free space" || n1.Score != 1 {
+			t.Errorf("wrong explanation of 10.1.1.1: %#v", n1)
+		}
+		if n2.Node != "10.1.1.2" || n2.Reason != "" || n2.Score != 2 {
+			t.Errorf("wrong explanation of 10.1.1.2: %#v", n2)
+		}
+		if len(n2.DeviceClasses) != 1 {
+			t.Fatalf("wrong device-classes of 10.1.1.2: %#v", n2.DeviceClasses)
+		}
+		dc := n2.DeviceClasses[0]
+		if dc.DeviceClass != "ssd" || dc.Requested != 3<<30 || dc.Capacity == nil || *dc.Capacity != 5<<30 ||
+			dc.Divisor != 1 || dc.Strategy != StrategyCapacity || dc.Score != 2 {
+			t.Errorf("wrong explanation of ssd on 10.1.1.2: %#v", dc)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/explain?namespace=default&name=notfound", nil))
+	if resp := w.Result(); resp.StatusCode != http.StatusNotFound {
+		t.Error("resp.StatusCode != http.StatusNotFound:", resp.StatusCode)
+	}
+}
+
 func TestRoute(t *testing.T) {
 	t.Run("predicate", testPredicate)
 	t.Run("prioritize", testPrioritize)
+	t.Run("explain", testExplain)
 }
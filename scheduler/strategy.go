@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+	"github.com/topolvm/topolvm/reservation"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StrategyType is the type of the scoring strategy.
+type StrategyType string
+
+const (
+	// StrategyCapacity scores nodes by the free capacity with this formula:
+	//
+	//	min(10, max(0, log2(capacity >> 30 / divisor)))
+	//
+	// This is the default strategy.
+	StrategyCapacity = StrategyType("capacity")
+
+	// StrategyLeastAllocated prefers nodes with less allocated space to spread volumes.
+	StrategyLeastAllocated = StrategyType("least-allocated")
+
+	// StrategyMostAllocated prefers nodes with more allocated space to pack volumes.
+	// This keeps whole nodes free for big volumes.
+	StrategyMostAllocated = StrategyType("most-allocated")
+
+	// StrategyBalanced prefers nodes where the allocation ratio of the device-class is
+	// close to the average of the device-classes requested by the pod.
+	StrategyBalanced = StrategyType("balanced")
+
+	// StrategyWeighted mixes the score of StrategyCapacity and the free data space of the thin pool.
+	StrategyWeighted = StrategyType("weighted")
+)
+
+const maxScore = 10
+
+// Strategy represents the scoring strategy of a device-class.
+type Strategy struct {
+	// Type is the type of the strategy.  The default is "capacity".
+	Type StrategyType `json:"type"`
+	// CapacityWeight is the weight of the capacity score for the weighted strategy.
+	CapacityWeight float64 `json:"capacity-weight"`
+	// ThinDataWeight is the weight of the thin pool data score for the weighted strategy.
+	ThinDataWeight float64 `json:"thin-data-weight"`
+}
+
+func (s Strategy) validate() error {
+	switch s.Type {
+	case "", StrategyCapacity, StrategyLeastAllocated, StrategyMostAllocated, StrategyBalanced:
+	case StrategyWeighted:
+		if s.CapacityWeight < 0 || s.ThinDataWeight < 0 {
+			return fmt.Errorf("invalid weights: capacity=%f, thin-data=%f", s.CapacityWeight, s.ThinDataWeight)
+		}
+	default:
+		return fmt.Errorf("unknown strategy: %s", s.Type)
+	}
+	return nil
+}
+
+// Scorer scores nodes with the divisors and the strategies of device-classes.
+type Scorer struct {
+	defaultDivisor  float64
+	divisors        map[string]float64
+	defaultStrategy Strategy
+	strategies      map[string]Strategy
+	thresholds      ThinPoolThresholds
+}
+
+// NewScorer returns Scorer.
+// The scores of thin device-classes are penalized as the thin pools approach the thresholds.
+func NewScorer(defaultDiv float64, divisors map[string]float64, defaultStrategy Strategy, strategies map[string]Strategy, thresholds ThinPoolThresholds) (Scorer, error) {
+	for _, divisor := range divisors {
+		if divisor <= 0 {
+			return Scorer{}, fmt.Errorf("invalid divisor: %f", divisor)
+		}
+	}
+	if err := defaultStrategy.validate(); err != nil {
+		return Scorer{}, err
+	}
+	for dc, strategy := range strategies {
+		if err := strategy.validate(); err != nil {
+			return Scorer{}, fmt.Errorf("invalid strategy for device-class %s: %w", dc, err)
+		}
+	}
+	if err := thresholds.validate(); err != nil {
+		return Scorer{}, err
+	}
+	return Scorer{
+		defaultDivisor:  defaultDiv,
+		divisors:        divisors,
+		defaultStrategy: defaultStrategy,
+		strategies:      strategies,
+		thresholds:      thresholds,
+	}, nil
+}
+
+func (s Scorer) divisor(dc string) float64 {
+	if v, ok := s.divisors[dc]; ok {
+		return v
+	}
+	return s.defaultDivisor
+}
+
+func (s Scorer) strategy(dc string) Strategy {
+	if v, ok := s.strategies[dc]; ok {
+		return v
+	}
+	return s.defaultStrategy
+}
+
+// deviceClassStats represents the state of a device-class on a node read from the annotations.
+type deviceClassStats struct {
+	// free is the free capacity minus the reserved space.
+	free uint64
+	// size is zero if unknown.
+	size uint64
+	// allocated is the ratio of the allocated space after the requested volume is created.
+	// It is meaningful only if size is not zero.
+	allocated float64
+	// dataPercent and metadataPercent are negative if unknown.
+	dataPercent     float64
+	metadataPercent float64
+}
+
+func getDeviceClassStats(node *corev1.Node, dc string, requested int64) (deviceClassStats, bool) {
+	val, ok := node.Annotations[topolvm.GetCapacityKeyPrefix()+dc]
+	if !ok {
+		return deviceClassStats{}, false
+	}
+	free, _ := strconv.ParseUint(val, 10, 64)
+	stats := deviceClassStats{
+		free: subtractReserved(free, reservation.FromAnnotation(node, dc)),
+	}
+	stats.dataPercent, stats.metadataPercent = getThinPoolPercents(node, dc)
+
+	if val, ok := node.Annotations[topolvm.GetSizeKeyPrefix()+dc]; ok {
+		stats.size, _ = strconv.ParseUint(val, 10, 64)
+	}
+	if stats.size != 0 {
+		freeAfter := float64(stats.free) - float64(requested)
+		if freeAfter < 0 {
+			freeAfter = 0
+		}
+		stats.allocated = clampRatio(1 - freeAfter/float64(stats.size))
+	}
+	return stats, true
+}
+
+func clampRatio(r float64) float64 {
+	switch {
+	case r < 0:
+		return 0
+	case r > 1:
+		return 1
+	default:
+		return r
+	}
+}
+
+func ratioToScore(r float64) int {
+	return int(math.Round(clampRatio(r) * maxScore))
+}
+
+// scoreDeviceClass returns the score of a device-class on a node.
+// meanAllocated is the average allocation ratio of the device-classes requested by the pod.
+func scoreDeviceClass(stats deviceClassStats, strategy Strategy, divisor, meanAllocated float64) int {
+	switch strategy.Type {
+	case StrategyLeastAllocated:
+		if stats.size == 0 {
+			return 0
+		}
+		return ratioToScore(1 - stats.allocated)
+	case StrategyMostAllocated:
+		if stats.size == 0 {
+			return 0
+		}
+		return ratioToScore(stats.allocated)
+	case StrategyBalanced:
+		if stats.size == 0 {
+			return 0
+		}
+		return ratioToScore(1 - math.Abs(stats.allocated-meanAllocated))
+	case StrategyWeighted:
+		capacityScore := float64(capacityToScore(stats.free, divisor))
+		if stats.dataPercent < 0 {
+			return int(capacityScore)
+		}
+		thinDataScore := (1 - clampRatio(stats.dataPercent/100)) * maxScore
+		cw, tw := strategy.CapacityWeight, strategy.ThinDataWeight
+		if cw == 0 && tw == 0 {
+			cw, tw = 1, 1
+		}
+		return int(math.Round((cw*capacityScore + tw*thinDataScore) / (cw + tw)))
+	default:
+		return capacityToScore(stats.free, divisor)
+	}
+}
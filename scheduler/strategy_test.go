@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testStatsNode returns a node with annotations of free GiB, size GiB and
+// thin pool data percent for each device-class.  Negative values are omitted.
+func testStatsNode(name string, stats map[string][3]float64) corev1.Node {
+	annotations := make(map[string]string)
+	for dc, s := range stats {
+		annotations[topolvm.GetCapacityKeyPrefix()+dc] = fmt.Sprintf("%d", int64(s[0])<<30)
+		if s[1] >= 0 {
+			annotations[topolvm.GetSizeKeyPrefix()+dc] = fmt.Sprintf("%d", int64(s[1])<<30)
+		}
+		if s[2] >= 0 {
+			annotations[topolvm.GetThinDataPercentKeyPrefix()+dc] = fmt.Sprintf("%g", s[2])
+		}
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+		},
+	}
+}
+
+func testStrategyPod(requestedGb map[string]int64) *corev1.Pod {
+	annotations := make(map[string]string)
+	for dc, r := range requestedGb {
+		annotations[topolvm.GetCapacityKeyPrefix()+dc] = fmt.Sprintf("%d", r<<30)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: annotations,
+		},
+	}
+}
+
+func TestScoreNodesWithStrategies(t *testing.T) {
+	singleNodes := []corev1.Node{
+		testStatsNode("free", map[string][3]float64{"ssd": {90, 100, 20}}),
+		testStatsNode("full", map[string][3]float64{"ssd": {30, 100, 80}}),
+		testStatsNode("nosize", map[string][3]float64{"ssd": {64, -1, -1}}),
+	}
+	multiNodes := []corev1.Node{
+		testStatsNode("balanced", map[string][3]float64{"ssd": {90, 100, -1}, "hdd": {90, 100, -1}}),
+		testStatsNode("unbalanced", map[string][3]float64{"ssd": {30, 100, -1}, "hdd": {90, 100, -1}}),
+	}
+
+	testCases := []struct {
+		name     string
+		pod      *corev1.Pod
+		nodes    []corev1.Node
+		strategy Strategy
+		expected []int
+	}{
+		{
+			name:     "capacity",
+			pod:      testStrategyPod(map[string]int64{"ssd": 10}),
+			nodes:    singleNodes,
+			strategy: Strategy{Type: StrategyCapacity},
+			expected: []int{6, 4, 6},
+		},
+		{
+			name:     "least-allocated",
+			pod:      testStrategyPod(map[string]int64{"ssd": 10}),
+			nodes:    singleNodes,
+			strategy: Strategy{Type: StrategyLeastAllocated},
+			expected: []int{8, 2, 0},
+		},
+		{
+			name:     "most-allocated",
+			pod:      testStrategyPod(map[string]int64{"ssd": 10}),
+			nodes:    singleNodes,
+			strategy: Strategy{Type: StrategyMostAllocated},
+			expected: []int{2, 8, 0},
+		},
+		{
+			name:     "weighted",
+			pod:      testStrategyPod(map[string]int64{"ssd": 10}),
+			nodes:    singleNodes,
+			strategy: Strategy{Type: StrategyWeighted},
+			expected: []int{7, 3, 6},
+		},
+		{
+			name:     "weighted with thin data weight",
+			pod:      testStrategyPod(map[string]int64{"ssd": 10}),
+			nodes:    singleNodes,
+			strategy: Strategy{Type: StrategyWeighted, CapacityWeight: 1, ThinDataWeight: 3},
+			expected: []int{8, 2, 6},
+		},
+		{
+			name:     "balanced",
+			pod:      testStrategyPod(map[string]int64{"ssd": 10, "hdd": 10}),
+			nodes:    multiNodes,
+			strategy: Strategy{Type: StrategyBalanced},
+			expected: []int{10, 7},
+		},
+	}
+
+	for _, tc := range testCases {
+		s := Scorer{defaultDivisor: 1, defaultStrategy: tc.strategy}
+		result := scoreNodes(tc.pod, tc.nodes, s)
+		scores := make([]int, len(result))
+		for i, r := range result {
+			scores[i] = r.Score
+		}
+		if !reflect.DeepEqual(scores, tc.expected) {
+			t.Errorf("%s: expected=%v actual=%v", tc.name, tc.expected, scores)
+		}
+
+		// scores must be deterministic.
+		for i := 0; i < 10; i++ {
+			if again := scoreNodes(tc.pod, tc.nodes, s); !reflect.DeepEqual(again, result) {
+				t.Errorf("%s: scores are not deterministic: %v, %v", tc.name, result, again)
+			}
+		}
+	}
+}
+
+func TestScoreNodesPerDeviceClassStrategy(t *testing.T) {
+	pod := testStrategyPod(map[string]int64{"ssd": 10, "hdd": 10})
+	nodes := []corev1.Node{
+		testStatsNode("10.1.1.1", map[string][3]float64{"ssd": {90, 100, -1}, "hdd": {1024, 2048, -1}}),
+	}
+	s := Scorer{
+		defaultDivisor:  1,
+		defaultStrategy: Strategy{Type: StrategyCapacity},
+		strategies: map[string]Strategy{
+			"ssd": {Type: StrategyMostAllocated},
+		},
+	}
+	// ssd: most-allocated => 2, hdd: log2(1024) => 10
+	result := scoreNodes(pod, nodes, s)
+	if len(result) != 1 || result[0].Score != 2 {
+		t.Errorf("unexpected result: %v", result)
+	}
+}
+
+func TestStrategyValidate(t *testing.T) {
+	testCases := []struct {
+		strategy Strategy
+		valid    bool
+	}{
+		{Strategy{}, true},
+		{Strategy{Type: StrategyMostAllocated}, true},
+		{Strategy{Type: StrategyWeighted, CapacityWeight: 1, ThinDataWeight: 2}, true},
+		{Strategy{Type: StrategyWeighted, CapacityWeight: -1}, false},
+		{Strategy{Type: "unknown"}, false},
+	}
+	for _, tc := range testCases {
+		err := tc.strategy.validate()
+		if tc.valid && err != nil {
+			t.Errorf("%v should be valid: %v", tc.strategy, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%v should be invalid", tc.strategy)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ThinPoolThresholds represents the limits of the physical usage of thin pools.
+// Overprovisioned free space does not tell how full a thin pool actually is,
+// so nodes whose thin pools are physically almost full are avoided with these limits.
+type ThinPoolThresholds struct {
+	// DataPercent is the limit of the data usage in percent.
+	// Nodes at or above the limit are filtered out.  Zero disables the limit.
+	DataPercent float64 `json:"data-percent"`
+	// MetadataPercent is the limit of the metadata usage in percent.
+	// Nodes at or above the limit are filtered out.  Zero disables the limit.
+	MetadataPercent float64 `json:"metadata-percent"`
+	// PenaltyMargin is the width in percent below the limits where the scores are penalized.
+	// The score decreases linearly to zero as the usage approaches the limit.
+	// Zero disables the penalty.
+	PenaltyMargin float64 `json:"penalty-margin"`
+}
+
+func (t ThinPoolThresholds) validate() error {
+	for _, v := range []float64{t.DataPercent, t.MetadataPercent, t.PenaltyMargin} {
+		if v < 0 || v > 100 {
+			return fmt.Errorf("invalid thin pool thresholds: %+v", t)
+		}
+	}
+	return nil
+}
+
+// getThinPoolPercents returns the data and metadata usage of the thin pool in percent.
+// Negative values are returned if unknown, e.g. the device-class is not a thin pool.
+func getThinPoolPercents(node *corev1.Node, dc string) (float64, float64) {
+	parse := func(key string) float64 {
+		val, ok := node.Annotations[key]
+		if !ok {
+			return -1
+		}
+		p, err := strconv.ParseFloat(val, 64)
+		if err != nil || p < 0 {
+			return -1
+		}
+		return p
+	}
+	return parse(topolvm.GetThinDataPercentKeyPrefix() + dc), parse(topolvm.GetThinMetadataPercentKeyPrefix() + dc)
+}
+
+// check returns the rejection if the thin pool exceeds the limits.
+// It returns an empty rejection if the thin pool is healthy.
+func (t ThinPoolThresholds) check(dataPercent, metadataPercent float64) rejection {
+	if t.DataPercent > 0 && dataPercent >= t.DataPercent {
+		return rejection{reasonThinPoolData, fmt.Sprintf("thin pool data usage %g%% exceeds the threshold %g%%", dataPercent, t.DataPercent)}
+	}
+	if t.MetadataPercent > 0 && metadataPercent >= t.MetadataPercent {
+		return rejection{reasonThinPoolMetadata, fmt.Sprintf("thin pool metadata usage %g%% exceeds the threshold %g%%", metadataPercent, t.MetadataPercent)}
+	}
+	return rejection{}
+}
+
+// penalize returns the score reduced by the usage approaching the limits.
+func (t ThinPoolThresholds) penalize(score int, dataPercent, metadataPercent float64) int {
+	if t.PenaltyMargin == 0 {
+		return score
+	}
+	factor := math.Min(t.penaltyFactor(t.DataPercent, dataPercent), t.penaltyFactor(t.MetadataPercent, metadataPercent))
+	return int(math.Round(float64(score) * factor))
+}
+
+func (t ThinPoolThresholds) penaltyFactor(limit, percent float64) float64 {
+	if limit == 0 || percent < 0 {
+		return 1
+	}
+	return clampRatio((limit - percent) / t.PenaltyMargin)
+}
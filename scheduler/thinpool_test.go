@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/topolvm/topolvm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func testThinPoolNode(name string, freeGb int64, dataPercent, metadataPercent float64) corev1.Node {
+	node := testStatsNode(name, map[string][3]float64{"thin": {float64(freeGb), -1, dataPercent}})
+	node.Annotations[topolvm.GetThinMetadataPercentKeyPrefix()+"thin"] = fmt.Sprintf("%g", metadataPercent)
+	return node
+}
+
+func TestFilterNodeWithThinPoolThresholds(t *testing.T) {
+	thresholds := ThinPoolThresholds{DataPercent: 90, MetadataPercent: 80}
+	requested := map[string]int64{"thin": 1 << 30}
+
+	testCases := []struct {
+		node      corev1.Node
+		requested map[string]int64
+		rejected  bool
+	}{
+		{testThinPoolNode("healthy", 100, 50, 50), requested, false},
+		{testThinPoolNode("data-full", 100, 97, 50), requested, true},
+		{testThinPoolNode("metadata-full", 100, 50, 80), requested, true},
+		{testNode("thick", 100, 100, 100), map[string]int64{"ssd": 1 << 30}, false},
+	}
+	for _, tc := range testCases {
+		reason := FilterNode(tc.node, tc.requested, thresholds)
+		if tc.rejected && reason == "" {
+			t.Errorf("%s should be rejected", tc.node.Name)
+		}
+		if !tc.rejected && reason != "" {
+			t.Errorf("%s should not be rejected: %s", tc.node.Name, reason)
+		}
+	}
+
+	// thresholds are disabled by default.
+	if reason := FilterNode(testThinPoolNode("data-full", 100, 97, 97), requested, ThinPoolThresholds{}); reason != "" {
+		t.Errorf("should not be rejected without thresholds: %s", reason)
+	}
+}
+
+func TestScoreNodesWithThinPoolThresholds(t *testing.T) {
+	pod := testStrategyPod(map[string]int64{"thin": 1})
+	nodes := []corev1.Node{
+		testThinPoolNode("healthy", 1024, 50, 50),
+		testThinPoolNode("approaching", 1024, 85, 50),
+		testThinPoolNode("metadata-approaching", 1024, 50, 88),
+	}
+	s := Scorer{
+		defaultDivisor: 1,
+		thresholds:     ThinPoolThresholds{DataPercent: 90, MetadataPercent: 90, PenaltyMargin: 10},
+	}
+
+	// log2(1024) = 10 reduced by (90-85)/10 and (90-88)/10.
+	expected := []int{10, 5, 2}
+	result := scoreNodes(pod, nodes, s)
+	for i, r := range result {
+		if r.Score != expected[i] {
+			t.Errorf("%s: expected=%d actual=%d", r.Host, expected[i], r.Score)
+		}
+	}
+}
+
+func TestThinPoolThresholdsValidate(t *testing.T) {
+	testCases := []struct {
+		thresholds ThinPoolThresholds
+		valid      bool
+	}{
+		{ThinPoolThresholds{}, true},
+		{ThinPoolThresholds{DataPercent: 95, MetadataPercent: 90, PenaltyMargin: 10}, true},
+		{ThinPoolThresholds{DataPercent: -1}, false},
+		{ThinPoolThresholds{MetadataPercent: 101}, false},
+	}
+	for _, tc := range testCases {
+		err := tc.thresholds.validate()
+		if tc.valid && err != nil {
+			t.Errorf("%+v should be valid: %v", tc.thresholds, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%+v should be invalid", tc.thresholds)
+		}
+	}
+}